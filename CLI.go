@@ -2,51 +2,518 @@ package poker
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// CLI runs games at the terminal until quit or the end of the input.
 type CLI struct {
-	in   *bufio.Scanner
-	out  io.Writer
-	game Game
+	in    *bufio.Scanner
+	out   io.Writer
+	game  Game
+	games *GameManager
+	store PlayerStore
+
+	current     *ManagedGame
+	unsubscribe func()
+	keeping     bool
+
+	terminal *Terminal
+	said     *heldWriter
+	output   Output
+	locale   *Locale
 }
 
-const PlayerPrompt = "Please enter the number of players: "
+const (
+	Prompt        = "> "
+	PlayerPrompt  = "Please enter the number of players: "
+	ResumePrompt  = "Resume the unfinished game (%v)? y to carry on, n to throw it away: "
+	ConfirmPrompt = "Record %s as the winner? y to save it, anything else to carry on playing: "
+	SuggestPrompt = "Nobody called %s is playing, did you mean %s? y/n: "
+)
+
+// CLICommandsHelp lists what can be typed at the prompt.
+const CLICommandsHelp = `start <players>  start a game, with a number of players or their names
+{Name} wins      finish the game and record the winner, or {Name} 1st
+{Name} out       knock a player out of a tournament
+pause, resume    stop and restart the clock
+skip, back       move the clock to the next or previous level
+undo             take back the last thing typed in the game
+screen           put the clock on the whole screen
+league           show the league
+score <name>     show a player's wins
+help             show this again
+quit             stop playing`
+
+var (
+	ErrNoGame      = errors.New("there is no game running, type start <players> to begin")
+	ErrGameRunning = errors.New("a game is already running, type {Name} wins to finish it first")
+	ErrNoLeague    = errors.New("there is no league kept here")
+)
+
+// UseStore sets where players are looked up, and games checkpointed if it can.
+func (cli *CLI) UseStore(store PlayerStore) {
+	cli.store = store
+	if checkpoints, ok := store.(CheckpointStore); ok {
+		cli.games.KeepCheckpoints(checkpoints)
+		cli.keeping = true
+	}
+}
+
+// UseTerminal lets the clock go full screen on term.
+func (cli *CLI) UseTerminal(term *Terminal) {
+	cli.terminal = term
+}
+
+// UseLocale sets the language the CLI speaks; commands stay English.
+func (cli *CLI) UseLocale(locale *Locale) {
+	cli.locale = locale
+}
+
+// UseOutput sets how the CLI prints; OutputJSON leaves out the prompt.
+func (cli *CLI) UseOutput(output Output) {
+	cli.output = output
+	if output == OutputJSON {
+		cli.said.out = &jsonLines{out: cli.out}
+	}
+}
 
+// PlayPoker reads commands until quit, saving a game still running if it can.
 func (cli *CLI) PlayPoker() {
-	fmt.Fprint(cli.out, PlayerPrompt)
+	if game := cli.resume(); game != nil {
+		cli.watch(game)
+	}
+
+	for {
+		if cli.output != OutputJSON {
+			fmt.Fprint(cli.out, Prompt)
+		}
+		input, ok := cli.readLine()
+		if !ok {
+			break
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+
+		quit, err := cli.command(input)
+		if err != nil {
+			cli.fail(err)
+		}
+		if quit {
+			break
+		}
+	}
+
+	cli.stop()
+}
+
+// command runs one line and reports whether it was quit.
+func (cli *CLI) command(input string) (bool, error) {
+	verb, argument, _ := strings.Cut(input, " ")
+	argument = strings.TrimSpace(argument)
+
+	switch strings.ToLower(verb) {
+	case "quit", "exit":
+		return true, nil
+	case "help":
+		help := cli.locale.Lines(CLICommandsHelp)
+		cli.print(help, messageEvent{Event: "message", Message: help})
+		return false, nil
+	case "league":
+		return false, cli.league()
+	case "score":
+		return false, cli.score(argument)
+	case "start":
+		return false, cli.start(argument)
+	case "undo":
+		return false, cli.undo()
+	case "screen":
+		return false, cli.screen()
+	}
+
+	if cli.current == nil {
+		// a bare number starts a game, as it always has
+		if _, err := strconv.Atoi(input); err == nil {
+			return false, cli.start(input)
+		}
+		if isClockCommand(input) {
+			return false, ErrNoGame
+		}
+		return false, cli.locale.Errorf("%q is not a command, type help to see what you can do", input)
+	}
+
+	return false, cli.play(input)
+}
+
+// start starts a game for a number of players, or for named ones.
+func (cli *CLI) start(players string) error {
+	if cli.current != nil {
+		return ErrGameRunning
+	}
+
+	if _, err := strconv.Atoi(players); err != nil && players != "" {
+		names := ParseNames(players)
+		if len(names) < 2 {
+			return cli.locale.Errorf("%q is not a number of players or a list of names, type start <players>", players)
+		}
+		return cli.startNamed(names)
+	}
 
-	numberOfPlayersInput := cli.readLine()
-	numberOfPlayers, err := strconv.Atoi(strings.Trim(numberOfPlayersInput, "\n"))
+	for players == "" {
+		input, ok := cli.ask("players", cli.locale.Text(PlayerPrompt))
+		if !ok {
+			return nil
+		}
+		players = strings.TrimSpace(input)
+		if _, err := strconv.Atoi(players); err != nil && players != "" {
+			cli.fail(cli.locale.Errorf("%q is not a number of players", players))
+			players = ""
+		}
+	}
 
+	numberOfPlayers, err := strconv.Atoi(players)
 	if err != nil {
+		return cli.locale.Errorf("%q is not a number of players, type start <players>", players)
+	}
+	if numberOfPlayers < 1 {
+		return cli.locale.Errorf("a game needs at least one player, not %d", numberOfPlayers)
+	}
+
+	game := cli.games.Create(cli.game, numberOfPlayers, "")
+	cli.watch(game)
+	return game.Start()
+}
+
+func (cli *CLI) startNamed(names []string) error {
+	game := cli.games.Create(cli.game, len(names), "")
+	for _, name := range names {
+		if err := game.Join(name); err != nil {
+			game.Abandon()
+			return err
+		}
+	}
+	cli.watch(game)
+	return game.Start()
+}
+
+// play passes input to the game, finishing it on a result.
+func (cli *CLI) play(input string) error {
+	if !isCashGame(cli.game) {
+		result, err := ParseResult(input)
+		if err == nil {
+			return cli.result(result)
+		}
+		if !errors.Is(err, ErrNotAResult) {
+			return err
+		}
+	}
+
+	handled, err := cli.current.Play(input)
+	if handled {
+		return err
+	}
+	if strings.EqualFold(input, "end") && isCashGame(cli.game) {
+		// the books balance, so the session is over
+		if err := cli.current.End(""); err != nil {
+			return err
+		}
+		cli.unwatch()
+		return nil
+	}
+	return cli.locale.Errorf("%q is not a command, type {Name} wins to finish the game or help to see what you can do", input)
+}
+
+// result knocks a player out, or finishes the game once confirmed.
+func (cli *CLI) result(result ResultLine) error {
+	player, err := cli.checkPlayer(result.Player)
+	if err != nil || player == "" {
+		return err
+	}
+
+	if result.Out {
+		handled, err := cli.current.Play(ResultLine{Player: player, Out: true}.String())
+		if !handled {
+			return cli.locale.Errorf("nobody goes out in this game, type {Name} wins when it is over")
+		}
+		return err
+	}
+
+	if !cli.confirm("winner", cli.locale.Sprintf(ConfirmPrompt, player)) {
+		cli.say("Not saved, carry on playing")
+		return nil
+	}
+	if err := cli.current.End(player); err != nil {
+		return err
+	}
+	cli.unwatch()
+	if cli.output == OutputJSON {
+		// people saw their answer go in, a script needs telling
+		cli.event(winnerEvent{Event: "winner", Player: player})
+	}
+	return nil
+}
+
+// checkPlayer returns who a result is for, or "" if a suggestion is turned down.
+func (cli *CLI) checkPlayer(name string) (string, error) {
+	seated := cli.seated()
+	if len(seated) == 0 {
+		return name, nil
+	}
+
+	player, suggestions := MatchPlayer(name, seated)
+	switch {
+	case player != "":
+		return player, nil
+	case len(suggestions) == 1:
+		if cli.confirm("suggestion", cli.locale.Sprintf(SuggestPrompt, name, suggestions[0])) {
+			return suggestions[0], nil
+		}
+		return "", nil
+	case len(suggestions) > 1:
+		return "", cli.locale.Errorf("nobody called %s is playing, did you mean %s?", name, strings.Join(suggestions, " or "))
+	}
+	return "", cli.locale.Errorf("nobody called %s is playing, the players are %s", name, strings.Join(seated, ", "))
+}
+
+// seated is everyone named at the start or in the seat draw.
+func (cli *CLI) seated() []string {
+	players := cli.current.Info().Players
+
+	if tournament, ok := cli.game.(TournamentGame); ok && tournament.Tournament() != nil {
+		if seating := tournament.Tournament().Seating(); seating != nil {
+			for _, seat := range seating.Draw {
+				if !slices.Contains(players, seat.Player) {
+					players = append(players, seat.Player)
+				}
+			}
+		}
+	}
+	return players
+}
+
+// confirm asks question and reports whether the answer was y.
+func (cli *CLI) confirm(question, prompt string) bool {
+	answer, _ := cli.ask(question, prompt)
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
+
+// ask prints prompt, or a prompt event for question, and reads the answer.
+func (cli *CLI) ask(question, prompt string) (string, bool) {
+	if cli.output == OutputJSON {
+		cli.event(promptEvent{Event: "prompt", Question: question, Prompt: prompt})
+	} else {
+		fmt.Fprint(cli.out, prompt)
+	}
+	return cli.readLine()
+}
+
+func (cli *CLI) undo() error {
+	if cli.current == nil {
+		return ErrNoGame
+	}
+
+	undone, err := cli.current.Undo()
+	if err != nil {
+		return err
+	}
+	cli.print(cli.locale.Sprintf("Took back %q", undone), undoneEvent{Event: "undone", Input: undone})
+	return nil
+}
+
+// screen shows the clock until q is pressed, or prints it once without a terminal.
+func (cli *CLI) screen() error {
+	if cli.current == nil {
+		return ErrNoGame
+	}
+	view, err := cli.current.ClockView()
+	if err != nil {
+		return err
+	}
+	if cli.terminal == nil || cli.output == OutputJSON {
+		view.Locale = cli.locale
+		cli.print(strings.TrimSuffix(view.String(), "\n"), view.event())
+		return nil
+	}
+
+	cli.said.hold()
+	defer cli.said.release()
+	return ShowClock(cli.terminal, cli.current, cli.store, cli.said.last, cli.locale)
+}
+
+func (cli *CLI) league() error {
+	if cli.store == nil {
+		return ErrNoLeague
+	}
+
+	league := cli.store.GetLeague()
+	if cli.output == OutputJSON {
+		if league == nil {
+			league = League{}
+		}
+		return cli.event(leagueEvent{Event: "league", League: league})
+	}
+	return WriteLeague(cli.out, league, cli.output, cli.locale)
+}
+
+func (cli *CLI) score(name string) error {
+	if cli.store == nil {
+		return ErrNoLeague
+	}
+	if name == "" {
+		return cli.locale.Errorf("whose score? type score <name>")
+	}
+
+	wins := cli.store.GetPlayerScore(name)
+	cli.print(cli.locale.Sprintf("%s has %s", name, cli.locale.Plural(wins, "%s win", "%s wins")), scoreEvent{Event: "score", Player: name, Wins: wins})
+	return nil
+}
+
+// watch makes game the one being played and prints what it says.
+func (cli *CLI) watch(game *ManagedGame) {
+	cli.current = game
+	cli.unsubscribe = game.Subscribe(cli.said)
+}
+
+func (cli *CLI) unwatch() {
+	if cli.unsubscribe != nil {
+		cli.unsubscribe()
+	}
+	cli.current, cli.unsubscribe = nil, nil
+}
+
+// stop puts away the game still running when the session ends.
+func (cli *CLI) stop() {
+	if cli.current == nil {
+		return
+	}
+
+	if _, ok := cli.game.(ResumableGame); ok && cli.keeping {
+		cli.current.Suspend()
+		cli.say("The game is saved, you will be offered it next time")
+	} else {
+		cli.current.Abandon()
+	}
+	cli.unwatch()
+}
+
+// resume offers each unfinished game, discarding those turned down.
+func (cli *CLI) resume() *ManagedGame {
+	if _, ok := cli.game.(ResumableGame); !ok {
+		return nil
+	}
+
+	for _, checkpoint := range cli.games.Checkpoints() {
+		if !cli.confirm("resume", cli.locale.Sprintf(ResumePrompt, checkpoint)) {
+			if err := cli.games.Discard(checkpoint.ID); err != nil {
+				cli.fail(err)
+			}
+			continue
+		}
+
+		game, err := cli.games.Resume(checkpoint, cli.game)
+		if err != nil {
+			cli.fail(err)
+			continue
+		}
+		return game
+	}
+	return nil
+}
+
+// say prints message in the CLI's language, as a line or a message event.
+func (cli *CLI) say(message string) {
+	message = cli.locale.Text(message)
+	cli.print(message, messageEvent{Event: "message", Message: message})
+}
+
+func (cli *CLI) fail(err error) {
+	message := cli.locale.Error(err)
+	cli.print(message, errorEvent{Event: "error", Error: message})
+}
+
+// print writes text as a line, or event as a line of JSON for a script.
+func (cli *CLI) print(text string, event any) {
+	if cli.output == OutputJSON {
+		cli.event(event)
 		return
 	}
+	fmt.Fprintln(cli.out, text)
+}
+
+func (cli *CLI) event(event any) error {
+	return encodeJSON(cli.out, event)
+}
+
+// heldWriter holds the game's output while the clock is on screen.
+type heldWriter struct {
+	mu   sync.Mutex
+	out  io.Writer
+	held bool
+	kept bytes.Buffer
+}
+
+func (w *heldWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.held {
+		return w.kept.Write(p)
+	}
+	return w.out.Write(p)
+}
+
+func (w *heldWriter) hold() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.held = true
+}
+
+func (w *heldWriter) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	cli.game.Start(numberOfPlayers)
+	w.held = false
+	w.out.Write(w.kept.Bytes())
+	w.kept.Reset()
+}
 
-	winnerInput := cli.readLine()
-	winner := extractWinner(winnerInput)
+// last is the last line kept.
+func (w *heldWriter) last() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	cli.game.Finish(winner)
+	lines := strings.Split(strings.TrimSpace(w.kept.String()), "\n")
+	return lines[len(lines)-1]
 }
 
-func extractWinner(userInput string) string {
-	return strings.Replace(userInput, " wins\n", "", 1)
+func isCashGame(game Game) bool {
+	_, ok := game.(*CashGame)
+	return ok
 }
 
-func (cli *CLI) readLine() string {
-	cli.in.Scan()
-	return cli.in.Text()
+// readLine reads the next line, reporting false at the end of the input.
+func (cli *CLI) readLine() (string, bool) {
+	if !cli.in.Scan() {
+		return "", false
+	}
+	return cli.in.Text(), true
 }
 
 func NewCLI(in io.Reader, out io.Writer, game Game) *CLI {
 	return &CLI{
-		in:   bufio.NewScanner(in),
-		out:  out,
-		game: game,
+		in:     bufio.NewScanner(in),
+		out:    out,
+		game:   game,
+		games:  NewGameManager(RealClock{}),
+		said:   &heldWriter{out: out},
+		locale: English,
 	}
 }
@@ -3,7 +3,10 @@ package poker_test
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,120 +21,262 @@ type SpyBlindAlerter struct {
 	alerts []ScheduledAlert
 }
 
-func (s *SpyBlindAlerter) ScheduleAlertAt(At time.Duration, Amount int) {
-	s.alerts = append(s.alerts, ScheduledAlert{At, Amount})
+func (s *SpyBlindAlerter) ScheduleAlertAt(At time.Duration, alert poker.BlindAlert, to io.Writer) poker.Timer {
+	s.alerts = append(s.alerts, ScheduledAlert{At, alert.Level.BigBlind})
+	return &StubTimer{}
 }
 
-func (s ScheduledAlert) String() string {
-	return fmt.Sprintf("%d chips At %v", s.Amount, s.At)
+type StubTimer struct {
+	Stopped bool
 }
 
-type GameSpy struct {
-	StartedWith  int
-	FinishedWith string
-	StartCalled  bool
+func (s *StubTimer) Stop() bool {
+	s.Stopped = true
+	return true
 }
 
-func (g *GameSpy) Start(numberOfPlayers int) {
-	g.StartedWith = numberOfPlayers
-}
-func (g *GameSpy) Finish(winner string) {
-	g.FinishedWith = winner
+func (s ScheduledAlert) String() string {
+	return fmt.Sprintf("%d chips At %v", s.Amount, s.At)
 }
 
 var DummySpyAlerter = &SpyBlindAlerter{}
 var DummyPlayerStore = &poker.StubPlayerStore{}
 var DummyStdIn = &bytes.Buffer{}
 var DummyStdOut = &bytes.Buffer{}
+var DummyClock = poker.NewManualClock(time.Time{})
+
+var unfinishedGame = poker.Checkpoint{
+	ID:      "1",
+	Players: 4,
+	Variant: "holdem",
+	Blinds: poker.BlindStructure{Levels: []poker.BlindLevel{
+		{SmallBlind: 50, BigBlind: 100, Duration: 10 * time.Minute},
+		{SmallBlind: 100, BigBlind: 200, Duration: 10 * time.Minute},
+	}},
+	Level:     1,
+	Remaining: 3 * time.Minute,
+}
+
+// syncBuffer lets alerts fired by the test write to the same output the CLI
+// goroutine writes to.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
 
 func TestCLI(t *testing.T) {
 	t.Run("record chris win from user input,", func(t *testing.T) {
 
-		in := strings.NewReader("Chris wins\n")
-		playerStore := &poker.StubPlayerStore{}
-		game := &GameSpy{}
+		in := strings.NewReader("5\nChris wins\ny\n")
+		game := &poker.GameSpy{}
 
 		cli := poker.NewCLI(in, DummyStdOut, game)
 		cli.PlayPoker()
 		winner := "Chris"
 
-		poker.AssertPlayerWin(t, playerStore, winner)
+		assertFinishCalledWith(t, game, winner)
 
 	})
 	t.Run("record cleo win from user input, ", func(t *testing.T) {
-		in := strings.NewReader("Cleo wins\n")
-		playerStore := &poker.StubPlayerStore{}
-		game := &GameSpy{}
+		in := strings.NewReader("5\nCleo wins\ny\n")
+		game := &poker.GameSpy{}
 
 		cli := poker.NewCLI(in, DummyStdOut, game)
 		cli.PlayPoker()
 
-		poker.AssertPlayerWin(t, playerStore, "Cleo")
-	})
-	// t.Run("it schedules printing of blind values", func(t *testing.T) {
-	// 	in := strings.NewReader("Chris wins\n")
-	// 	blindAlerter := &SpyBlindAlerter{}
-
-	// 	stdout := &bytes.Buffer{}
-	// 	game := &GameSpy{}
-	// 	cli := poker.NewCLI(in, stdout, game)
-	// 	cli.PlayPoker()
-
-	// 	cases := []ScheduledAlert{
-	// 		{0 * time.Second, 100},
-	// 		{10 * time.Minute, 200},
-	// 		{20 * time.Minute, 300},
-	// 		{30 * time.Minute, 400},
-	// 		{40 * time.Minute, 500},
-	// 		{50 * time.Minute, 600},
-	// 		{60 * time.Minute, 800},
-	// 		{70 * time.Minute, 1000},
-	// 		{80 * time.Minute, 2000},
-	// 		{90 * time.Minute, 4000},
-	// 		{100 * time.Minute, 8000},
-	// 	}
-
-	// 	for i, want := range cases {
-	// 		t.Run(fmt.Sprint(want), func(t *testing.T) {
-
-	// 			if len(blindAlerter.alerts) <= i {
-	// 				t.Fatalf("alert %d was not scheduled %v", i, blindAlerter.alerts)
-	// 			}
-
-	// 			got := blindAlerter.alerts[i]
-	// 			assertScheduledAlert(t, got, want)
-	// 		})
-	// 	}
-
-	// })
-	t.Run("it prompts the user to enter the number of players", func(t *testing.T) {
+		assertFinishCalledWith(t, game, "Cleo")
+	})
+	t.Run("it offers to carry on an unfinished game", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		store.SaveCheckpoint(unfinishedGame)
 		stdout := &bytes.Buffer{}
+		clock := poker.NewManualClock(time.Time{})
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, clock)
+
+		cli := poker.NewCLI(strings.NewReader("y\nChris wins\ny\n"), stdout, game)
+		cli.UseStore(store)
+		cli.PlayPoker()
+
+		if !strings.HasPrefix(stdout.String(), fmt.Sprintf(poker.ResumePrompt, unfinishedGame)) {
+			t.Errorf("expected to be offered the unfinished game, got %q", stdout.String())
+		}
+		if !strings.Contains(stdout.String(), "Resuming Texas Hold'em for 4 players") {
+			t.Errorf("expected the game to resume, got %q", stdout.String())
+		}
+		if got := game.Clock().State(); got.Level != 1 || got.Remaining != 3*time.Minute {
+			t.Errorf("got the clock at %+v", got)
+		}
+		poker.AssertPlayerWin(t, store, "Chris")
+		if len(store.Checkpoints()) != 0 {
+			t.Error("expected the finished game to be forgotten")
+		}
+	})
+	t.Run("unfinished games turned down are thrown away", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		store.SaveCheckpoint(unfinishedGame)
+		stdout := &bytes.Buffer{}
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+
+		cli := poker.NewCLI(strings.NewReader("n\n5\nChris wins\ny\n"), stdout, game)
+		cli.UseStore(store)
+		cli.PlayPoker()
+
+		poker.AssertPlayerWin(t, store, "Chris")
+		if len(store.Checkpoints()) != 0 {
+			t.Errorf("got checkpoints %v", store.Checkpoints())
+		}
+	})
+	t.Run("it schedules printing of blind values", func(t *testing.T) {
+		in := strings.NewReader("5\nChris wins\ny\n")
 		blindAlerter := &SpyBlindAlerter{}
-		in := strings.NewReader("7\n")
-		game := &GameSpy{}
+		game := poker.NewTexasHoldem(DummyPlayerStore, blindAlerter, DummyClock)
+
+		cli := poker.NewCLI(in, DummyStdOut, game)
+		cli.PlayPoker()
+
+		cases := []ScheduledAlert{
+			{0 * time.Second, 100},
+			{10 * time.Minute, 200},
+			{20 * time.Minute, 300},
+			{30 * time.Minute, 400},
+			{40 * time.Minute, 500},
+			{50 * time.Minute, 600},
+			{60 * time.Minute, 800},
+			{70 * time.Minute, 1000},
+			{80 * time.Minute, 2000},
+			{90 * time.Minute, 4000},
+			{100 * time.Minute, 8000},
+		}
+
+		checkSchedulingCases(cases, t, blindAlerter)
+	})
+
+	t.Run("blind alerts are printed as the clock moves on", func(t *testing.T) {
+		in, userInput := io.Pipe()
+		stdout := &syncBuffer{}
+		clock := poker.NewManualClock(time.Time{})
+		game := poker.NewTexasHoldem(&poker.StubPlayerStore{}, poker.NewAlerter(clock), clock)
+
+		cli := poker.NewCLI(in, stdout, game)
+		done := make(chan struct{})
+		go func() {
+			cli.PlayPoker()
+			close(done)
+		}()
+
+		fmt.Fprintln(userInput, "5")
+		for clock.Pending() < 11 {
+			time.Sleep(time.Millisecond)
+		}
+
+		clock.Advance(25 * time.Minute)
+		fmt.Fprintln(userInput, "Chris wins")
+		fmt.Fprintln(userInput, "y")
+		fmt.Fprintln(userInput, "quit")
+		<-done
+		clock.Advance(time.Hour)
+
+		want := "Blinds are now 50/100, next 100/200 in 10m0s\n" +
+			"Blinds are now 100/200, next 150/300 in 10m0s\n" +
+			"Blinds are now 150/300, next 200/400 in 10m0s\n"
+
+		got := strings.ReplaceAll(stdout.String(), poker.Prompt, "")
+		got = strings.ReplaceAll(got, fmt.Sprintf(poker.ConfirmPrompt, "Chris"), "")
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("it prompts the user to enter the number of players", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		in := strings.NewReader("start\n7\n")
+		game := &poker.GameSpy{}
 		cli := poker.NewCLI(in, stdout, game)
 		cli.PlayPoker()
 
 		got := stdout.String()
-		want := "Please enter the number of players: "
+		want := poker.Prompt + poker.PlayerPrompt + poker.Prompt
 
 		if got != want {
 			t.Errorf("got %s want %s", got, want)
 		}
-		cases := []ScheduledAlert{
-			{0 * time.Second, 100},
-			{12 * time.Minute, 200},
-			{24 * time.Minute, 300},
-			{36 * time.Minute, 400},
+
+		if game.StartedWith != 7 {
+			t.Errorf("wanted Start called with 7 but got %d", game.StartedWith)
 		}
-		checkSchedulingCases(cases, t, blindAlerter)
+	})
+
+	t.Run("it sends blind alerts to the CLI output", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		in := strings.NewReader("7\n")
+		game := &poker.GameSpy{BlindAlert: []byte("Blinds are now 50/100\n")}
+		cli := poker.NewCLI(in, stdout, game)
+		cli.PlayPoker()
 
+		want := poker.Prompt + "Blinds are now 50/100\n" + poker.Prompt
+		if got := stdout.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("clock commands control the game clock rather than finish the game", func(t *testing.T) {
+		in := strings.NewReader("5\npause\nChris wins\ny\n")
+		game := poker.NewTexasHoldem(&poker.StubPlayerStore{}, &SpyBlindAlerter{}, DummyClock)
+
+		cli := poker.NewCLI(in, &bytes.Buffer{}, game)
+		cli.PlayPoker()
+
+		if !game.Clock().State().Paused {
+			t.Error("expected the clock to have been paused")
+		}
+	})
+
+	t.Run("clock commands report when there is no clock", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		in := strings.NewReader("5\nskip\nChris wins\ny\n")
+		game := &poker.GameSpy{}
+
+		cli := poker.NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		if !strings.Contains(stdout.String(), "no clock running") {
+			t.Errorf("expected an error about the clock but got %q", stdout.String())
+		}
+		assertFinishCalledWith(t, game, "Chris")
+	})
+
+	t.Run("moves are played at the table rather than finishing the game", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		in := strings.NewReader("3\ncheck\nfold\nChris wins\ny\n")
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, DummyClock)
+		game.DealCards(1000, 1)
+
+		cli := poker.NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		if !strings.Contains(stdout.String(), "cannot check") || !strings.Contains(stdout.String(), "Player 1 folds") {
+			t.Errorf("expected the moves to be played but got %q", stdout.String())
+		}
+		poker.AssertPlayerWin(t, store, "Chris")
 	})
 
 	t.Run("it prints an error when a non numeric value is entered and does not start the game", func(t *testing.T) {
 		stdout := &bytes.Buffer{}
 		in := strings.NewReader("Pies\n")
-		game := &GameSpy{}
+		game := &poker.GameSpy{}
 
 		cli := poker.NewCLI(in, stdout, game)
 		cli.PlayPoker()
@@ -139,7 +284,172 @@ func TestCLI(t *testing.T) {
 		if game.StartCalled {
 			t.Errorf("game should not have started")
 		}
+		assertOutputContains(t, stdout, `"Pies" is not a command`)
 	})
+
+	t.Run("it asks again until it gets a number of players", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		in := strings.NewReader("start\nPies\n4\nChris wins\ny\n")
+		game := &poker.GameSpy{}
+
+		cli := poker.NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		if strings.Count(stdout.String(), poker.PlayerPrompt) != 2 {
+			t.Errorf("expected to be asked twice, got %q", stdout.String())
+		}
+		assertOutputContains(t, stdout, `"Pies" is not a number of players`)
+		if game.StartedWith != 4 {
+			t.Errorf("wanted Start called with 4 but got %d", game.StartedWith)
+		}
+	})
+
+	t.Run("several games are played in one session", func(t *testing.T) {
+		store := newFileStore(t)
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+
+		cli := poker.NewCLI(strings.NewReader("start 5\nChris wins\ny\nstart 3\nCleo wins\ny\nquit\nstart 4\n"), &bytes.Buffer{}, game)
+		cli.UseStore(store)
+		cli.PlayPoker()
+
+		if store.GetPlayerScore("Chris") != 1 || store.GetPlayerScore("Cleo") != 1 {
+			t.Errorf("got league %v", store.GetLeague())
+		}
+	})
+
+	t.Run("the league and scores can be looked up", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		store := newFileStore(t)
+		for _, winner := range []string{"Chris", "Cleo", "Chris", "Chris"} {
+			store.RecordWin(winner)
+		}
+
+		cli := poker.NewCLI(strings.NewReader("league\nscore Cleo\nscore\n"), stdout, &poker.GameSpy{})
+		cli.UseStore(store)
+		cli.PlayPoker()
+
+		for _, want := range []string{"1. Chris: 3 wins\n", "2. Cleo: 1 win\n", "Cleo has 1 win\n", "whose score?"} {
+			assertOutputContains(t, stdout, want)
+		}
+	})
+
+	t.Run("it explains what cannot be done", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		game := &poker.GameSpy{}
+
+		cli := poker.NewCLI(strings.NewReader("pause\nundo\nleague\n5\nstart 6\nChris\nChris wins\ny\n"), stdout, game)
+		cli.PlayPoker()
+
+		for _, want := range []error{poker.ErrNoGame, poker.ErrNoLeague, poker.ErrGameRunning} {
+			assertOutputContains(t, stdout, want.Error())
+		}
+		assertOutputContains(t, stdout, `"Chris" is not a command`)
+		assertFinishCalledWith(t, game, "Chris")
+	})
+
+	t.Run("undo takes back the last command", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+		game.PlayTournament(poker.TournamentRules{BuyIn: 10})
+
+		cli := poker.NewCLI(strings.NewReader("start 4\nDan out\nundo\nundo\nCleo out\n"), stdout, game)
+		cli.PlayPoker()
+
+		assertOutputContains(t, stdout, `Took back "Dan out"`)
+		assertOutputContains(t, stdout, poker.ErrNothingToUndo.Error())
+		if got := game.Tournament().Remaining(); got != 3 {
+			t.Errorf("got %d players left, want 3", got)
+		}
+	})
+
+	t.Run("a tournament is not won while others are still in", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+		game.PlayTournament(poker.TournamentRules{BuyIn: 10})
+
+		cli := poker.NewCLI(strings.NewReader("start 3\nDan out\nChris wins\ny\nCleo out\nChris wins\ny\n"), stdout, game)
+		cli.PlayPoker()
+
+		assertOutputContains(t, stdout, "Chris cannot win while others are still in, 2 players are left")
+		poker.AssertPlayerWin(t, store, "Chris")
+		assertOutputContains(t, stdout, "1st Chris wins 30")
+	})
+
+	t.Run("results are checked against the players named at the start", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		game := &poker.GameSpy{}
+		in := strings.NewReader("start Chris, Cleo, Mary Ann\nBob wins\nwins\nChirs wins\ny\nn\n\"mary ann\" 1st\ny\n")
+
+		cli := poker.NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertOutputContains(t, stdout, "nobody called Bob is playing, the players are Chris, Cleo, Mary Ann")
+		assertOutputContains(t, stdout, "who? type {Name} wins")
+		assertOutputContains(t, stdout, fmt.Sprintf(poker.SuggestPrompt, "Chirs", "Chris"))
+		assertOutputContains(t, stdout, "Not saved")
+		assertFinishCalledWith(t, game, "Mary Ann")
+	})
+
+	t.Run("only tournaments knock players out", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		game := &poker.GameSpy{}
+
+		cli := poker.NewCLI(strings.NewReader("5\nDan out\n"), stdout, game)
+		cli.PlayPoker()
+
+		assertOutputContains(t, stdout, "nobody goes out in this game")
+		if game.FinishCalled {
+			t.Error("expected the game to carry on")
+		}
+	})
+
+	t.Run("without a terminal the clock screen is printed", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		game := poker.NewTexasHoldem(&poker.StubPlayerStore{}, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+
+		cli := poker.NewCLI(strings.NewReader("screen\nstart 6\nscreen\n"), stdout, game)
+		cli.PlayPoker()
+
+		assertOutputContains(t, stdout, poker.ErrNoGame.Error())
+		assertOutputContains(t, stdout, "Texas Hold'em, level 1: 50/100\n11:00 left\n")
+		assertOutputContains(t, stdout, "6 players\n")
+	})
+
+	t.Run("quitting part way through keeps the game for next time", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		store := newFileStore(t)
+		game := poker.NewTexasHoldem(store, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+
+		cli := poker.NewCLI(strings.NewReader("start 5\nquit\n"), stdout, game)
+		cli.UseStore(store)
+		cli.PlayPoker()
+
+		if got := store.Checkpoints(); len(got) != 1 || got[0].Players != 5 {
+			t.Errorf("got checkpoints %+v", got)
+		}
+		assertOutputContains(t, stdout, "The game is saved")
+	})
+}
+
+func newFileStore(t testing.TB) *poker.FileSystemPlayerStore {
+	t.Helper()
+
+	store, close, err := poker.FileSystemPlayerStoreFromFile(filepath.Join(t.TempDir(), "game.db.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(close)
+	return store
+}
+
+func assertOutputContains(t testing.TB, out fmt.Stringer, want string) {
+	t.Helper()
+
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("expected %q in %q", want, out.String())
+	}
 }
 func assertScheduledAlert(t testing.TB, got, want ScheduledAlert) {
 	t.Helper()
@@ -155,12 +465,20 @@ func assertScheduledAlert(t testing.TB, got, want ScheduledAlert) {
 	}
 }
 
+func assertFinishCalledWith(t testing.TB, game *poker.GameSpy, winner string) {
+	t.Helper()
+
+	if game.FinishedWith != winner {
+		t.Errorf("expected finish called with %q but got %q", winner, game.FinishedWith)
+	}
+}
+
 func TestTexasHoldem_Start(t *testing.T) {
 	t.Run("schedules alerts on game start for 5 players", func(t *testing.T) {
 		blindAlerter := &SpyBlindAlerter{}
-		game := poker.NewTexasHoldem(DummyPlayerStore, blindAlerter)
+		game := poker.NewTexasHoldem(DummyPlayerStore, blindAlerter, DummyClock)
 
-		game.Start(5)
+		game.Start(5, io.Discard)
 
 		cases := []ScheduledAlert{
 			{At: 0 * time.Second, Amount: 100},
@@ -181,9 +499,9 @@ func TestTexasHoldem_Start(t *testing.T) {
 
 	t.Run("schedules alerts on game start for 7 players", func(t *testing.T) {
 		blindAlerter := &SpyBlindAlerter{}
-		game := poker.NewTexasHoldem(DummyPlayerStore, blindAlerter)
+		game := poker.NewTexasHoldem(DummyPlayerStore, blindAlerter, DummyClock)
 
-		game.Start(7)
+		game.Start(7, io.Discard)
 
 		cases := []ScheduledAlert{
 			{At: 0 * time.Second, Amount: 100},
@@ -197,13 +515,198 @@ func TestTexasHoldem_Start(t *testing.T) {
 
 }
 
+func TestTexasHoldem_Alerts(t *testing.T) {
+	t.Run("alerts fire in order as time passes", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		clock := poker.NewManualClock(time.Time{})
+		game := poker.NewTexasHoldem(DummyPlayerStore, poker.NewAlerter(clock), clock)
+
+		game.Start(5, out)
+
+		steps := []struct {
+			advance time.Duration
+			want    string
+		}{
+			{0, "Blinds are now 50/100, next 100/200 in 10m0s\n"},
+			{9 * time.Minute, ""},
+			{time.Minute, "Blinds are now 100/200, next 150/300 in 10m0s\n"},
+			{20 * time.Minute, "Blinds are now 150/300, next 200/400 in 10m0s\nBlinds are now 200/400, next 250/500 in 10m0s\n"},
+		}
+
+		for _, step := range steps {
+			clock.Advance(step.advance)
+
+			if got := out.String(); got != step.want {
+				t.Fatalf("after %v got %q want %q", step.advance, got, step.want)
+			}
+			out.Reset()
+		}
+	})
+
+	t.Run("paused games fire nothing until resumed", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		clock := poker.NewManualClock(time.Time{})
+		game := poker.NewTexasHoldem(DummyPlayerStore, poker.NewAlerter(clock), clock)
+
+		game.Start(5, out)
+		clock.Advance(4 * time.Minute)
+		game.Clock().Pause()
+		out.Reset()
+
+		clock.Advance(time.Hour)
+		if out.Len() != 0 {
+			t.Fatalf("expected no alerts while paused but got %q", out.String())
+		}
+
+		game.Clock().Resume()
+		clock.Advance(6 * time.Minute)
+
+		want := "Blinds are now 50/100, next 100/200 in 6m0s\nBlinds are now 100/200, next 150/300 in 10m0s\n"
+		if got := out.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
+
 func TestTexasHoldem_Finish(t *testing.T) {
-	store := &poker.StubPlayerStore{}
-	game := poker.NewTexasHoldem(store, DummySpyAlerter)
-	winner := "Ruth"
+	t.Run("records the winner", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(store, DummySpyAlerter, DummyClock)
+		winner := "Ruth"
+
+		game.Finish(winner)
+		poker.AssertPlayerWin(t, store, winner)
+	})
+
+	t.Run("cancels the alerts that have not fired yet", func(t *testing.T) {
+		var timers []*StubTimer
+		alerter := poker.BlindAlerterFunc(func(time.Duration, poker.BlindAlert, io.Writer) poker.Timer {
+			timer := &StubTimer{}
+			timers = append(timers, timer)
+			return timer
+		})
+		game := poker.NewTexasHoldem(&poker.StubPlayerStore{}, alerter, DummyClock)
+
+		game.Start(5, io.Discard)
+		game.Finish("Ruth")
+
+		for i, timer := range timers {
+			if !timer.Stopped {
+				t.Errorf("alert %d was not cancelled", i)
+			}
+		}
+	})
+}
+
+func TestTexasHoldem_Deal(t *testing.T) {
+	newDealingGame := func(out io.Writer) *poker.TexasHoldem {
+		game := poker.NewTexasHoldem(&poker.StubPlayerStore{}, &SpyBlindAlerter{}, poker.NewManualClock(time.Time{}))
+		game.DealCards(1000, 1)
+		game.Start(3, out)
+		return game
+	}
+
+	t.Run("starting deals the first hand at the current blinds", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		game := newDealingGame(out)
+
+		hand := game.Table().Hand
+		if hand == nil || hand.Blinds.BigBlind != 100 {
+			t.Fatalf("expected a hand at 50/100 but got %+v", hand)
+		}
+		if !strings.Contains(out.String(), "Player 1 to act") {
+			t.Errorf("expected Player 1 to be asked to act but got %q", out.String())
+		}
+	})
+
+	t.Run("moves are played by whoever is to act", func(t *testing.T) {
+		game := newDealingGame(io.Discard)
+
+		for _, move := range []string{"raise 300", "fold", "fold"} {
+			handled, err := game.Play(move)
+			if !handled || err != nil {
+				t.Fatalf("%q: got handled %v, error %v", move, handled, err)
+			}
+		}
+
+		if got := game.Table().Seats[0].Stack; got != 1150 {
+			t.Errorf("got %d chips for Player 1, want 1150", got)
+		}
+	})
+
+	t.Run("a new hand can only be dealt once the last one is over", func(t *testing.T) {
+		game := newDealingGame(io.Discard)
+
+		if _, err := game.Play("deal"); err == nil {
+			t.Error("expected an error dealing over a hand in progress")
+		}
+	})
+
+	t.Run("showdowns are settled by the best hand", func(t *testing.T) {
+		game := newDealingGame(io.Discard)
 
-	game.Finish(winner)
-	poker.AssertPlayerWin(t, store, winner)
+		for _, move := range []string{"call", "call", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check"} {
+			if _, err := game.Play(move); err != nil {
+				t.Fatalf("%q: %v", move, err)
+			}
+		}
+
+		if !game.Table().Hand.Finished() {
+			t.Error("expected the hand to be over")
+		}
+		if _, err := game.Play("deal"); err != nil {
+			t.Errorf("expected the next hand to be dealt but got %v", err)
+		}
+	})
+
+	t.Run("anything else is left for the game", func(t *testing.T) {
+		game := newDealingGame(io.Discard)
+
+		if handled, _ := game.Play("Chris wins"); handled {
+			t.Error("expected a result to be left alone")
+		}
+	})
+}
+
+func TestAlerter(t *testing.T) {
+	t.Run("alerts announce the next level", func(t *testing.T) {
+		alert := poker.BlindAlert{
+			Level:  poker.BlindLevel{SmallBlind: 50, BigBlind: 100, Duration: 10 * time.Minute},
+			Next:   poker.BlindLevel{SmallBlind: 100, BigBlind: 200, Ante: 25, Duration: 10 * time.Minute},
+			NextIn: 10 * time.Minute,
+		}
+
+		got := alert.String()
+		want := "Blinds are now 50/100, next 100/200 ante 25 in 10m0s"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("breaks announce when play resumes", func(t *testing.T) {
+		alert := poker.BlindAlert{
+			Level:  poker.BlindLevel{Break: true, Duration: 5 * time.Minute},
+			Next:   poker.BlindLevel{SmallBlind: 300, BigBlind: 600, Duration: 10 * time.Minute},
+			NextIn: 5 * time.Minute,
+		}
+
+		got := alert.String()
+		want := "Break time, next 300/600 in 5m0s"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("the final level has nothing after it", func(t *testing.T) {
+		got := poker.BlindAlert{Level: poker.BlindLevel{SmallBlind: 4000, BigBlind: 8000}}.String()
+		want := "Blinds are now 4000/8000"
+
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
 }
 
 func checkSchedulingCases(cases []ScheduledAlert, t *testing.T, blindAlerter *SpyBlindAlerter) {
@@ -2,22 +2,78 @@ package poker
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"time"
 )
 
+// BlindAlert announces a level; Next is zero after the final level.
+type BlindAlert struct {
+	Level  BlindLevel
+	Next   BlindLevel
+	NextIn time.Duration
+}
+
+func (b BlindAlert) HasNext() bool {
+	return b.Next != BlindLevel{}
+}
+
+func (b BlindAlert) String() string {
+	if b.Level.Break {
+		return "Break time" + b.upcoming()
+	}
+	return fmt.Sprintf("Blinds are now %v%s", b.Level, b.upcoming())
+}
+
+func (b BlindAlert) upcoming() string {
+	if !b.HasNext() {
+		return ""
+	}
+	return fmt.Sprintf(", next %v in %v", b.Next, b.NextIn)
+}
+
+// Timer is a scheduled alert that can be cancelled before it fires.
+type Timer interface {
+	Stop() bool
+}
+
 type BlindAlerter interface {
-	ScheduleAlertAt(duration time.Duration, amount int)
+	ScheduleAlertAt(duration time.Duration, alert BlindAlert, to io.Writer) Timer
 }
 
-type BlindAlerterFunc func(duration time.Duration, amount int)
+type BlindAlerterFunc func(duration time.Duration, alert BlindAlert, to io.Writer) Timer
 
-func (a BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, amount int) {
-	a(duration, amount)
+func (a BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, alert BlindAlert, to io.Writer) Timer {
+	return a(duration, alert, to)
 }
 
-func StdOutAlerter(duration time.Duration, amount int) {
-	time.AfterFunc(duration, func() {
-		fmt.Fprintf(os.Stdout, "Blind is now %d\n", amount)
+// NewAlerter writes each alert when the clock reaches it.
+func NewAlerter(clock Clock) BlindAlerter {
+	return BlindAlerterFunc(func(duration time.Duration, alert BlindAlert, to io.Writer) Timer {
+		return clock.AfterFunc(duration, func() {
+			fmt.Fprintln(to, alert)
+		})
 	})
 }
+
+// NewLocalisedAlerter is NewAlerter speaking locale, for people rather than the web page.
+func NewLocalisedAlerter(clock Clock, locale *Locale) BlindAlerter {
+	return BlindAlerterFunc(func(duration time.Duration, alert BlindAlert, to io.Writer) Timer {
+		return clock.AfterFunc(duration, func() {
+			fmt.Fprintln(to, locale.Alert(alert))
+		})
+	})
+}
+
+// NewJSONAlerter writes each alert as a blinds event.
+func NewJSONAlerter(clock Clock) BlindAlerter {
+	return BlindAlerterFunc(func(duration time.Duration, alert BlindAlert, to io.Writer) Timer {
+		return clock.AfterFunc(duration, func() {
+			encodeJSON(to, alert.event())
+		})
+	})
+}
+
+// Alerter writes the alert to the given destination once duration has passed.
+func Alerter(duration time.Duration, alert BlindAlert, to io.Writer) Timer {
+	return NewAlerter(RealClock{}).ScheduleAlertAt(duration, alert, to)
+}
@@ -0,0 +1,218 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlindDesign describes the night a blind structure should be designed for.
+type BlindDesign struct {
+	Players       int
+	StartingStack int
+	Duration      time.Duration
+	Chips         []int
+}
+
+const (
+	// startingBigBlinds is how deep everyone starts: a 10,000 stack opens at 50/100.
+	startingBigBlinds = 100
+	// finalBigBlinds is how many big blinds are in play when the time is up.
+	finalBigBlinds = 20
+	// playBetweenBreaks is the least play between breaks.
+	playBetweenBreaks = 90 * time.Minute
+	breakLength       = 10 * time.Minute
+)
+
+var levelLengths = []time.Duration{
+	10 * time.Minute, 12 * time.Minute, 15 * time.Minute, 20 * time.Minute,
+	25 * time.Minute, 30 * time.Minute, 40 * time.Minute, 45 * time.Minute, 60 * time.Minute,
+}
+
+// niceBlinds are the big blinds people are used to seeing, for each power of ten.
+var niceBlinds = []float64{1, 1.2, 1.5, 2, 2.5, 3, 4, 5, 6, 8}
+
+func (d BlindDesign) validate() error {
+	if d.Players < 2 {
+		return fmt.Errorf("need at least 2 players, got %d", d.Players)
+	}
+	if d.StartingStack <= 0 {
+		return fmt.Errorf("starting stack must be positive, got %d", d.StartingStack)
+	}
+	if d.Duration < 30*time.Minute {
+		return fmt.Errorf("a tournament needs at least 30m, got %v", d.Duration)
+	}
+	if len(d.Chips) == 0 {
+		return errors.New("need at least one chip denomination")
+	}
+	for _, chip := range d.Chips {
+		if chip <= 0 {
+			return fmt.Errorf("chip denominations must be positive, got %d", chip)
+		}
+	}
+	if d.Chips[0] > d.StartingStack/startingBigBlinds {
+		return fmt.Errorf("the smallest chip (%d) is too big for a starting stack of %d", d.Chips[0], d.StartingStack)
+	}
+	var unusable []string
+	for _, chip := range d.Chips {
+		if !makesSmallBlind(chip) {
+			unusable = append(unusable, strconv.Itoa(chip))
+		}
+	}
+	if len(unusable) > 0 {
+		return fmt.Errorf("chips of %s cannot make a small blind, which go 25, 50, 100, 200 and so on", strings.Join(unusable, ", "))
+	}
+	return nil
+}
+
+// makesSmallBlind reports whether a usual small blind is a multiple of chip.
+func makesSmallBlind(chip int) bool {
+	for exponent := 0; exponent < 10; exponent++ {
+		for _, nice := range niceBlinds {
+			bigBlind := int(math.Round(nice * math.Pow10(exponent)))
+			if bigBlind%2 == 0 && (bigBlind/2)%chip == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DesignBlindStructure grows the blinds from 100 big blinds deep to 20 in play over design.Duration.
+func DesignBlindStructure(design BlindDesign) (BlindStructure, error) {
+	design.Chips = append([]int(nil), design.Chips...)
+	sort.Ints(design.Chips)
+
+	if err := design.validate(); err != nil {
+		return BlindStructure{}, err
+	}
+
+	levelLength := chooseLevelLength(design.Duration)
+	// a break is due once playBetweenBreaks has been played, not before
+	levelsBetweenBreaks := int(math.Ceil(float64(playBetweenBreaks) / float64(levelLength)))
+
+	// breaks eat into the time for play, so take them out before counting levels.
+	playingLevels := int(design.Duration / levelLength)
+	for playingLevels > 2 {
+		breaks := (playingLevels - 1) / levelsBetweenBreaks
+		if time.Duration(playingLevels)*levelLength+time.Duration(breaks)*breakLength <= design.Duration {
+			break
+		}
+		playingLevels--
+	}
+
+	startBigBlind := float64(design.StartingStack) / startingBigBlinds
+	finalBigBlind := float64(design.StartingStack*design.Players) / finalBigBlinds
+	growth := math.Pow(finalBigBlind/startBigBlind, 1/float64(max(playingLevels-1, 1)))
+
+	var levels []BlindLevel
+	previous := 0
+	for i := 0; i < playingLevels; i++ {
+		target := startBigBlind * math.Pow(growth, float64(i))
+		bigBlind := roundBigBlind(target, previous, design.Chips)
+		if bigBlind == 0 {
+			return BlindStructure{}, fmt.Errorf("no big blind after %d can be paid in the chips %v", previous, design.Chips)
+		}
+
+		level := BlindLevel{SmallBlind: bigBlind / 2, BigBlind: bigBlind, Duration: levelLength}
+		if i >= playingLevels/3 {
+			level.Ante = roundToChip(float64(bigBlind)/8, design.Chips)
+		}
+
+		if i > 0 && i%levelsBetweenBreaks == 0 {
+			levels = append(levels, BlindLevel{Break: true, Duration: breakLength})
+		}
+		levels = append(levels, level)
+		previous = bigBlind
+	}
+
+	structure := BlindStructure{
+		Name:   fmt.Sprintf("%d players, %d chips, %v", design.Players, design.StartingStack, design.Duration),
+		Levels: levels,
+	}
+	return structure, structure.Validate()
+}
+
+// chooseLevelLength picks the usual level length that gives about a dozen levels.
+func chooseLevelLength(duration time.Duration) time.Duration {
+	ideal := duration / 12
+
+	best := levelLengths[0]
+	for _, length := range levelLengths {
+		if absDuration(length-ideal) < absDuration(best-ideal) {
+			best = length
+		}
+	}
+	return best
+}
+
+// roundBigBlind finds the nice big blind nearest target above previous, or 0.
+func roundBigBlind(target float64, previous int, chips []int) int {
+	best := 0
+	bestDistance := math.Inf(1)
+
+	for exponent := 0; exponent < 10; exponent++ {
+		for _, nice := range niceBlinds {
+			bigBlind := int(math.Round(nice * math.Pow10(exponent)))
+			if bigBlind <= previous || !payable(bigBlind, chips) {
+				continue
+			}
+
+			distance := math.Abs(math.Log(float64(bigBlind) / target))
+			if distance < bestDistance {
+				best, bestDistance = bigBlind, distance
+			}
+		}
+	}
+	return best
+}
+
+// payable reports whether both blinds are multiples of the chip worth using.
+func payable(bigBlind int, chips []int) bool {
+	if bigBlind%2 != 0 {
+		return false
+	}
+
+	chip := workingChip(bigBlind/2, chips)
+	return bigBlind/2 >= chips[0] && (bigBlind/2)%chip == 0
+}
+
+func workingChip(smallBlind int, chips []int) int {
+	chip := chips[0]
+	for _, c := range chips {
+		if c*5 <= smallBlind {
+			chip = c
+		}
+	}
+	return chip
+}
+
+// roundToChip rounds amount to the chip worth using, at least the smallest.
+func roundToChip(amount float64, chips []int) int {
+	chip := workingChip(int(amount), chips)
+	return max(int(math.Round(amount/float64(chip)))*chip, chips[0])
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// ParseChips reads chip denominations written like "25,100,500".
+func ParseChips(input string) ([]int, error) {
+	var chips []int
+	for _, field := range strings.Split(input, ",") {
+		chip, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a chip denomination", field)
+		}
+		chips = append(chips, chip)
+	}
+	return chips, nil
+}
@@ -0,0 +1,170 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDesignBlindStructure(t *testing.T) {
+	designs := []BlindDesign{
+		{Players: 8, StartingStack: 10000, Duration: 4 * time.Hour, Chips: []int{25, 100, 500, 1000}},
+		{Players: 4, StartingStack: 1500, Duration: time.Hour, Chips: []int{5, 25, 100}},
+		{Players: 10, StartingStack: 20000, Duration: 6 * time.Hour, Chips: []int{1000, 25, 500, 100, 5000}},
+		{Players: 2, StartingStack: 1000, Duration: 30 * time.Minute, Chips: []int{5}},
+		{Players: 9, StartingStack: 50000, Duration: 12 * time.Hour, Chips: []int{100, 500, 1000}},
+	}
+
+	for _, design := range designs {
+		structure, err := DesignBlindStructure(design)
+		assertNoError(t, err)
+
+		t.Run(structure.Name, func(t *testing.T) {
+			t.Run("fits in the time asked for", func(t *testing.T) {
+				var total, longest time.Duration
+				for _, level := range structure.Levels {
+					total += level.Duration
+					longest = max(longest, level.Duration)
+				}
+
+				if total > design.Duration || total <= design.Duration-longest-breakLength {
+					t.Errorf("levels add up to %v for a %v tournament", total, design.Duration)
+				}
+			})
+
+			t.Run("starts 100 big blinds deep and ends with 20 in play", func(t *testing.T) {
+				levels := playingLevels(structure)
+
+				assertAbout(t, levels[0].BigBlind, design.StartingStack/startingBigBlinds)
+				assertAbout(t, levels[len(levels)-1].BigBlind, design.Players*design.StartingStack/finalBigBlinds)
+			})
+
+			t.Run("breaks come after at least 90 minutes of play", func(t *testing.T) {
+				var played time.Duration
+				for _, level := range structure.Levels {
+					if !level.Break {
+						played += level.Duration
+						continue
+					}
+					if played < playBetweenBreaks {
+						t.Errorf("a break after only %v of play", played)
+					}
+					played = 0
+				}
+			})
+
+			t.Run("uses chip friendly blinds that always go up", func(t *testing.T) {
+				smallest := design.Chips[0]
+				for _, chip := range design.Chips {
+					smallest = min(smallest, chip)
+				}
+
+				previous := 0
+				for _, level := range playingLevels(structure) {
+					if level.BigBlind <= previous {
+						t.Errorf("big blind %d does not go up from %d", level.BigBlind, previous)
+					}
+					if level.SmallBlind*2 != level.BigBlind {
+						t.Errorf("small blind %d is not half of %d", level.SmallBlind, level.BigBlind)
+					}
+					if level.SmallBlind%smallest != 0 || level.Ante%smallest != 0 {
+						t.Errorf("%v cannot be paid in chips of %d", level, smallest)
+					}
+					previous = level.BigBlind
+				}
+			})
+		})
+	}
+
+	t.Run("rounds blinds to values people know", func(t *testing.T) {
+		structure, err := DesignBlindStructure(designs[0])
+		assertNoError(t, err)
+
+		var got []int
+		for _, level := range playingLevels(structure) {
+			got = append(got, level.BigBlind)
+		}
+
+		want := []int{100, 150, 200, 300, 400, 600, 1000, 1200, 2000, 3000, 4000}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got big blinds %v want %v", got, want)
+		}
+	})
+
+	t.Run("the design can be saved and loaded back", func(t *testing.T) {
+		structure, err := DesignBlindStructure(designs[0])
+		assertNoError(t, err)
+
+		saved, err := json.Marshal(structure)
+		assertNoError(t, err)
+
+		loaded, err := NewBlindStructure(bytes.NewReader(saved))
+		assertNoError(t, err)
+
+		if !reflect.DeepEqual(loaded, structure) {
+			t.Errorf("got %v back, want %v", loaded, structure)
+		}
+	})
+
+	t.Run("rejects designs that cannot work", func(t *testing.T) {
+		cases := map[string]BlindDesign{
+			"one player":    {Players: 1, StartingStack: 1000, Duration: time.Hour, Chips: []int{5}},
+			"no chips":      {Players: 6, StartingStack: 1000, Duration: time.Hour},
+			"no stack":      {Players: 6, Duration: time.Hour, Chips: []int{5}},
+			"too short":     {Players: 6, StartingStack: 1000, Duration: 10 * time.Minute, Chips: []int{5}},
+			"chips too big": {Players: 6, StartingStack: 1000, Duration: time.Hour, Chips: []int{100}},
+			"negative chip": {Players: 6, StartingStack: 1000, Duration: time.Hour, Chips: []int{-5}},
+		}
+
+		for name, design := range cases {
+			t.Run(name, func(t *testing.T) {
+				if _, err := DesignBlindStructure(design); err == nil {
+					t.Error("expected an error but got none")
+				}
+			})
+		}
+	})
+
+	t.Run("says which chips cannot make a small blind", func(t *testing.T) {
+		design := BlindDesign{Players: 6, StartingStack: 1000, Duration: time.Hour, Chips: []int{5, 7}}
+		_, err := DesignBlindStructure(design)
+		if err == nil || !strings.Contains(err.Error(), "chips of 7 cannot make a small blind") {
+			t.Errorf("got %v, want the 7 chip named", err)
+		}
+	})
+}
+
+func TestParseChips(t *testing.T) {
+	chips, err := ParseChips("25, 100,500")
+	assertNoError(t, err)
+
+	if !reflect.DeepEqual(chips, []int{25, 100, 500}) {
+		t.Errorf("got %v", chips)
+	}
+
+	if _, err := ParseChips("25,red"); err == nil {
+		t.Error("expected an error for a chip that is not a number")
+	}
+}
+
+// assertAbout allows for blinds being rounded to something payable.
+func assertAbout(t testing.TB, got, want int) {
+	t.Helper()
+
+	if float64(got) < float64(want)/1.5 || float64(got) > float64(want)*1.5 {
+		t.Errorf("got big blind %d, want about %d", got, want)
+	}
+}
+
+func playingLevels(structure BlindStructure) []BlindLevel {
+	var levels []BlindLevel
+	for _, level := range structure.Levels {
+		if !level.Break {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
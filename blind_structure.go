@@ -0,0 +1,210 @@
+package poker
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed blinds/*.json
+var builtInBlinds embed.FS
+
+// BlindLevel is one step of the schedule; a break only has a Duration.
+type BlindLevel struct {
+	SmallBlind int           `json:"small_blind,omitempty" yaml:"small_blind,omitempty"`
+	BigBlind   int           `json:"big_blind,omitempty" yaml:"big_blind,omitempty"`
+	Ante       int           `json:"ante,omitempty" yaml:"ante,omitempty"`
+	Duration   time.Duration `json:"duration" yaml:"duration"`
+	Break      bool          `json:"break,omitempty" yaml:"break,omitempty"`
+}
+
+func (l BlindLevel) String() string {
+	if l.Break {
+		return "break"
+	}
+	if l.Ante > 0 {
+		return fmt.Sprintf("%d/%d ante %d", l.SmallBlind, l.BigBlind, l.Ante)
+	}
+	return fmt.Sprintf("%d/%d", l.SmallBlind, l.BigBlind)
+}
+
+// blindLevelJSON spells the duration out as "20m" rather than nanoseconds.
+type blindLevelJSON struct {
+	SmallBlind int    `json:"small_blind,omitempty"`
+	BigBlind   int    `json:"big_blind,omitempty"`
+	Ante       int    `json:"ante,omitempty"`
+	Duration   string `json:"duration"`
+	Break      bool   `json:"break,omitempty"`
+}
+
+func (l BlindLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blindLevelJSON{l.SmallBlind, l.BigBlind, l.Ante, l.Duration.String(), l.Break})
+}
+
+func (l *BlindLevel) UnmarshalJSON(data []byte) error {
+	var raw blindLevelJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(raw.Duration)
+	if err != nil {
+		return fmt.Errorf("problem parsing level duration %q %v", raw.Duration, err)
+	}
+
+	*l = BlindLevel{raw.SmallBlind, raw.BigBlind, raw.Ante, duration, raw.Break}
+	return nil
+}
+
+// BlindStructure is a named blind schedule.
+type BlindStructure struct {
+	Name   string       `json:"name" yaml:"name"`
+	Levels []BlindLevel `json:"levels" yaml:"levels"`
+}
+
+// Validate checks every level has a duration and the blinds never go down.
+func (s BlindStructure) Validate() error {
+	if len(s.Levels) == 0 {
+		return errors.New("blind structure has no levels")
+	}
+	if s.Levels[0].Break {
+		return errors.New("blind structure cannot start with a break")
+	}
+
+	previousBigBlind := 0
+	for i, level := range s.Levels {
+		number := i + 1
+
+		if level.Duration <= 0 {
+			return fmt.Errorf("level %d: duration must be positive, got %v", number, level.Duration)
+		}
+
+		if level.Break {
+			if level.SmallBlind != 0 || level.BigBlind != 0 || level.Ante != 0 {
+				return fmt.Errorf("level %d: a break cannot have blinds or an ante", number)
+			}
+			continue
+		}
+
+		if level.SmallBlind <= 0 || level.BigBlind <= 0 {
+			return fmt.Errorf("level %d: blinds must be positive, got %v", number, level)
+		}
+		if level.SmallBlind > level.BigBlind {
+			return fmt.Errorf("level %d: small blind %d is bigger than the big blind %d", number, level.SmallBlind, level.BigBlind)
+		}
+		if level.Ante < 0 {
+			return fmt.Errorf("level %d: ante cannot be negative, got %d", number, level.Ante)
+		}
+		if level.BigBlind < previousBigBlind {
+			return fmt.Errorf("level %d: big blind %d is lower than the level before it (%d)", number, level.BigBlind, previousBigBlind)
+		}
+
+		previousBigBlind = level.BigBlind
+	}
+
+	return nil
+}
+
+// BlindsGame is a Game that can be played with a chosen blind structure.
+type BlindsGame interface {
+	Game
+	UseBlinds(BlindStructure)
+}
+
+// DefaultBlindStructure is the house schedule: blinds go up every 5+N minutes.
+func DefaultBlindStructure(numberOfPlayers int) BlindStructure {
+	blindIncrement := time.Duration(5+numberOfPlayers) * time.Minute
+	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
+
+	levels := make([]BlindLevel, len(blinds))
+	for i, blind := range blinds {
+		levels[i] = BlindLevel{SmallBlind: blind / 2, BigBlind: blind, Duration: blindIncrement}
+	}
+
+	return BlindStructure{Name: "default", Levels: levels}
+}
+
+// NewBlindStructure reads a JSON blind structure and validates it.
+func NewBlindStructure(rdr io.Reader) (BlindStructure, error) {
+	var structure BlindStructure
+	if err := json.NewDecoder(rdr).Decode(&structure); err != nil {
+		return structure, fmt.Errorf("problem parsing blind structure %v", err)
+	}
+	return structure, structure.Validate()
+}
+
+// NewBlindStructureFromYAML reads a YAML blind structure and validates it.
+func NewBlindStructureFromYAML(rdr io.Reader) (BlindStructure, error) {
+	var structure BlindStructure
+	if err := yaml.NewDecoder(rdr).Decode(&structure); err != nil {
+		return structure, fmt.Errorf("problem parsing blind structure %v", err)
+	}
+	return structure, structure.Validate()
+}
+
+// LoadBlindStructure returns a built in structure by name, or reads a YAML or JSON file.
+func LoadBlindStructure(nameOrPath string) (BlindStructure, error) {
+	if structure, ok, err := BuiltInBlindStructure(nameOrPath); ok {
+		return structure, err
+	}
+
+	file, err := os.Open(nameOrPath)
+	if err != nil {
+		return BlindStructure{}, fmt.Errorf("%q is not a built in blind structure (%s) or a readable file: %v",
+			nameOrPath, strings.Join(BlindStructureNames(), ", "), err)
+	}
+	defer file.Close()
+
+	var structure BlindStructure
+	switch strings.ToLower(filepath.Ext(nameOrPath)) {
+	case ".yaml", ".yml":
+		structure, err = NewBlindStructureFromYAML(file)
+	default:
+		structure, err = NewBlindStructure(file)
+	}
+
+	if err != nil {
+		return structure, fmt.Errorf("problem loading blind structure from %s: %v", nameOrPath, err)
+	}
+	if structure.Name == "" {
+		structure.Name = strings.TrimSuffix(filepath.Base(nameOrPath), filepath.Ext(nameOrPath))
+	}
+	return structure, nil
+}
+
+// BuiltInBlindStructure looks up a structure shipped with the package.
+func BuiltInBlindStructure(name string) (structure BlindStructure, ok bool, err error) {
+	file, err := builtInBlinds.Open(path.Join("blinds", name+".json"))
+	if err != nil {
+		return BlindStructure{}, false, nil
+	}
+	defer file.Close()
+
+	structure, err = NewBlindStructure(file)
+	if err != nil {
+		return structure, true, fmt.Errorf("built in blind structure %s is invalid: %v", name, err)
+	}
+	return structure, true, nil
+}
+
+// BlindStructureNames lists the built in structures.
+func BlindStructureNames() []string {
+	entries, _ := builtInBlinds.ReadDir("blinds")
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
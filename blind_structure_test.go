@@ -0,0 +1,149 @@
+package poker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlindStructure(t *testing.T) {
+	t.Run("reads levels and breaks from JSON", func(t *testing.T) {
+		structure, err := NewBlindStructure(strings.NewReader(`{
+			"name": "quick",
+			"levels": [
+				{"small_blind": 25, "big_blind": 50, "duration": "15m"},
+				{"break": true, "duration": "5m"},
+				{"small_blind": 50, "big_blind": 100, "ante": 10, "duration": "15m"}
+			]}`))
+
+		assertNoError(t, err)
+		assertBlindLevels(t, structure.Levels, []BlindLevel{
+			{SmallBlind: 25, BigBlind: 50, Duration: 15 * time.Minute},
+			{Break: true, Duration: 5 * time.Minute},
+			{SmallBlind: 50, BigBlind: 100, Ante: 10, Duration: 15 * time.Minute},
+		})
+	})
+
+	t.Run("reads levels and breaks from YAML", func(t *testing.T) {
+		structure, err := NewBlindStructureFromYAML(strings.NewReader(`
+name: quick
+levels:
+  - small_blind: 25
+    big_blind: 50
+    duration: 15m
+  - break: true
+    duration: 5m
+  - small_blind: 50
+    big_blind: 100
+    ante: 10
+    duration: 15m
+`))
+
+		assertNoError(t, err)
+		assertBlindLevels(t, structure.Levels, []BlindLevel{
+			{SmallBlind: 25, BigBlind: 50, Duration: 15 * time.Minute},
+			{Break: true, Duration: 5 * time.Minute},
+			{SmallBlind: 50, BigBlind: 100, Ante: 10, Duration: 15 * time.Minute},
+		})
+	})
+
+	t.Run("writes durations the way they are read", func(t *testing.T) {
+		level := BlindLevel{SmallBlind: 25, BigBlind: 50, Duration: 15 * time.Minute}
+
+		got, err := level.MarshalJSON()
+		assertNoError(t, err)
+
+		want := `{"small_blind":25,"big_blind":50,"duration":"15m0s"}`
+		if string(got) != want {
+			t.Errorf("got %s want %s", got, want)
+		}
+	})
+
+	t.Run("rejects structures that cannot be played", func(t *testing.T) {
+		cases := map[string][]BlindLevel{
+			"no levels":         nil,
+			"starts on a break": {{Break: true, Duration: time.Minute}},
+			"no duration":       {{SmallBlind: 25, BigBlind: 50}},
+			"no blinds":         {{Duration: time.Minute}},
+			"small above big":   {{SmallBlind: 100, BigBlind: 50, Duration: time.Minute}},
+			"negative ante":     {{SmallBlind: 25, BigBlind: 50, Ante: -1, Duration: time.Minute}},
+			"break with blinds": {{SmallBlind: 25, BigBlind: 50, Duration: time.Minute}, {Break: true, BigBlind: 50, Duration: time.Minute}},
+			"blinds go down": {
+				{SmallBlind: 50, BigBlind: 100, Duration: time.Minute},
+				{SmallBlind: 25, BigBlind: 50, Duration: time.Minute},
+			},
+		}
+
+		for name, levels := range cases {
+			t.Run(name, func(t *testing.T) {
+				if err := (BlindStructure{Levels: levels}).Validate(); err == nil {
+					t.Error("expected a validation error but got none")
+				}
+			})
+		}
+	})
+
+	t.Run("invalid files are reported on load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "broken.json")
+		os.WriteFile(path, []byte(`{"levels": [{"small_blind": 50, "big_blind": 25, "duration": "10m"}]}`), 0644)
+
+		_, err := LoadBlindStructure(path)
+
+		if err == nil || !strings.Contains(err.Error(), "level 1") {
+			t.Errorf("expected an error pointing at level 1 but got %v", err)
+		}
+	})
+
+	t.Run("files are named after themselves when they have no name", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "friday.yaml")
+		os.WriteFile(path, []byte("levels:\n  - {small_blind: 25, big_blind: 50, duration: 10m}\n"), 0644)
+
+		structure, err := LoadBlindStructure(path)
+
+		assertNoError(t, err)
+		if structure.Name != "friday" {
+			t.Errorf("got name %q want %q", structure.Name, "friday")
+		}
+	})
+
+	t.Run("built in structures are valid", func(t *testing.T) {
+		names := BlindStructureNames()
+		want := []string{"deep-stack", "standard", "turbo"}
+
+		if strings.Join(names, ",") != strings.Join(want, ",") {
+			t.Fatalf("got built in structures %v want %v", names, want)
+		}
+
+		for _, name := range names {
+			structure, err := LoadBlindStructure(name)
+			assertNoError(t, err)
+
+			if structure.Name != name {
+				t.Errorf("structure in %s.json is called %q", name, structure.Name)
+			}
+		}
+	})
+
+	t.Run("unknown names are not built in", func(t *testing.T) {
+		_, ok, _ := BuiltInBlindStructure("hyper")
+
+		if ok {
+			t.Error("did not expect a built in structure called hyper")
+		}
+	})
+}
+
+func assertBlindLevels(t testing.TB, got, want []BlindLevel) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d levels %v want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("level %d: got %+v want %+v", i+1, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package poker
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+type Suit uint8
+
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+const suitLetters = "cdhs"
+
+func (s Suit) String() string {
+	return string(suitLetters[s])
+}
+
+// Rank runs from Two to Ace so ranks compare the way players expect.
+type Rank uint8
+
+const (
+	Two Rank = iota + 2
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+	Ace
+)
+
+const rankLetters = "23456789TJQKA"
+
+func (r Rank) String() string {
+	return string(rankLetters[r-Two])
+}
+
+type Card struct {
+	Rank Rank
+	Suit Suit
+}
+
+// String writes cards the usual short way, e.g. "As" or "Td".
+func (c Card) String() string {
+	return c.Rank.String() + c.Suit.String()
+}
+
+// ParseCard reads a card written like "As", "td" or "10h".
+func ParseCard(input string) (Card, error) {
+	text := strings.TrimSpace(input)
+	if strings.HasPrefix(text, "10") {
+		text = "T" + text[2:]
+	}
+	if len(text) != 2 {
+		return Card{}, fmt.Errorf("%q is not a card", input)
+	}
+
+	rank := strings.IndexByte(rankLetters, strings.ToUpper(text[:1])[0])
+	suit := strings.IndexByte(suitLetters, strings.ToLower(text[1:])[0])
+	if rank < 0 || suit < 0 {
+		return Card{}, fmt.Errorf("%q is not a card", input)
+	}
+
+	return Card{Rank(rank) + Two, Suit(suit)}, nil
+}
+
+// ParseCards reads cards separated by spaces, e.g. "As Kd 7c".
+func ParseCards(input string) ([]Card, error) {
+	var cards []Card
+	for _, field := range strings.Fields(input) {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func formatCards(cards []Card) string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.String()
+	}
+	return strings.Join(names, " ")
+}
+
+type Deck struct {
+	cards []Card
+}
+
+// NewDeck returns the 52 cards in order, Two of Clubs first.
+func NewDeck() *Deck {
+	deck := &Deck{}
+	for suit := Clubs; suit <= Spades; suit++ {
+		for rank := Two; rank <= Ace; rank++ {
+			deck.cards = append(deck.cards, Card{rank, suit})
+		}
+	}
+	return deck
+}
+
+// NewShuffledDeck returns a deck shuffled from seed.
+func NewShuffledDeck(seed int64) *Deck {
+	deck := NewDeck()
+	deck.Shuffle(rand.New(rand.NewSource(seed)))
+	return deck
+}
+
+func (d *Deck) Shuffle(rng *rand.Rand) {
+	rng.Shuffle(len(d.cards), func(i, j int) {
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	})
+}
+
+func (d *Deck) Len() int {
+	return len(d.cards)
+}
+
+// Draw takes n cards off the top of the deck.
+func (d *Deck) Draw(n int) ([]Card, error) {
+	if n > len(d.cards) {
+		return nil, fmt.Errorf("cannot draw %d cards from a deck of %d", n, len(d.cards))
+	}
+
+	cards := d.cards[:n:n]
+	d.cards = d.cards[n:]
+	return cards, nil
+}
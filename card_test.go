@@ -0,0 +1,78 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	cases := map[string]Card{
+		"As":  {Ace, Spades},
+		"td":  {Ten, Diamonds},
+		"10h": {Ten, Hearts},
+		"2c":  {Two, Clubs},
+	}
+
+	for input, want := range cases {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseCard(input)
+			if err != nil {
+				t.Fatalf("did not expect an error but got %v", err)
+			}
+			if got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "A", "1s", "Ax", "Asd"} {
+		t.Run("rejects "+input, func(t *testing.T) {
+			if _, err := ParseCard(input); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
+
+func TestDeck(t *testing.T) {
+	t.Run("a new deck has 52 different cards", func(t *testing.T) {
+		deck := NewDeck()
+		cards, err := deck.Draw(52)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen := map[Card]bool{}
+		for _, card := range cards {
+			if seen[card] {
+				t.Fatalf("%v is in the deck twice", card)
+			}
+			seen[card] = true
+		}
+		if len(seen) != 52 || deck.Len() != 0 {
+			t.Errorf("got %d cards with %d left, want 52 with none left", len(seen), deck.Len())
+		}
+	})
+
+	t.Run("the same seed shuffles the same way", func(t *testing.T) {
+		first, _ := NewShuffledDeck(42).Draw(10)
+		second, _ := NewShuffledDeck(42).Draw(10)
+		other, _ := NewShuffledDeck(43).Draw(10)
+
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("got %v and %v from the same seed", first, second)
+		}
+		if reflect.DeepEqual(first, other) {
+			t.Errorf("different seeds dealt the same cards %v", first)
+		}
+	})
+
+	t.Run("cannot draw more cards than are left", func(t *testing.T) {
+		deck := NewDeck()
+		deck.Draw(50)
+
+		if _, err := deck.Draw(3); err == nil {
+			t.Error("expected an error drawing 3 cards from 2")
+		}
+	})
+}
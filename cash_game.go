@@ -0,0 +1,220 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const CashCommandsHelp = "{Name} buys in {amount}, {Name} cashes out {amount}, balance or end"
+
+// CashStake is what one player put into a cash game and took out of it.
+type CashStake struct {
+	Player    string `json:"player"`
+	BoughtIn  int    `json:"bought_in"`
+	CashedOut int    `json:"cashed_out"`
+	Net       int    `json:"net"`
+}
+
+// CashResult is a finished cash game session.
+type CashResult struct {
+	Started  time.Time   `json:"started"`
+	Finished time.Time   `json:"finished"`
+	Players  []CashStake `json:"players"`
+}
+
+// CashGameStore is a PlayerStore that keeps cash games and net results.
+type CashGameStore interface {
+	PlayerStore
+	RecordCashGame(CashResult) error
+}
+
+// CashGame keeps the books for a cash game, which ends only once they balance.
+type CashGame struct {
+	store   PlayerStore
+	clock   Clock
+	out     io.Writer
+	started time.Time
+	stakes  map[string]*CashStake
+	seated  map[string]bool
+}
+
+func NewCashGame(store PlayerStore, clock Clock) *CashGame {
+	return &CashGame{store: store, clock: clock}
+}
+
+// Start opens the books; anyone can buy in whatever numberOfPlayers is.
+func (c *CashGame) Start(numberOfPlayers int, alertsDestination io.Writer) {
+	c.out = alertsDestination
+	c.started = c.clock.Now()
+	c.stakes = map[string]*CashStake{}
+	c.seated = map[string]bool{}
+
+	fmt.Fprintf(c.out, "Cash game for %d players, type %s\n", numberOfPlayers, CashCommandsHelp)
+}
+
+// BuyIn puts amount more of player's money on the table.
+func (c *CashGame) BuyIn(player string, amount int) error {
+	if amount <= 0 {
+		return fmt.Errorf("a buy-in must be more than nothing, got %d", amount)
+	}
+
+	stake, ok := c.stakes[player]
+	if !ok {
+		stake = &CashStake{Player: player}
+		c.stakes[player] = stake
+	}
+	stake.BoughtIn += amount
+	c.seated[player] = true
+
+	fmt.Fprintf(c.out, "%s buys in for %d, %d in play\n", player, amount, c.InPlay())
+	return nil
+}
+
+// CashOut takes player off the table with amount, 0 if they busted.
+func (c *CashGame) CashOut(player string, amount int) error {
+	if !c.seated[player] {
+		return fmt.Errorf("%s is not playing, type \"%s buys in {amount}\" first", player, player)
+	}
+	if amount < 0 {
+		return fmt.Errorf("%s cannot cash out less than nothing", player)
+	}
+	if amount > c.InPlay() {
+		return fmt.Errorf("%s cannot cash out %d, only %d is in play", player, amount, c.InPlay())
+	}
+
+	stake := c.stakes[player]
+	stake.CashedOut += amount
+	delete(c.seated, player)
+
+	fmt.Fprintf(c.out, "%s cashes out %d (%+d), %d in play\n", player, amount, stake.CashedOut-stake.BoughtIn, c.InPlay())
+	return nil
+}
+
+// InPlay is everything bought in less everything cashed out.
+func (c *CashGame) InPlay() int {
+	total := 0
+	for _, stake := range c.stakes {
+		total += stake.BoughtIn - stake.CashedOut
+	}
+	return total
+}
+
+// Balanced reports whether the session can end, and why not when it cannot.
+func (c *CashGame) Balanced() error {
+	if len(c.stakes) == 0 {
+		return errors.New("nobody has bought in")
+	}
+	if len(c.seated) > 0 {
+		return fmt.Errorf("%s still to cash out", strings.Join(sortedNames(c.seated), ", "))
+	}
+	if inPlay := c.InPlay(); inPlay != 0 {
+		return fmt.Errorf("the table does not balance, %d more was bought in than cashed out", inPlay)
+	}
+	return nil
+}
+
+// Result is the session so far, biggest winner first.
+func (c *CashGame) Result() CashResult {
+	result := CashResult{Started: c.started, Finished: c.clock.Now()}
+	for _, stake := range c.stakes {
+		stake.Net = stake.CashedOut - stake.BoughtIn
+		result.Players = append(result.Players, *stake)
+	}
+	sort.Slice(result.Players, func(i, j int) bool {
+		a, b := result.Players[i], result.Players[j]
+		if a.Net != b.Net {
+			return a.Net > b.Net
+		}
+		return a.Player < b.Player
+	})
+	return result
+}
+
+// Play runs the cash game's commands, leaving "end" unhandled once balanced.
+func (c *CashGame) Play(input string) (bool, error) {
+	input = strings.TrimSpace(input)
+
+	switch strings.ToLower(input) {
+	case "balance":
+		c.writeBalance()
+		return true, nil
+	case "end":
+		if err := c.Balanced(); err != nil {
+			return true, err
+		}
+		return false, nil
+	}
+
+	for _, command := range []struct {
+		verb string
+		run  func(string, int) error
+	}{
+		{" buys in ", c.BuyIn},
+		{" cashes out ", c.CashOut},
+	} {
+		player, amount, ok := strings.Cut(input, command.verb)
+		if !ok || player == "" {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(amount))
+		if err != nil {
+			return true, fmt.Errorf("%q is not an amount", amount)
+		}
+		return true, command.run(player, value)
+	}
+
+	return true, fmt.Errorf("%q is not a cash game command, type %s", input, CashCommandsHelp)
+}
+
+func (c *CashGame) writeBalance() {
+	for _, stake := range c.Result().Players {
+		line := fmt.Sprintf("%s: bought in %d, cashed out %d", stake.Player, stake.BoughtIn, stake.CashedOut)
+		if c.seated[stake.Player] {
+			line += ", still playing"
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	fmt.Fprintf(c.out, "%d in play\n", c.InPlay())
+}
+
+// Finish saves everyone's net result; there is no winner.
+func (c *CashGame) Finish(string) {
+	if err := c.Balanced(); err != nil {
+		fmt.Fprintf(c.out, "%v, the session was not saved\n", err)
+		return
+	}
+
+	result := c.Result()
+	for _, stake := range result.Players {
+		fmt.Fprintf(c.out, "%s %+d\n", stake.Player, stake.Net)
+	}
+	if payments, err := result.Nets().Settle(); err == nil && len(payments) > 0 {
+		fmt.Fprintln(c.out, "To settle up:")
+		for _, payment := range payments {
+			fmt.Fprintln(c.out, payment)
+		}
+	}
+
+	store, ok := c.store.(CashGameStore)
+	if !ok {
+		fmt.Fprintln(c.out, "this store cannot keep cash games, the session was not saved")
+		return
+	}
+	if err := store.RecordCashGame(result); err != nil {
+		fmt.Fprintf(c.out, "problem saving the cash game %v\n", err)
+	}
+}
+
+func sortedNames(players map[string]bool) []string {
+	names := make([]string, 0, len(players))
+	for name := range players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
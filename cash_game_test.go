@@ -0,0 +1,94 @@
+package poker
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCashGame(store PlayerStore) (*CashGame, *ManualClock, *bytes.Buffer) {
+	clock := NewManualClock(eightPM)
+	out := &bytes.Buffer{}
+	game := NewCashGame(store, clock)
+	game.Start(3, out)
+	return game, clock, out
+}
+
+func TestCashGame(t *testing.T) {
+	t.Run("a balanced session saves everyone's net result", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game, clock, out := newTestCashGame(store)
+
+		for _, input := range []string{
+			"Alice buys in 100",
+			"Bob buys in 100",
+			"Cleo buys in 100",
+			"Bob buys in 50",
+			"Cleo cashes out 0",
+			"Alice cashes out 240",
+			"Bob cashes out 110",
+		} {
+			handled, err := gameCommand(game, input)
+			if !handled || err != nil {
+				t.Fatalf("%q: got handled %v, error %v", input, handled, err)
+			}
+		}
+		clock.Advance(3 * time.Hour)
+
+		if handled, err := gameCommand(game, "end"); handled || err != nil {
+			t.Fatalf("expected end to finish the game, got handled %v, error %v", handled, err)
+		}
+		game.Finish("end")
+
+		want := CashResult{Started: eightPM, Finished: eightPM.Add(3 * time.Hour), Players: []CashStake{
+			{Player: "Alice", BoughtIn: 100, CashedOut: 240, Net: 140},
+			{Player: "Bob", BoughtIn: 150, CashedOut: 110, Net: -40},
+			{Player: "Cleo", BoughtIn: 100, CashedOut: 0, Net: -100},
+		}}
+		if len(store.cashGames) != 1 || !reflect.DeepEqual(store.cashGames[0], want) {
+			t.Errorf("got cash games %+v\nwant %+v", store.cashGames, want)
+		}
+		if !strings.Contains(out.String(), "Alice +140\nBob -40\nCleo -100\n") {
+			t.Errorf("expected the nets to be announced but got %q", out.String())
+		}
+	})
+
+	t.Run("a session that does not balance cannot end", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game, _, _ := newTestCashGame(store)
+
+		assertNoError(t, game.BuyIn("Alice", 100))
+		assertNoError(t, game.BuyIn("Bob", 100))
+
+		if handled, err := gameCommand(game, "end"); !handled || err == nil {
+			t.Error("expected players still seated to stop the game ending")
+		}
+
+		assertNoError(t, game.CashOut("Alice", 150))
+		if err := game.CashOut("Bob", 100); err == nil {
+			t.Error("expected a cash-out bigger than what is left to be refused")
+		}
+		assertNoError(t, game.CashOut("Bob", 40))
+
+		if handled, err := gameCommand(game, "end"); !handled || err == nil || !strings.Contains(err.Error(), "10 more") {
+			t.Errorf("expected the missing 10 to be reported, got %v", err)
+		}
+
+		game.Finish("end")
+		if len(store.cashGames) != 0 {
+			t.Errorf("expected nothing saved but got %+v", store.cashGames)
+		}
+	})
+
+	t.Run("things that are not cash game commands", func(t *testing.T) {
+		game, _, _ := newTestCashGame(&StubPlayerStore{})
+
+		for _, input := range []string{"Alice wins", "Alice buys in lots", "Alice cashes out 10", "Alice buys in 0"} {
+			if handled, err := gameCommand(game, input); !handled || err == nil {
+				t.Errorf("%q: expected an error, got handled %v, error %v", input, handled, err)
+			}
+		}
+	})
+}
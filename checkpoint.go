@@ -0,0 +1,52 @@
+package poker
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// CheckpointInterval is the most of the clock a restart can lose.
+const CheckpointInterval = 30 * time.Second
+
+// Checkpoint is a game saved between hands, to be resumed after a restart.
+type Checkpoint struct {
+	ID      string    `json:"id"`
+	Saved   time.Time `json:"saved"`
+	Options string    `json:"options,omitempty"`
+	Players int       `json:"players"`
+	Variant string    `json:"variant"`
+
+	Blinds    BlindStructure `json:"blinds"`
+	Level     int            `json:"level"`
+	Remaining time.Duration  `json:"remaining"`
+	Paused    bool           `json:"paused,omitempty"`
+
+	StartingStack int                   `json:"starting_stack,omitempty"`
+	Table         *TableCheckpoint      `json:"table,omitempty"`
+	Tournament    *TournamentCheckpoint `json:"tournament,omitempty"`
+}
+
+// String is like "6 players, 100/200 with 4m0s left, saved 21:04".
+func (c Checkpoint) String() string {
+	description := fmt.Sprintf("%d players", c.Players)
+	if c.Level < len(c.Blinds.Levels) {
+		description += fmt.Sprintf(", %v with %v left", c.Blinds.Levels[c.Level], c.Remaining.Round(time.Second))
+	}
+	return description + ", saved " + c.Saved.Format("Mon 15:04")
+}
+
+// ResumableGame can be picked up again from a Checkpoint.
+type ResumableGame interface {
+	Game
+	Checkpoint() Checkpoint
+	Resume(checkpoint Checkpoint, alertsDestination io.Writer) error
+}
+
+// CheckpointStore keeps unfinished games, one checkpoint per ID.
+type CheckpointStore interface {
+	PlayerStore
+	SaveCheckpoint(Checkpoint) error
+	RemoveCheckpoint(id string) error
+	Checkpoints() []Checkpoint
+}
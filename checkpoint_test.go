@@ -0,0 +1,209 @@
+package poker
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckpoints(t *testing.T) {
+	t.Run("a clock can start part way through a level", func(t *testing.T) {
+		clock, alerter, _ := newTestClock(io.Discard)
+
+		assertNoClockError(t, clock.StartAt(1, 4*time.Minute, false))
+
+		assertClockState(t, clock.State(), ClockState{Level: 1, Blinds: level200, Remaining: 4 * time.Minute})
+		assertLiveAlerts(t, alerter, []scheduledAlert{
+			{at: 0, alert: BlindAlert{level200, level400, 4 * time.Minute}},
+			{at: 4 * time.Minute, alert: BlindAlert{Level: level400}},
+		})
+
+		if err := clock.StartAt(3, time.Minute, false); err == nil {
+			t.Error("expected an error starting at a level that does not exist")
+		}
+	})
+
+	t.Run("a hand being played is called off", func(t *testing.T) {
+		table, _ := NewTable([]string{"A", "B", "C"}, 1000, 1, io.Discard)
+		hand, _ := table.DealHand(blinds50100)
+		hand.Act(hand.ToAct(), Move{Action: Fold})
+		hand.Act(hand.ToAct(), Move{Action: Fold})
+		table.DealHand(blinds50100)
+
+		checkpoint := table.Checkpoint()
+
+		want := []SeatCheckpoint{{"A", 1000}, {"B", 950}, {"C", 1050}}
+		if !reflect.DeepEqual(checkpoint.Seats, want) {
+			t.Errorf("got seats %v want %v", checkpoint.Seats, want)
+		}
+
+		restored, err := RestoreTable(checkpoint, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hand, _ = restored.DealHand(blinds50100)
+		assertButton(t, restored, hand, "B")
+	})
+
+	t.Run("a tournament's books are kept", func(t *testing.T) {
+		clock := NewManualClock(time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))
+		tournament := NewTournament(TournamentRules{BuyIn: 10, Rebuy: 10}, 4, clock, io.Discard)
+		tournament.Eliminate("Dan")
+		tournament.Rebuy("Dan")
+		tournament.Eliminate("Dan")
+		tournament.Eliminate("Cleo")
+
+		restored, err := RestoreTournament(tournament.Checkpoint(), clock, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if restored.Remaining() != 2 || restored.PrizePool() != 50 {
+			t.Errorf("got %d players left and a prize pool of %d", restored.Remaining(), restored.PrizePool())
+		}
+		assertPlace(t, restored, "Bob", 2)
+		result, err := restored.Finish("Alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.Placings[len(result.Placings)-1]; got.Player != "Dan" || got.Place != 4 || got.Rebuys != 1 {
+			t.Errorf("got last place %+v", got)
+		}
+	})
+
+	t.Run("a game carries on from where it was saved", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		game := NewTexasHoldem(&StubPlayerStore{}, NewAlerter(clock), clock)
+		game.PlayVariant(OmahaVariant)
+		game.UseBlinds(BlindStructure{Name: "test", Levels: testLevels})
+		game.PlayTournament(TournamentRules{BuyIn: 10})
+		game.DealCards(1000, 1)
+		game.Start(3, io.Discard)
+		clock.Advance(14 * time.Minute)
+		game.Play("fold")
+
+		checkpoint := game.Checkpoint()
+
+		out := &bytes.Buffer{}
+		later := NewManualClock(time.Time{}.Add(time.Hour))
+		resumed := NewTexasHoldem(&StubPlayerStore{}, NewAlerter(later), later)
+		if err := resumed.Resume(checkpoint, out); err != nil {
+			t.Fatal(err)
+		}
+		later.Advance(0)
+
+		if resumed.Variant().Name != "omaha" || resumed.Tournament() == nil {
+			t.Error("expected the same variant and tournament")
+		}
+		if state := resumed.Clock().State(); state.Level != 1 || state.Remaining != 6*time.Minute {
+			t.Errorf("got the clock at %+v", state)
+		}
+		if got := resumed.Table().PlayersWithChips(); len(got) != 3 {
+			t.Errorf("got players %v", got)
+		}
+		for _, want := range []string{"Resuming Pot Limit Omaha for 3 players", "Blinds are now", "Hand #"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in %q", want, out.String())
+			}
+		}
+	})
+
+	t.Run("checkpoints are kept in the file until the game is over", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		assertNoError(t, store.SaveCheckpoint(Checkpoint{ID: "1", Players: 3, Level: 1}))
+		assertNoError(t, store.SaveCheckpoint(Checkpoint{ID: "2", Players: 5}))
+		assertNoError(t, store.SaveCheckpoint(Checkpoint{ID: "1", Players: 3, Level: 2}))
+		assertNoError(t, store.RemoveCheckpoint("2"))
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		if got := reopened.Checkpoints(); len(got) != 1 || got[0].ID != "1" || got[0].Level != 2 {
+			t.Errorf("got checkpoints %+v", got)
+		}
+	})
+}
+
+func TestGameManagerCheckpoints(t *testing.T) {
+	t.Run("a running game is saved as it goes and forgotten when it ends", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		store := &StubPlayerStore{}
+		manager := NewGameManager(clock)
+		manager.KeepCheckpoints(store)
+
+		holdem := NewTexasHoldem(store, &spyAlerter{}, clock)
+		holdem.UseBlinds(BlindStructure{Name: "test", Levels: testLevels})
+		game := manager.Create(holdem, 3, "")
+		game.Start()
+		assertCheckpoints(t, store, 1)
+
+		clock.Advance(CheckpointInterval + CheckpointInterval/2)
+		if saved := store.Checkpoints()[0]; saved.Remaining != 10*time.Minute-CheckpointInterval {
+			t.Errorf("got %v left in the level, want it saved after %v", saved.Remaining, CheckpointInterval)
+		}
+
+		game.End("Ruth")
+		assertCheckpoints(t, store, 0)
+		if clock.Pending() != 0 {
+			t.Error("expected no more checkpoints once the game is over")
+		}
+	})
+
+	t.Run("a saved game is resumed under its own id", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		store := &StubPlayerStore{}
+		store.SaveCheckpoint(Checkpoint{
+			ID: "7", Players: 4, Variant: "holdem",
+			Blinds: BlindStructure{Levels: testLevels}, Level: 2, Remaining: time.Minute, Paused: true,
+		})
+
+		manager := NewGameManager(clock)
+		manager.KeepCheckpoints(store)
+
+		game, err := manager.Resume(store.Checkpoints()[0], NewTexasHoldem(store, &spyAlerter{}, clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGameState(t, game, GamePaused)
+
+		if _, err := manager.Resume(store.Checkpoints()[0], NewTexasHoldem(store, &spyAlerter{}, clock)); err == nil {
+			t.Error("expected an error resuming the same game twice")
+		}
+		if next := manager.Create(&GameSpy{}, 2, ""); next.ID != "8" {
+			t.Errorf("got id %s for a new game, want 8", next.ID)
+		}
+	})
+}
+
+func TestPlayerServer_ResumeGames(t *testing.T) {
+	store := &StubPlayerStore{}
+	store.SaveCheckpoint(Checkpoint{ID: "3", Players: 4, Variant: "holdem", Blinds: BlindStructure{Levels: testLevels}, Remaining: time.Minute})
+	store.SaveCheckpoint(Checkpoint{ID: "4", Players: 4, Variant: "canasta", Blinds: BlindStructure{Levels: testLevels}})
+
+	clock := NewManualClock(time.Time{})
+	server := NewPlayerServer(store, func() Game { return NewTexasHoldem(store, &spyAlerter{}, clock) })
+
+	resumed, err := server.ResumeGames()
+	if err == nil || !strings.Contains(err.Error(), "canasta") {
+		t.Errorf("expected the game that cannot be dealt to be reported, got %v", err)
+	}
+	if len(resumed) != 1 || resumed[0].ID != "3" {
+		t.Fatalf("got %d games resumed", len(resumed))
+	}
+	assertGameState(t, resumed[0], GamePaused)
+}
+
+func assertCheckpoints(t testing.TB, store *StubPlayerStore, want int) {
+	t.Helper()
+	if got := store.Checkpoints(); len(got) != want {
+		t.Errorf("got %d checkpoints want %d", len(got), want)
+	}
+}
@@ -0,0 +1,260 @@
+// Package client talks to a poker PlayerServer over HTTP.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+const (
+	DefaultTimeout = 5 * time.Second
+	DefaultRetries = 3
+	DefaultBackoff = 200 * time.Millisecond
+)
+
+// ErrUnreachable is wrapped by every request that got no answer.
+var ErrUnreachable = errors.New("cannot reach the server")
+
+// Error is an answer from the server saying a request failed.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("the server said %d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("the server said %d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Client retries failed requests Retries times, doubling Backoff each time.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	Retries int
+	Backoff time.Duration
+}
+
+// New returns a client for an http or https server.
+func New(server string) (*Client, error) {
+	u, err := url.Parse(server)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not a server, give one like http://localhost:5000", server)
+	}
+
+	return &Client{
+		BaseURL: strings.TrimSuffix(u.String(), "/"),
+		HTTP:    &http.Client{Timeout: DefaultTimeout},
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}, nil
+}
+
+// Score is player's wins, 0 if the server does not know them.
+func (c *Client) Score(player string) (int, error) {
+	var body bytes.Buffer
+	err := c.do(http.MethodGet, "/players/"+url.PathEscape(player), nil, &body)
+
+	var notFound *Error
+	if errors.As(err, &notFound) && notFound.Status == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	score, err := strconv.Atoi(strings.TrimSpace(body.String()))
+	if err != nil {
+		return 0, fmt.Errorf("the server sent %q rather than a score", body.String())
+	}
+	return score, nil
+}
+
+func (c *Client) RecordWin(player string) error {
+	return c.do(http.MethodPost, "/players/"+url.PathEscape(player), nil, nil)
+}
+
+// League is ranked by wins, or by net when sortBy is "net".
+func (c *Client) League(sortBy string) (poker.League, error) {
+	path := "/league"
+	if sortBy != "" {
+		path += "?sort=" + url.QueryEscape(sortBy)
+	}
+
+	var league poker.League
+	return league, c.do(http.MethodGet, path, nil, &league)
+}
+
+func (c *Client) RecordTournament(result poker.TournamentResult) error {
+	return c.do(http.MethodPost, "/tournaments", result, nil)
+}
+
+func (c *Client) RecordCashGame(result poker.CashResult) error {
+	return c.do(http.MethodPost, "/cash-games", result, nil)
+}
+
+// Settlement is what everyone is owed and who pays whom to square it.
+func (c *Client) Settlement() (poker.Settlement, error) {
+	var settlement poker.Settlement
+	return settlement, c.do(http.MethodGet, "/settlement", nil, &settlement)
+}
+
+func (c *Client) RecordPayment(payment poker.Payment) error {
+	return c.do(http.MethodPost, "/settlement", payment, nil)
+}
+
+// Sync sends journal entries, which the server takes only once, so it is safe to retry.
+func (c *Client) Sync(entries []poker.JournalEntry) ([]poker.SyncResult, error) {
+	if entries == nil {
+		entries = []poker.JournalEntry{}
+	}
+	var results []poker.SyncResult
+	return results, c.do(http.MethodPut, "/journal", entries, &results)
+}
+
+// SyncJournal syncs and marks what the server took; rejected entries stay in the journal.
+func (c *Client) SyncJournal(journal *poker.FileSystemPlayerStore) ([]poker.SyncResult, error) {
+	entries := journal.Unsynced()
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	results, err := c.Sync(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var synced []string
+	for _, result := range results {
+		if result.Done() {
+			synced = append(synced, result.ID)
+		}
+	}
+	return results, journal.MarkSynced(synced...)
+}
+
+// Games lists the games the server is running or has run.
+func (c *Client) Games() ([]poker.GameInfo, error) {
+	var games []poker.GameInfo
+	return games, c.do(http.MethodGet, "/games", nil, &games)
+}
+
+// CreateGame takes options as they follow the player count in a start message.
+func (c *Client) CreateGame(players int, options string) (poker.GameInfo, error) {
+	request := map[string]any{"players": players, "options": options}
+
+	var game poker.GameInfo
+	return game, c.do(http.MethodPost, "/games", request, &game)
+}
+
+func (c *Client) Game(id string) (poker.GameInfo, error) {
+	var game poker.GameInfo
+	return game, c.do(http.MethodGet, "/games/"+url.PathEscape(id), nil, &game)
+}
+
+func (c *Client) JoinGame(id, player string) (poker.GameInfo, error) {
+	var game poker.GameInfo
+	return game, c.do(http.MethodPost, "/games/"+url.PathEscape(id)+"/join", map[string]string{"name": player}, &game)
+}
+
+func (c *Client) StartGame(id string) (poker.GameInfo, error) {
+	var game poker.GameInfo
+	return game, c.do(http.MethodPost, "/games/"+url.PathEscape(id)+"/start", nil, &game)
+}
+
+func (c *Client) EndGame(id, winner string) (poker.GameInfo, error) {
+	var game poker.GameInfo
+	return game, c.do(http.MethodPost, "/games/"+url.PathEscape(id)+"/end", map[string]string{"winner": winner}, &game)
+}
+
+// do sends in as JSON and reads the answer into out, as JSON or into a *bytes.Buffer.
+func (c *Client) do(method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	wait := c.Backoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(method, path, body, out)
+		if err == nil || attempt >= c.Retries || !retry(method, err) {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func (c *Client) attempt(method, path string, body []byte, out any) error {
+	request, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("content-type", "application/json")
+	}
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w at %s: %w", ErrUnreachable, c.BaseURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return &Error{Status: response.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err = io.Copy(out, response.Body)
+		return err
+	default:
+		if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+			return fmt.Errorf("problem reading what the server sent %v", err)
+		}
+		return nil
+	}
+}
+
+// retry reports whether a failed request is safe to send again.
+func retry(method string, err error) bool {
+	var status *Error
+	if errors.As(err, &status) {
+		switch status.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return idempotent(method)
+		}
+		return false
+	}
+	if !errors.Is(err, ErrUnreachable) {
+		return false
+	}
+	if idempotent(method) {
+		return true
+	}
+
+	var dial *net.OpError
+	return errors.As(err, &dial) && dial.Op == "dial"
+}
+
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut
+}
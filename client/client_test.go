@@ -0,0 +1,374 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+func TestClient(t *testing.T) {
+	t.Run("it keeps results on the server", func(t *testing.T) {
+		store := newFileStore(t)
+		c := newClient(t, poker.NewPlayerServer(store, newGame))
+
+		assertNoError(t, c.RecordWin("Pepper"))
+		assertNoError(t, c.RecordWin("Pepper"))
+
+		score, err := c.Score("Pepper")
+		assertNoError(t, err)
+		if score != 2 {
+			t.Errorf("got score %d want 2", score)
+		}
+		if got := store.GetPlayerScore("Pepper"); got != 2 {
+			t.Errorf("the server has %d wins for Pepper, want 2", got)
+		}
+
+		score, err = c.Score("Nobody")
+		assertNoError(t, err)
+		if score != 0 {
+			t.Errorf("got score %d for someone who has never played, want 0", score)
+		}
+	})
+
+	t.Run("it reads the league both ways", func(t *testing.T) {
+		store := newFileStore(t)
+		c := newClient(t, poker.NewPlayerServer(store, newGame))
+
+		assertNoError(t, c.RecordTournament(poker.TournamentResult{Entrants: 2, Placings: []poker.Placing{
+			{Place: 1, Player: "Cleo"}, {Place: 2, Player: "Chris"},
+		}}))
+		assertNoError(t, c.RecordCashGame(poker.CashResult{Players: []poker.CashStake{
+			{Player: "Chris", BoughtIn: 100, CashedOut: 150, Net: 50},
+			{Player: "Cleo", BoughtIn: 100, CashedOut: 50, Net: -50},
+		}}))
+
+		league, err := c.League("")
+		assertNoError(t, err)
+		if len(league) != 2 || league[0].Name != "Cleo" {
+			t.Errorf("got league %v, want Cleo first on wins", league)
+		}
+
+		league, err = c.League("net")
+		assertNoError(t, err)
+		if len(league) != 2 || league[0].Name != "Chris" {
+			t.Errorf("got league %v, want Chris first on net", league)
+		}
+
+		_, err = c.League("luck")
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("it settles up", func(t *testing.T) {
+		store := newFileStore(t)
+		c := newClient(t, poker.NewPlayerServer(store, newGame))
+
+		assertNoError(t, c.RecordCashGame(poker.CashResult{Players: []poker.CashStake{
+			{Player: "Chris", Net: 50}, {Player: "Cleo", Net: -50},
+		}}))
+
+		settlement, err := c.Settlement()
+		assertNoError(t, err)
+		if settlement.Balances["Chris"] != 50 || len(settlement.Payments) != 1 {
+			t.Fatalf("got settlement %+v", settlement)
+		}
+
+		assertNoError(t, c.RecordPayment(settlement.Payments[0]))
+		if owed := store.Balances()["Chris"]; owed != 0 {
+			t.Errorf("Chris is still owed %d after being paid", owed)
+		}
+	})
+
+	t.Run("it runs games", func(t *testing.T) {
+		c := newClient(t, poker.NewPlayerServer(newFileStore(t), newGame))
+
+		game, err := c.CreateGame(2, "")
+		assertNoError(t, err)
+
+		_, err = c.JoinGame(game.ID, "Chris")
+		assertNoError(t, err)
+		_, err = c.JoinGame(game.ID, "Cleo")
+		assertNoError(t, err)
+		_, err = c.StartGame(game.ID)
+		assertNoError(t, err)
+
+		game, err = c.EndGame(game.ID, "Cleo")
+		assertNoError(t, err)
+		if game.Winner != "Cleo" {
+			t.Errorf("got winner %q want Cleo", game.Winner)
+		}
+
+		games, err := c.Games()
+		assertNoError(t, err)
+		if len(games) != 1 {
+			t.Errorf("got %d games want 1", len(games))
+		}
+
+		_, err = c.Game("nope")
+		assertStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("it tries reading again while the server is unavailable", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) < 3 {
+				http.Error(w, "starting up", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`[{"Name": "Cleo", "Wins": 1}]`))
+		}))
+
+		league, err := c.League("")
+		assertNoError(t, err)
+		if len(league) != 1 || requests.Load() != 3 {
+			t.Errorf("got league %v after %d requests, want Cleo after 3", league, requests.Load())
+		}
+	})
+
+	t.Run("it gives up after its retries", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+		}))
+
+		_, err := c.League("")
+		assertStatus(t, err, http.StatusServiceUnavailable)
+		if got := requests.Load(); got != int32(c.Retries+1) {
+			t.Errorf("got %d requests want %d", got, c.Retries+1)
+		}
+		if !strings.Contains(err.Error(), "down for maintenance") {
+			t.Errorf("got %q, want what the server said", err)
+		}
+	})
+
+	t.Run("it does not send a win twice when the server may have kept it", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+		assertStatus(t, c.RecordWin("Pepper"), http.StatusBadGateway)
+		if got := requests.Load(); got != 1 {
+			t.Errorf("got %d requests want 1", got)
+		}
+	})
+
+	t.Run("it says when the server cannot be reached", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		c, err := New(server.URL)
+		assertNoError(t, err)
+		c.Backoff = time.Millisecond
+
+		for _, err := range []error{c.RecordWin("Pepper"), func() error { _, err := c.Games(); return err }()} {
+			if !errors.Is(err, ErrUnreachable) {
+				t.Errorf("got %v, want %v", err, ErrUnreachable)
+			}
+		}
+	})
+
+	t.Run("it stops waiting for a slow server", func(t *testing.T) {
+		release := make(chan struct{})
+		c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer close(release)
+		c.HTTP.Timeout = 20 * time.Millisecond
+		c.Retries = 0
+
+		_, err := c.Games()
+		if !errors.Is(err, ErrUnreachable) {
+			t.Errorf("got %v, want %v", err, ErrUnreachable)
+		}
+	})
+
+	t.Run("it only takes http and https servers", func(t *testing.T) {
+		for _, server := range []string{"", "localhost:5000", "ftp://example.com", "http://"} {
+			if _, err := New(server); err == nil {
+				t.Errorf("expected an error for %q", server)
+			}
+		}
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("it is a store for games played at the cli", func(t *testing.T) {
+		files := newFileStore(t)
+		store := NewStore(newClient(t, poker.NewPlayerServer(files, newGame)), nil)
+
+		var _ poker.TournamentStore = store
+		var _ poker.CashGameStore = store
+		var _ poker.SettlementStore = store
+
+		game := poker.NewCashGame(store, poker.NewManualClock(time.Now()))
+		game.Start(2, &bytes.Buffer{})
+		for _, command := range []string{"Chris buys in 100", "Cleo buys in 100", "Chris cashes out 150", "Cleo cashes out 50"} {
+			if _, err := game.Play(command); err != nil {
+				t.Fatalf("%s: %v", command, err)
+			}
+		}
+		game.Finish("")
+
+		store.RecordWin("Cleo")
+		if got := store.GetPlayerScore("Cleo"); got != 1 {
+			t.Errorf("got score %d want 1", got)
+		}
+		if league := store.GetLeague(); len(league) != 2 {
+			t.Errorf("got league %v", league)
+		}
+		if owed := store.Balances()["Chris"]; owed != 50 {
+			t.Errorf("Chris is owed %d want 50", owed)
+		}
+	})
+
+	t.Run("it reports what it could not do", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		c, _ := New(server.URL)
+		c.Retries = 0
+
+		var errs bytes.Buffer
+		store := NewStore(c, &errs)
+		store.GetLeague()
+
+		if !strings.Contains(errs.String(), "problem getting the league") {
+			t.Errorf("got %q", errs.String())
+		}
+		if err := store.RecordWin("Pepper"); !errors.Is(err, ErrUnreachable) {
+			t.Errorf("got %v, want the win to fail as the server is unreachable", err)
+		}
+	})
+}
+
+func TestSyncJournal(t *testing.T) {
+	t.Run("it sends what was kept offline once", func(t *testing.T) {
+		files := newFileStore(t)
+		c := newClient(t, poker.NewPlayerServer(files, newGame))
+
+		journal := newFileStore(t)
+		journal.KeepJournal()
+		journal.RecordWin("Chris")
+		journal.RecordWin("Cleo")
+
+		results, err := c.SyncJournal(journal)
+		assertNoError(t, err)
+		if len(results) != 2 || results[0].Status != poker.SyncMerged || results[1].Status != poker.SyncMerged {
+			t.Fatalf("got %+v, want both wins merged", results)
+		}
+		if unsynced := journal.Unsynced(); len(unsynced) != 0 {
+			t.Errorf("got %d entries still to sync", len(unsynced))
+		}
+
+		results, err = c.SyncJournal(journal)
+		assertNoError(t, err)
+		if len(results) != 0 {
+			t.Errorf("got %+v the second time, want nothing sent", results)
+		}
+		if got := files.GetPlayerScore("Chris"); got != 1 {
+			t.Errorf("got %d wins for Chris on the server want 1", got)
+		}
+	})
+
+	t.Run("it sends again when the answer was lost, without counting twice", func(t *testing.T) {
+		files := newFileStore(t)
+		server := poker.NewPlayerServer(files, newGame)
+		var requests atomic.Int32
+		c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				server.ServeHTTP(httptest.NewRecorder(), r)
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			server.ServeHTTP(w, r)
+		}))
+
+		journal := newFileStore(t)
+		journal.KeepJournal()
+		journal.RecordWin("Chris")
+
+		results, err := c.SyncJournal(journal)
+		assertNoError(t, err)
+		if len(results) != 1 || results[0].Status != poker.SyncDuplicate || !results[0].Done() {
+			t.Errorf("got %+v, want the win found already there", results)
+		}
+		if got := files.GetPlayerScore("Chris"); got != 1 {
+			t.Errorf("got %d wins for Chris on the server want 1", got)
+		}
+	})
+
+	t.Run("conflicts stay in the journal", func(t *testing.T) {
+		started := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+		tournament := func(winner, second string) poker.TournamentResult {
+			return poker.TournamentResult{Started: started, Entrants: 2, Placings: []poker.Placing{
+				{Place: 1, Player: winner}, {Place: 2, Player: second},
+			}}
+		}
+
+		files := newFileStore(t)
+		assertNoError(t, files.RecordTournament(tournament("Cleo", "Chris")))
+		c := newClient(t, poker.NewPlayerServer(files, newGame))
+
+		journal := newFileStore(t)
+		journal.KeepJournal()
+		assertNoError(t, journal.RecordTournament(tournament("Chris", "Cleo")))
+
+		results, err := c.SyncJournal(journal)
+		assertNoError(t, err)
+		if len(results) != 1 || results[0].Status != poker.SyncConflict {
+			t.Fatalf("got %+v, want a conflict", results)
+		}
+		if len(journal.Unsynced()) != 1 {
+			t.Error("expected the conflict to be left to sync")
+		}
+	})
+}
+
+func newGame() poker.Game {
+	return &poker.GameSpy{}
+}
+
+func newClient(t testing.TB, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL)
+	assertNoError(t, err)
+	c.Backoff = time.Millisecond
+	return c
+}
+
+func newFileStore(t testing.TB) *poker.FileSystemPlayerStore {
+	t.Helper()
+
+	store, close, err := poker.FileSystemPlayerStoreFromFile(filepath.Join(t.TempDir(), "game.db.json"))
+	assertNoError(t, err)
+	t.Cleanup(close)
+	return store
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got one, %v", err)
+	}
+}
+
+func assertStatus(t testing.TB, err error, want int) {
+	t.Helper()
+	var status *Error
+	if !errors.As(err, &status) || status.Status != want {
+		t.Errorf("got %v, want the server to say %d", err, want)
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"io"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+// Store is a PlayerStore on the server. Read errors go to Errors, as PlayerStore cannot return them.
+type Store struct {
+	Client *Client
+	Errors io.Writer
+}
+
+func NewStore(client *Client, errors io.Writer) *Store {
+	return &Store{Client: client, Errors: errors}
+}
+
+func (s *Store) GetPlayerScore(name string) int {
+	score, err := s.Client.Score(name)
+	s.report("problem getting %s's score, %v", name, err)
+	return score
+}
+
+func (s *Store) RecordWin(name string) error {
+	return s.Client.RecordWin(name)
+}
+
+func (s *Store) GetLeague() poker.League {
+	league, err := s.Client.League("")
+	s.report("problem getting the league, %v", err)
+	return league
+}
+
+func (s *Store) RecordTournament(result poker.TournamentResult) error {
+	return s.Client.RecordTournament(result)
+}
+
+func (s *Store) RecordCashGame(result poker.CashResult) error {
+	return s.Client.RecordCashGame(result)
+}
+
+func (s *Store) Balances() poker.Balances {
+	settlement, err := s.Client.Settlement()
+	s.report("problem getting what is owed, %v", err)
+	return settlement.Balances
+}
+
+func (s *Store) RecordPayment(payment poker.Payment) error {
+	return s.Client.RecordPayment(payment)
+}
+
+// report writes err, if any, after format's other arguments.
+func (s *Store) report(format string, a ...any) {
+	if err := a[len(a)-1]; err != nil && s.Errors != nil {
+		fmt.Fprintf(s.Errors, format+"\n", a...)
+	}
+}
@@ -0,0 +1,290 @@
+package poker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ClockScreenHelp lists the keys the full screen clock answers to.
+const ClockScreenHelp = "space pause/resume · s skip · b back · q back to the prompt"
+
+// ClockView is everything the clock screen shows at one moment.
+type ClockView struct {
+	Title        string
+	State        ClockState
+	Next         BlindLevel
+	HasNext      bool
+	Players      int
+	AverageStack int
+	League       League
+	Message      string
+
+	// Locale is the language to show the clock in, English when it is nil.
+	Locale *Locale
+}
+
+// ScreenGame is the game a ManagedGame runs, not the ManagedGame itself.
+type ScreenGame interface {
+	ClockedGame
+	ClockView() ClockView
+}
+
+// String is the clock as plain lines, for when there is no terminal.
+func (v ClockView) String() string {
+	l := v.Locale
+	var b strings.Builder
+	if v.Title != "" {
+		fmt.Fprintf(&b, "%s, ", v.Title)
+	}
+	b.WriteString(l.Sprintf("level %d: %s", v.State.Level+1, v.blinds()) + "\n")
+
+	b.WriteString(l.Sprintf("%s left", countdown(v.State.Remaining)))
+	if v.State.Paused {
+		b.WriteString(l.Text(", paused"))
+	}
+	b.WriteString("\n")
+
+	if v.HasNext {
+		b.WriteString(l.Sprintf("Next: %s", v.levelName(v.Next)) + "\n")
+	}
+	b.WriteString(v.players() + "\n")
+	return b.String()
+}
+
+func (v ClockView) blinds() string {
+	return v.levelName(v.State.Blinds)
+}
+
+func (v ClockView) levelName(level BlindLevel) string {
+	if level.Break {
+		return v.Locale.Text("Break")
+	}
+	return v.Locale.Blinds(level)
+}
+
+func (v ClockView) players() string {
+	players := v.Locale.Plural(v.Players, "%s player", "%s players")
+	if v.AverageStack > 0 {
+		players += v.Locale.Sprintf(", average stack %s", v.Locale.Number(v.AverageStack))
+	}
+	return players
+}
+
+// Draw paints the clock, and the league when there is room.
+func (v ClockView) Draw(out io.Writer, width, height int) {
+	sidebar := 0
+	if width >= 72 && len(v.League) > 0 {
+		sidebar = 26
+	}
+	main := width - sidebar
+
+	var lines []string
+	add := func(line string) { lines = append(lines, center(line, main)) }
+
+	add(strings.ToUpper(v.Title))
+	add("")
+	add(strings.ToUpper(v.Locale.Sprintf("Level %d", v.State.Level+1)))
+	add(v.blinds())
+	add("")
+	digits := bigDigits(countdown(v.State.Remaining))
+	if len([]rune(digits[0])) > main {
+		digits = []string{countdown(v.State.Remaining)}
+	}
+	for _, row := range digits {
+		add(row)
+	}
+	add("")
+	if v.State.Paused {
+		add(strings.ToUpper(v.Locale.Text("Paused")))
+	} else {
+		add("")
+	}
+	if v.HasNext {
+		add(v.Locale.Sprintf("Next: %s", v.levelName(v.Next)))
+	} else {
+		add(v.Locale.Text("Last level"))
+	}
+	add(v.players())
+
+	if sidebar > 0 {
+		lines = v.withLeague(lines, sidebar, height-2)
+	}
+
+	fmt.Fprint(out, "\x1b[H\x1b[2J")
+	for i, line := range lines {
+		if i >= height-2 {
+			break
+		}
+		fmt.Fprint(out, line+"\r\n")
+	}
+	fmt.Fprintf(out, "\x1b[%d;1H%s\r\n%s", height-1, fit(v.Message, width), fit(v.Locale.Text(ClockScreenHelp), width))
+}
+
+func (v ClockView) withLeague(lines []string, width, rows int) []string {
+	league := []string{strings.ToUpper(v.Locale.Text("League")), ""}
+	for i, player := range v.League {
+		league = append(league, fit(fmt.Sprintf("%2d. %-16s %3d", i+1, player.Name, player.Wins), width-2))
+	}
+
+	for len(lines) < len(league) && len(lines) < rows {
+		lines = append(lines, strings.Repeat(" ", len([]rune(lines[0]))))
+	}
+	for i := range lines {
+		entry := ""
+		if i < len(league) {
+			entry = league[i]
+		}
+		lines[i] += "│ " + entry
+	}
+	return lines
+}
+
+// countdown is minutes and seconds, with hours in front when there are any.
+func countdown(remaining time.Duration) string {
+	seconds := int(remaining / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// font is five rows of each character the countdown can have in it.
+var font = map[rune][5]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+func bigDigits(text string) []string {
+	rows := make([]string, 5)
+	for _, r := range text {
+		for i := range rows {
+			rows[i] += font[r][i] + " "
+		}
+	}
+	for i := range rows {
+		rows[i] = strings.TrimSuffix(rows[i], " ")
+	}
+	return rows
+}
+
+func center(line string, width int) string {
+	length := len([]rune(line))
+	if length >= width {
+		return fit(line, width)
+	}
+	left := (width - length) / 2
+	return strings.Repeat(" ", left) + line + strings.Repeat(" ", width-length-left)
+}
+
+func fit(line string, width int) string {
+	if runes := []rune(line); len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
+
+// keyboard reads one key at a time, reporting false if none is pressed.
+type keyboard interface {
+	ReadKey() (key byte, ok bool, err error)
+}
+
+// clockScreen redraws the clock until it is told to go.
+type clockScreen struct {
+	game    *ManagedGame
+	store   PlayerStore
+	out     io.Writer
+	size    func() (int, int)
+	message func() string
+	locale  *Locale
+	err     error
+}
+
+func (s *clockScreen) run(keys keyboard) error {
+	for {
+		if err := s.draw(); err != nil {
+			return err
+		}
+
+		key, ok, err := keys.ReadKey()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if s.press(key) {
+			return nil
+		}
+	}
+}
+
+// press acts on a key and reports whether it was one to leave the screen.
+func (s *clockScreen) press(key byte) bool {
+	var command string
+	switch key {
+	case 'q', 'Q', 27, 3: // escape and control-c leave too
+		return true
+	case ' ', 'p', 'P':
+		command = "pause"
+		if view, err := s.game.ClockView(); err == nil && view.State.Paused {
+			command = "resume"
+		}
+	case 's', 'S':
+		command = "skip"
+	case 'b', 'B':
+		command = "back"
+	default:
+		return false
+	}
+
+	_, s.err = s.game.Play(command)
+	return false
+}
+
+func (s *clockScreen) draw() error {
+	view, err := s.game.ClockView()
+	if err != nil {
+		return err
+	}
+	if s.store != nil {
+		view.League = s.store.GetLeague()
+	}
+	if s.message != nil {
+		view.Message = s.message()
+	}
+	view.Locale = s.locale
+	if s.err != nil {
+		view.Message, s.err = s.err.Error(), nil
+	}
+
+	width, height := s.size()
+	view.Draw(s.out, width, height)
+	return nil
+}
+
+// ShowClock shows game's clock full screen until q is pressed.
+func ShowClock(term *Terminal, game *ManagedGame, store PlayerStore, message func() string, locale *Locale) error {
+	restore, err := term.raw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	// the alternate screen leaves what was typed before as it was
+	fmt.Fprint(term.out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(term.out, "\x1b[?25h\x1b[?1049l")
+
+	screen := &clockScreen{game: game, store: store, out: term.out, size: term.Size, message: message, locale: locale}
+	return screen.run(term)
+}
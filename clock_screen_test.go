@@ -0,0 +1,125 @@
+package poker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockView(t *testing.T) {
+	view := ClockView{
+		Title:        "Texas Hold'em",
+		State:        ClockState{Level: 1, Blinds: level200, Remaining: 4*time.Minute + 5*time.Second, Paused: true},
+		Next:         level400,
+		HasNext:      true,
+		Players:      7,
+		AverageStack: 14286,
+		League:       League{{Name: "Chris", Wins: 3}, {Name: "Cleo", Wins: 1}},
+		Message:      "Dan is out in 8th place",
+	}
+
+	t.Run("prints as lines without a terminal", func(t *testing.T) {
+		want := "Texas Hold'em, level 2: 100/200\n" +
+			"04:05 left, paused\n" +
+			"Next: 200/400\n" +
+			"7 players, average stack 14,286\n"
+
+		if got := view.String(); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("draws the whole screen with the league beside the clock", func(t *testing.T) {
+		screen := &bytes.Buffer{}
+		view.Draw(screen, 100, 30)
+
+		for _, want := range []string{"LEVEL 2", "PAUSED", "Next: 200/400", " 1. Chris", "│", "Dan is out", ClockScreenHelp} {
+			if !strings.Contains(screen.String(), want) {
+				t.Errorf("expected %q on the screen %q", want, screen.String())
+			}
+		}
+		for _, row := range bigDigits("04:05") {
+			if !strings.Contains(screen.String(), row) {
+				t.Errorf("expected the countdown in big digits, missing %q", row)
+			}
+		}
+	})
+
+	t.Run("leaves out what does not fit on a small screen", func(t *testing.T) {
+		screen := &bytes.Buffer{}
+		view.Draw(screen, 20, 24)
+
+		if strings.Contains(screen.String(), "LEAGUE") || strings.Contains(screen.String(), "█") {
+			t.Errorf("expected no league or big digits at 20 wide, got %q", screen.String())
+		}
+		if !strings.Contains(screen.String(), "04:05") {
+			t.Errorf("expected the countdown, got %q", screen.String())
+		}
+	})
+
+	t.Run("formats times and numbers", func(t *testing.T) {
+		cases := map[string]string{
+			countdown(90 * time.Second):                        "01:30",
+			countdown(time.Hour + 2*time.Minute + time.Second): "1:02:01",
+			English.Number(999):                                "999",
+			English.Number(1234567):                            "1,234,567",
+			English.Number(-20000):                             "-20,000",
+		}
+		for got, want := range cases {
+			if got != want {
+				t.Errorf("got %q want %q", got, want)
+			}
+		}
+	})
+}
+
+func TestClockScreen(t *testing.T) {
+	clock := NewManualClock(time.Time{})
+	holdem := NewTexasHoldem(&StubPlayerStore{}, NewAlerter(clock), clock)
+	holdem.UseBlinds(BlindStructure{Name: "test", Levels: testLevels})
+	holdem.PlayTournament(TournamentRules{BuyIn: 10, Rebuy: 10, StartingStack: 1000})
+	game := NewGameManager(clock).Create(holdem, 4, "")
+	game.Start()
+
+	game.Play("Dan out")
+	game.Play("Dan rebuys")
+	game.Play("Cleo out")
+
+	out := &bytes.Buffer{}
+	screen := &clockScreen{game: game, out: out, size: func() (int, int) { return 80, 24 }}
+	keys := &scriptedKeys{keys: "s xq"}
+
+	if err := screen.run(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	view, _ := game.ClockView()
+	if view.State.Level != 1 || !view.State.Paused {
+		t.Errorf("expected the clock skipped a level and paused, got %+v", view.State)
+	}
+	if view.Players != 3 || view.AverageStack != 1666 {
+		t.Errorf("got %d players averaging %d chips", view.Players, view.AverageStack)
+	}
+	if keys.read != 2*len(keys.keys) {
+		t.Errorf("expected the screen to go at q, read %d keys", keys.read)
+	}
+	if !strings.Contains(out.String(), "PAUSED") {
+		t.Errorf("expected the pause to be shown, got %q", out.String())
+	}
+}
+
+// scriptedKeys presses each of keys in turn, with nothing pressed for a
+// moment between them.
+type scriptedKeys struct {
+	keys string
+	read int
+}
+
+func (k *scriptedKeys) ReadKey() (byte, bool, error) {
+	k.read++
+	if k.read%2 == 1 {
+		return 0, false, nil
+	}
+	return k.keys[(k.read-1)/2], true, nil
+}
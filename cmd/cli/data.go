@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+const backupTime = "20060102-150405"
+
+// export writes everything kept as JSON.
+func export(args []string) error {
+	flags := newFlags("export", "", "Write everything kept as JSON, to be read back by import.")
+	db := storeFlag(flags)
+	output := flags.String("o", "-", "file to write, - for the standard output")
+	flags.Parse(args)
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if *output == "-" {
+		return store.Export(os.Stdout)
+	}
+	return writeExport(store, *output)
+}
+
+// importData replaces everything kept with an export.
+func importData(args []string) error {
+	flags := newFlags("import", "<file | ->", "Replace everything kept with an export. Anything already kept is backed up first.")
+	db := storeFlag(flags)
+	force := flags.Bool("force", false, "replace what is already kept")
+	dir := flags.String("dir", "backups", "directory to back up to first")
+	output := outputFlag(flags, poker.OutputPlain)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("import takes the file to read")
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if !store.Empty() && !*force {
+		return fmt.Errorf("%s already has a league in it, use -force to replace it", *db)
+	}
+	return replace(store, data, *db, *dir, *output)
+}
+
+// backup saves a copy of everything kept in the backup directory.
+func backup(args []string) error {
+	flags := newFlags("backup", "", "Save a copy of everything kept, named for when it was taken.")
+	db := storeFlag(flags)
+	dir := flags.String("dir", "backups", "directory to keep backups in")
+	output := outputFlag(flags, poker.OutputPlain)
+	flags.Parse(args)
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	path, err := takeBackup(store, *db, *dir)
+	if err != nil {
+		return err
+	}
+	return report(*output, path, map[string]string{"backup": path})
+}
+
+// restore puts a backup back, or lists the backups there are.
+func restore(args []string) error {
+	flags := newFlags("restore", "[backup]", "Put a backup back in place of what is kept, backing that up first. With no backup named, list them newest first.")
+	db := storeFlag(flags)
+	dir := flags.String("dir", "backups", "directory backups are kept in")
+	output := outputFlag(flags, poker.OutputPlain)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		backups, err := filepath.Glob(filepath.Join(*dir, "*.json"))
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(backups, func(i, j int) bool { return modified(backups[i]).After(modified(backups[j])) })
+		if *output == poker.OutputJSON {
+			return report(*output, "", append([]string{}, backups...))
+		}
+		if len(backups) == 0 {
+			fmt.Printf("There are no backups in %s\n", *dir)
+		}
+		for _, backup := range backups {
+			fmt.Println(backup)
+		}
+		return nil
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	return replace(store, data, *db, *dir, *output)
+}
+
+// fsck checks everything kept adds up.
+func fsck(args []string) error {
+	flags := newFlags("fsck", "", "Check everything kept adds up, printing each problem found. It exits with 1 if there are any.")
+	db := storeFlag(flags)
+	output := outputFlag(flags, poker.OutputPlain)
+	flags.Parse(args)
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	problems := store.Check()
+	if *output == poker.OutputJSON {
+		report(*output, "", map[string]any{"db": *db, "problems": append([]string{}, problems...)})
+	} else {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%s is fine\n", *db)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems in %s", len(problems), *db)
+	}
+	return nil
+}
+
+// replace backs up store, imports data and reports what does not add up.
+func replace(store *poker.FileSystemPlayerStore, data []byte, db, dir string, output poker.Output) error {
+	var backup string
+	if !store.Empty() {
+		path, err := takeBackup(store, db, dir)
+		if err != nil {
+			return fmt.Errorf("problem backing up before replacing, %v", err)
+		}
+		backup = path
+	}
+
+	if err := store.Import(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	warnings := append([]string{}, store.Check()...)
+
+	if output == poker.OutputJSON {
+		return report(output, "", map[string]any{"backup": backup, "warnings": warnings})
+	}
+	if backup != "" {
+		fmt.Printf("What was kept before is in %s\n", backup)
+	}
+	for _, warning := range warnings {
+		fmt.Println("warning:", warning)
+	}
+	return nil
+}
+
+// takeBackup exports store to dir, named after its file and the time.
+func takeBackup(store *poker.FileSystemPlayerStore, db, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s.%s", strings.TrimSuffix(filepath.Base(db), ".json"), time.Now().Format(backupTime))
+
+	// never write over an earlier backup, even one taken the same second
+	for n := 1; ; n++ {
+		path := filepath.Join(dir, name+".json")
+		if n > 1 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d.json", name, n))
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return path, exportTo(store, file)
+	}
+}
+
+func writeExport(store *poker.FileSystemPlayerStore, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return exportTo(store, file)
+}
+
+// exportTo writes store to file and closes it.
+func exportTo(store *poker.FileSystemPlayerStore, file *os.File) error {
+	if err := store.Export(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func modified(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
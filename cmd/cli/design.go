@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+// design prints a blind structure; its JSON works with -blinds.
+func design(args []string) error {
+	flags := newFlags("design", "", "Design a blind structure for the night. The JSON printed can be handed straight back to play -blinds.")
+	players := flags.Int("players", 8, "number of players")
+	stack := flags.Int("stack", 10000, "starting stack")
+	duration := flags.Duration("duration", 4*time.Hour, "how long the tournament should last")
+	chips := flags.String("chips", "25,100,500,1000", "chip denominations, smallest first")
+	output := outputFlag(flags, poker.OutputJSON)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+
+	denominations, err := poker.ParseChips(*chips)
+	if err != nil {
+		return err
+	}
+
+	structure, err := poker.DesignBlindStructure(poker.BlindDesign{
+		Players:       *players,
+		StartingStack: *stack,
+		Duration:      *duration,
+		Chips:         denominations,
+	})
+	if err != nil {
+		return fmt.Errorf("problem designing blinds %v", err)
+	}
+
+	return poker.WriteBlindStructure(os.Stdout, structure, *output, lang.locale)
+}
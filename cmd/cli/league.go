@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+// league prints the league, by wins or by cash game results.
+func league(args []string) error {
+	flags := newFlags("league", "", "Print the league.")
+	db := storeFlag(flags)
+	server := serverFlag(flags)
+	sortBy := flags.String("sort", "wins", "order by wins or net")
+	output := outputFlag(flags, poker.OutputPlain)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+
+	if *sortBy != "wins" && *sortBy != "net" {
+		return fmt.Errorf("the league can be sorted by wins or net, not %q", *sortBy)
+	}
+
+	league, err := readLeague(*db, *server, *sortBy)
+	if err != nil {
+		return err
+	}
+
+	return poker.WriteLeague(os.Stdout, league, *output, lang.locale)
+}
+
+func readLeague(db, server, sortBy string) (poker.League, error) {
+	if server != "" {
+		c, err := newClient(server)
+		if err != nil {
+			return nil, err
+		}
+		return c.League(sortBy)
+	}
+
+	store, close, err := openStore(db)
+	if err != nil {
+		return nil, err
+	}
+	defer close()
+
+	league := store.GetLeague()
+	if sortBy == "net" {
+		league = league.ByNet()
+	}
+	return league, nil
+}
+
+// stats prints everyone's record, or one player's.
+func stats(args []string) error {
+	flags := newFlags("stats", "[name]", "Print each player's wins, tournaments, cash games and what they are owed.")
+	db := storeFlag(flags)
+	output := outputFlag(flags, poker.OutputTable)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	records := store.Stats()
+	if name := flags.Arg(0); name != "" {
+		record, err := store.PlayerStats(name)
+		if err != nil {
+			return err
+		}
+		records = []poker.PlayerStats{record}
+	}
+
+	return poker.WriteStats(os.Stdout, records, *output, lang.locale)
+}
+
+// player adds, renames and merges players.
+func player(args []string) error {
+	flags := newFlags("player", "add <name> | rename <name> <new name> | merge <name> <into name>",
+		"Add a player to the league, rename one everywhere they appear, or merge someone in the league twice into one.")
+	db := storeFlag(flags)
+	output := outputFlag(flags, poker.OutputPlain)
+	flags.Parse(args)
+	args = flags.Args()
+
+	want := map[string]int{"add": 2, "rename": 3, "merge": 3}
+	if len(args) == 0 || want[args[0]] != len(args) {
+		flags.Usage()
+		return fmt.Errorf("player takes add, rename or merge")
+	}
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	switch args[0] {
+	case "add":
+		err = store.AddPlayer(args[1])
+	case "rename":
+		err = store.RenamePlayer(args[1], args[2])
+	case "merge":
+		err = store.MergePlayers(args[1], args[2])
+	}
+	if err != nil {
+		return err
+	}
+
+	done := map[string]string{"action": args[0], "player": args[1]}
+	if len(args) == 3 {
+		done["to"] = args[2]
+	}
+	return report(*output, "Done", done)
+}
@@ -1,27 +1,229 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	poker "github.com/phildehovre/go-server"
+	"github.com/phildehovre/go-server/client"
 )
 
 const dbFileName = "game.db.json"
 
+// command is one of "cli <name> [flags] [args]".
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+// config is where each command's flags start.
+var config = poker.DefaultConfig()
+
+func init() {
+	commands = []command{
+		{"play", "play poker at the table, the default", play},
+		{"league", "print the league", league},
+		{"stats", "print everyone's record, or one player's", stats},
+		{"player", "add, rename or merge players", player},
+		{"settle", "print who pays whom, or record a payment", settle},
+		{"design", "design a blind structure", design},
+		{"export", "write everything kept as JSON", export},
+		{"import", "replace everything kept with an export", importData},
+		{"backup", "save a copy of everything kept", backup},
+		{"restore", "put a backup back, or list them", restore},
+		{"fsck", "check everything kept adds up", fsck},
+		{"sync", "send results kept offline to a server", syncJournal},
+		{"help", "print this", help},
+	}
+}
+
 func main() {
-	fmt.Println("Let's play poker")
-	fmt.Println("Type {Name} wins to record a win")
+	args := os.Args[1:]
 
-	store, close, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	// with no command, or only flags, it plays as it always has
+	name := "play"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	if err != nil {
+	var err error
+	if config, err = poker.LoadConfig(poker.ConfigPath(args, os.Getenv), os.Getenv); err != nil {
 		log.Fatal(err)
 	}
-	defer close()
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			if err := c.run(args); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%q is not a command\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func help([]string) error {
+	usage()
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cli <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run cli <command> -h for a command's flags.")
+}
+
+// newFlags starts a command's flags, with its usage for -h.
+func newFlags(name, arguments, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.String("config", "", "YAML file of settings, read before anything else (POKER_CONFIG)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: cli %s [flags] %s\n\n%s\n\n", name, arguments, description)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// storeFlag adds -db, defaulting to the configured store if it is a file.
+func storeFlag(flags *flag.FlagSet) *string {
+	path, ok := config.StoreFile()
+	if !ok {
+		path = dbFileName
+	}
+	return flags.String("db", path, "file everything is kept in (POKER_STORE)")
+}
+
+// openStore is how every command gets at the store.
+func openStore(path string) (*poker.FileSystemPlayerStore, func(), error) {
+	return poker.FileSystemPlayerStoreFromFile(path)
+}
+
+// outputFlag adds the -output flag, starting at what suits the command.
+func outputFlag(flags *flag.FlagSet, output poker.Output) *poker.Output {
+	flags.Var(&output, "output", "how to print: plain, table or json")
+	return &output
+}
+
+// report prints text, or value as JSON for a script.
+func report(output poker.Output, text string, value any) error {
+	if output == poker.OutputJSON {
+		return json.NewEncoder(os.Stdout).Encode(value)
+	}
+	fmt.Println(text)
+	return nil
+}
+
+// localeFlag adds -lang, defaulting to LC_ALL, LC_MESSAGES or LANG.
+func localeFlag(flags *flag.FlagSet) *localeValue {
+	lang := &localeValue{poker.LocaleFromEnv(os.Getenv)}
+	flags.Var(lang, "lang", "language to print in: "+strings.Join(localeNames(), ", "))
+	return lang
+}
+
+type localeValue struct {
+	locale *poker.Locale
+}
+
+func (v *localeValue) String() string {
+	if v.locale == nil {
+		return ""
+	}
+	return v.locale.Name
+}
+
+func (v *localeValue) Set(tag string) error {
+	locale, ok := poker.LookupLocale(tag)
+	if !ok {
+		return fmt.Errorf("%q is not a language the cli speaks, use one of %s", tag, strings.Join(localeNames(), ", "))
+	}
+	v.locale = locale
+	return nil
+}
+
+func localeNames() []string {
+	var names []string
+	for _, locale := range poker.Locales {
+		names = append(names, locale.Name)
+	}
+	return names
+}
+
+// serverFlag adds -server, defaulting to the configured store if it is a server.
+func serverFlag(flags *flag.FlagSet) *string {
+	server, _ := config.StoreServer()
+	return flags.String("server", server, "keep results on the server at this URL, e.g. http://localhost:5000, rather than in -db (POKER_STORE)")
+}
+
+// newClient is a client for server with the configured request timeout.
+func newClient(server string) (*client.Client, error) {
+	c, err := client.New(server)
+	if err != nil {
+		return nil, err
+	}
+	c.HTTP.Timeout = config.Timeouts.Request
+	return c, nil
+}
 
-	game := poker.NewTexasHoldem(store, poker.BlindAlerterFunc(poker.StdOutAlerter))
-	cli := poker.NewCLI(os.Stdin, os.Stdout, game)
-	cli.PlayPoker()
+// connect checks the server is there before anything is played.
+func connect(server string) (*client.Client, error) {
+	c, err := newClient(server)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.League(""); err != nil {
+		return nil, fmt.Errorf("problem connecting to %s, %w", server, err)
+	}
+	return c, nil
+}
+
+// journalFlag adds -journal, for results to sync later.
+func journalFlag(flags *flag.FlagSet, path string) *string {
+	return flags.String("journal", path, "file to keep results in while offline, for sync to send to a server later")
+}
+
+// openJournal opens the store at path, journalling for sync.
+func openJournal(path string) (*poker.FileSystemPlayerStore, func(), error) {
+	store, close, err := openStore(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	store.KeepJournal()
+	return store, close, nil
+}
+
+// openPlayStore opens the file, the server, or the journal when the server is down.
+func openPlayStore(path, server, journal string, locale *poker.Locale) (poker.PlayerStore, func(), error) {
+	if server == "" && journal != "" {
+		return openJournal(journal)
+	}
+	if server == "" {
+		return openStore(path)
+	}
+	c, err := connect(server)
+	if journal != "" && errors.Is(err, client.ErrUnreachable) {
+		fmt.Fprintln(os.Stderr, locale.Sprintf("Cannot reach %s, keeping results in %s to sync later", server, journal))
+		return openJournal(journal)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.NewStore(c, os.Stderr), func() {}, nil
 }
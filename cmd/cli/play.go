@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+// play runs the game at the table until quit.
+func play(args []string) error {
+	flags := newFlags("play", "", "Play poker, keeping the clock and recording the results.")
+	db := storeFlag(flags)
+	server := serverFlag(flags)
+	journal := journalFlag(flags, "")
+	variant := flags.String("variant", "holdem", "game to deal: one of "+strings.Join(poker.VariantNames(), ", "))
+	blinds := flags.String("blinds", config.Blinds, "blind structure to play: one of "+strings.Join(poker.BlindStructureNames(), ", ")+", or a JSON/YAML file (POKER_BLINDS)")
+	deal := flags.Bool("deal", false, "deal the cards and run the betting")
+	stack := flags.Int("stack", poker.DefaultStartingStack, "starting stack when dealing, and for the average stack in a tournament")
+	seed := flags.Int64("seed", 0, "shuffle seed when dealing, to replay the same cards")
+	buyIn := flags.Int("buyin", 0, "tournament buy-in, which turns on bust-outs and payouts")
+	rebuy := flags.Int("rebuy", 0, "price of a rebuy, 0 for none")
+	addOn := flags.Int("addon", 0, "price of the add-on, 0 for none")
+	tableSize := flags.Int("table-size", poker.DefaultTableSize, "seats per table when seats are drawn for a tournament")
+	payouts := flags.String("payouts", "", "payouts per place, e.g. 50%,30%,20% or 500,300,200 (default depends on the field)")
+	cash := flags.Bool("cash", false, "play a cash game, keeping buy-ins and cash-outs instead of a winner")
+	output := outputFlag(flags, poker.OutputPlain)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+	locale := lang.locale
+
+	say := func(format string, a ...any) {
+		fmt.Println(locale.Sprintf(format, a...))
+	}
+	alerter := poker.NewLocalisedAlerter(poker.RealClock{}, locale)
+	// a script reading JSON gets no introductions, and blind alerts as events
+	if *output == poker.OutputJSON {
+		say = func(string, ...any) {}
+		alerter = poker.NewJSONAlerter(poker.RealClock{})
+	}
+
+	store, close, err := openPlayStore(*db, *server, *journal, locale)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if *cash {
+		say("Let's play a cash game")
+		say("Type %s to keep the books, or help for more", locale.Text(poker.CashCommandsHelp))
+		cli := poker.NewCLI(os.Stdin, os.Stdout, poker.NewCashGame(store, poker.RealClock{}))
+		cli.UseStore(store)
+		cli.UseOutput(*output)
+		cli.UseLocale(locale)
+		cli.PlayPoker()
+		return nil
+	}
+
+	game := poker.NewTexasHoldem(store, alerter, poker.RealClock{})
+
+	chosen, ok := poker.LookupVariant(*variant)
+	if !ok {
+		return fmt.Errorf("%q is not a variant, choose one of %s", *variant, strings.Join(poker.VariantNames(), ", "))
+	}
+	game.PlayVariant(chosen)
+
+	if *blinds != "" {
+		structure, err := poker.LoadBlindStructure(*blinds)
+		if err != nil {
+			return err
+		}
+		game.UseBlinds(structure)
+	}
+
+	if *deal {
+		if *seed == 0 {
+			*seed = poker.NewSeed()
+		}
+		game.DealCards(*stack, *seed)
+	}
+
+	var tournament bool
+	if *buyIn > 0 {
+		rules := poker.TournamentRules{BuyIn: *buyIn, Rebuy: *rebuy, AddOn: *addOn, TableSize: *tableSize, StartingStack: *stack}
+		if *payouts != "" {
+			structure, err := poker.ParsePayouts(*payouts)
+			if err != nil {
+				return err
+			}
+			rules.Payouts = &structure
+		}
+		game.PlayTournament(rules)
+		tournament = true
+	}
+
+	say("Let's play poker")
+	say("Type start <players>, or start {Names} to have results checked against them")
+	say("Type %s to record a result", locale.Text(poker.ResultCommandsHelp))
+	say("Type %s to control the clock, or help for more", locale.Text(poker.ClockCommandsHelp))
+	if *deal {
+		say("Type %s to play the hands", locale.Text(poker.DealCommandsHelp))
+	}
+	if tournament {
+		say("Type %s to keep the books", locale.Text(poker.TournamentCommandsHelp))
+	}
+
+	cli := poker.NewCLI(os.Stdin, os.Stdout, game)
+	cli.UseStore(store)
+	cli.UseOutput(*output)
+	cli.UseLocale(locale)
+	// piped in or out, the clock is printed rather than put on screen
+	if term, err := poker.OpenTerminal(os.Stdin, os.Stdout); err == nil && *output != poker.OutputJSON {
+		cli.UseTerminal(term)
+		say("Type screen to put the clock on the whole screen")
+	}
+	cli.PlayPoker()
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+// settle prints who pays whom, or records "paid <from> <to> <amount>".
+func settle(args []string) error {
+	flags := newFlags("settle", "[paid <from> <to> <amount>]", "Print who pays whom to square what is owed, after recording a payment if one is given.")
+	db := storeFlag(flags)
+	output := outputFlag(flags, poker.OutputPlain)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+	args = flags.Args()
+
+	store, close, err := openStore(*db)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if len(args) > 0 {
+		if len(args) != 4 || args[0] != "paid" {
+			flags.Usage()
+			return fmt.Errorf("settle takes paid <from> <to> <amount>")
+		}
+		amount, err := strconv.Atoi(args[3])
+		if err != nil {
+			return fmt.Errorf("%q is not an amount", args[3])
+		}
+		if err := store.RecordPayment(poker.Payment{From: args[1], To: args[2], Amount: amount}); err != nil {
+			return err
+		}
+	}
+
+	payments, err := store.Balances().Settle()
+	if err != nil {
+		return err
+	}
+	return poker.WritePayments(os.Stdout, payments, *output, lang.locale)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	poker "github.com/phildehovre/go-server"
+)
+
+const journalFileName = "journal.json"
+
+// syncJournal sends the results kept with play -journal to a server.
+func syncJournal(args []string) error {
+	flags := newFlags("sync", "", "Send the results kept with play -journal while offline to a server. Sending them again is safe:\nthe server takes each result once, and results it has a different version of stay in the journal.")
+	journal := journalFlag(flags, journalFileName)
+	server, _ := config.StoreServer()
+	flags.StringVar(&server, "server", server, "the server to send the results to, e.g. http://localhost:5000 (POKER_STORE)")
+	output := outputFlag(flags, poker.OutputPlain)
+	lang := localeFlag(flags)
+	flags.Parse(args)
+	if server == "" {
+		flags.Usage()
+		return fmt.Errorf("sync needs the -server to send the results to")
+	}
+
+	c, err := newClient(server)
+	if err != nil {
+		return err
+	}
+	store, close, err := openStore(*journal)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	results, err := c.SyncJournal(store)
+	if err != nil {
+		return err
+	}
+	if err := poker.WriteSyncResults(os.Stdout, results, *output, lang.locale); err != nil {
+		return err
+	}
+
+	left := 0
+	for _, result := range results {
+		if !result.Done() {
+			left++
+		}
+	}
+	if left > 0 {
+		return fmt.Errorf("%d of the results were not synced and are still in %s", left, *journal)
+	}
+	return nil
+}
@@ -1,24 +1,134 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	poker "github.com/phildehovre/go-server"
 )
 
 func main() {
-	fmt.Println("helloe world")
+	config, err := poker.LoadConfig(poker.ConfigPath(os.Args[1:], os.Getenv), os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.Flags(flag.CommandLine)
+	flag.Parse()
+
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	path, ok := config.StoreFile()
+	if !ok {
+		log.Fatalf("the server keeps results in a file, not at %s", config.Store)
+	}
+
+	level, _ := config.Level()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
 
-	file, _ := os.Open("game.db.json")
-	store, err := poker.NewFileSystemStore(file)
-	server := poker.NewPlayerServer(store)
+	// games are checkpointed as they are played, so the store must be writable
+	store, _, err := poker.FileSystemPlayerStoreFromFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
 
+	var blinds *poker.BlindStructure
+	if config.Blinds != "" {
+		structure, err := poker.LoadBlindStructure(config.Blinds)
+		if err != nil {
+			log.Fatal(err)
+		}
+		blinds = &structure
+	}
+
+	server := poker.NewPlayerServer(store, func() poker.Game {
+		game := poker.NewTexasHoldem(store, poker.BlindAlerterFunc(poker.Alerter), poker.RealClock{})
+		if blinds != nil {
+			game.UseBlinds(*blinds)
+		}
+		return game
+	})
+
+	// browsers asking for a language in Accept-Language get it, the rest
+	// get the one LANG asks for
+	server.UseLocale(poker.LocaleFromEnv(os.Getenv))
+
+	if config.Templates != "" {
+		if err := server.UseTemplates(config.Templates); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	resumed, err := server.ResumeGames()
+	for _, game := range resumed {
+		slog.Info(fmt.Sprintf("resumed game %s with the clock paused, open /game?game=%s to carry on", game.ID, game.ID))
+	}
 	if err != nil {
-		log.Fatal("no file was found")
+		slog.Error(err.Error())
 	}
 
-	log.Fatal(http.ListenAndServe(":5000", server))
+	httpServer := &http.Server{
+		Addr:         config.Listen,
+		Handler:      logRequests(server),
+		ReadTimeout:  config.Timeouts.Read,
+		WriteTimeout: config.Timeouts.Write,
+		IdleTimeout:  config.Timeouts.Idle,
+	}
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	serving := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "address", config.Listen, "store", path)
+		serving <- httpServer.ListenAndServe()
+	}()
+
+	var failed error
+	select {
+	case failed = <-serving:
+	case <-stop.Done():
+		slog.Info("shutting down", "timeout", config.Timeouts.Shutdown)
+	}
+	cancel()
+
+	if err := errors.Join(failed, shutdown(httpServer, server, store, config.Timeouts.Shutdown)); err != nil {
+		log.Fatal(err)
+	}
+	slog.Info("stopped")
+}
+
+// shutdown finishes requests, suspends games and saves the store within timeout.
+func shutdown(httpServer *http.Server, server *poker.PlayerServer, store *poker.FileSystemPlayerStore, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("problem finishing requests %v", err))
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := store.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// logRequests logs each request at debug level.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slog.Debug("request", "method", r.Method, "path", r.URL.Path, "from", r.RemoteAddr)
+		next.ServeHTTP(w, r)
+	})
 }
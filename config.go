@@ -0,0 +1,254 @@
+package poker
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config comes from flags, then POKER_* variables, then the YAML file, then the defaults.
+type Config struct {
+	// Listen is the address the server listens on.
+	Listen string `yaml:"listen"`
+	// Store is a file, like file:game.db.json, or for the CLI a server URL.
+	Store string `yaml:"store"`
+	// Templates is a directory with a game.html to serve instead of the built in one.
+	Templates string `yaml:"templates"`
+	// LogLevel is debug, info, warn or error.
+	LogLevel string `yaml:"log_level"`
+	// Blinds is a built in structure or a JSON or YAML file, empty for the default.
+	Blinds   string   `yaml:"blinds"`
+	Timeouts Timeouts `yaml:"timeouts"`
+}
+
+// Timeouts are the server's connection timeouts and the CLI's request timeout.
+type Timeouts struct {
+	// Read is how long the server takes to read a request.
+	Read time.Duration `yaml:"read"`
+	// Write is how long the server takes to write a response.
+	Write time.Duration `yaml:"write"`
+	// Idle is how long the server keeps a connection open between requests.
+	Idle time.Duration `yaml:"idle"`
+	// Shutdown is how long the server waits for requests to finish when stopping.
+	Shutdown time.Duration `yaml:"shutdown"`
+	// Request is how long the CLI waits on each request to a server.
+	Request time.Duration `yaml:"request"`
+}
+
+const configEnv = "POKER_CONFIG"
+
+// DefaultConfig is what is used when nothing else is set.
+func DefaultConfig() Config {
+	return Config{
+		Listen:   ":5000",
+		Store:    "game.db.json",
+		LogLevel: "info",
+		Timeouts: Timeouts{
+			Read:     10 * time.Second,
+			Write:    10 * time.Second,
+			Idle:     time.Minute,
+			Shutdown: 10 * time.Second,
+			Request:  5 * time.Second,
+		},
+	}
+}
+
+// ConfigPath finds -config before the flags are parsed, so the file can set their defaults.
+func ConfigPath(args []string, getenv func(string) string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return getenv(configEnv)
+}
+
+// LoadConfig reads the file and environment over the defaults; see Validate.
+func LoadConfig(path string, getenv func(string) string) (Config, error) {
+	config := DefaultConfig()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return config, fmt.Errorf("problem reading the config file %v", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return config, fmt.Errorf("problem reading the config file %s: %v", path, err)
+		}
+	}
+
+	env := flag.NewFlagSet("environment", flag.ContinueOnError)
+	env.SetOutput(io.Discard)
+	config.Flags(env)
+
+	var problems []error
+	env.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		name := envName(f.Name)
+		if value := getenv(name); value != "" {
+			if err := env.Set(f.Name, value); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %v", name, err))
+			}
+		}
+	})
+	return config, errors.Join(problems...)
+}
+
+// envName is the variable for the flag called name.
+func envName(name string) string {
+	return "POKER_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Flags adds a flag for each setting, defaulting to its current value.
+func (c *Config) Flags(flags *flag.FlagSet) {
+	for _, s := range []struct {
+		name, usage string
+		value       *string
+	}{
+		{"listen", "address to listen on", &c.Listen},
+		{"store", "where results are kept: a file, or a server like http://localhost:5000", &c.Store},
+		{"templates", "directory with a game.html to serve in place of the built in one", &c.Templates},
+		{"log-level", "least important thing logged: debug, info, warn or error", &c.LogLevel},
+		{"blinds", "blind structure games start with: one of " + strings.Join(BlindStructureNames(), ", ") + ", or a JSON/YAML file", &c.Blinds},
+	} {
+		flags.StringVar(s.value, s.name, *s.value, s.usage+" ("+envName(s.name)+")")
+	}
+
+	for _, s := range []struct {
+		name, usage string
+		value       *time.Duration
+	}{
+		{"read-timeout", "longest to take reading a request", &c.Timeouts.Read},
+		{"write-timeout", "longest to take writing a response", &c.Timeouts.Write},
+		{"idle-timeout", "longest to keep a connection open between requests", &c.Timeouts.Idle},
+		{"shutdown-timeout", "longest to wait for requests to finish when stopping", &c.Timeouts.Shutdown},
+		{"request-timeout", "longest to wait on each request to the server", &c.Timeouts.Request},
+	} {
+		flags.DurationVar(s.value, s.name, *s.value, s.usage+" ("+envName(s.name)+")")
+	}
+
+	flags.String("config", "", "YAML file of settings ("+configEnv+")")
+}
+
+// Validate reports every setting that cannot be used.
+func (c Config) Validate() error {
+	var problems []error
+	problem := func(format string, a ...any) {
+		problems = append(problems, fmt.Errorf(format, a...))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Listen); err != nil {
+		problem("listen: %q is not an address to listen on, give one like :5000", c.Listen)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		problem("listen: %q is not a port", port)
+	}
+
+	if _, _, err := c.store(); err != nil {
+		problem("store: %v", err)
+	}
+
+	if c.Templates != "" {
+		if _, err := loadGameTemplate(c.Templates); err != nil {
+			problem("templates: %v", err)
+		}
+	}
+
+	if _, err := c.Level(); err != nil {
+		problem("log level: %q is not one of debug, info, warn or error", c.LogLevel)
+	}
+
+	if c.Blinds != "" {
+		if _, err := LoadBlindStructure(c.Blinds); err != nil {
+			problem("blinds: %v", err)
+		}
+	}
+
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read", c.Timeouts.Read},
+		{"write", c.Timeouts.Write},
+		{"idle", c.Timeouts.Idle},
+		{"shutdown", c.Timeouts.Shutdown},
+		{"request", c.Timeouts.Request},
+	} {
+		if timeout.value < 0 {
+			problem("%s timeout: %v cannot be negative", timeout.name, timeout.value)
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// Level is the log level as slog has it.
+func (c Config) Level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(c.LogLevel))
+	return level, err
+}
+
+// StoreFile is the file the store is kept in, when it is kept in a file.
+func (c Config) StoreFile() (string, bool) {
+	kind, location, err := c.store()
+	if err != nil || kind != "file" {
+		return "", false
+	}
+	return location, true
+}
+
+// StoreServer is the server the store is kept on, when it is kept on one.
+func (c Config) StoreServer() (string, bool) {
+	kind, location, err := c.store()
+	if err != nil || kind != "server" {
+		return "", false
+	}
+	return location, true
+}
+
+// store reads the store DSN as either a file or a server.
+func (c Config) store() (kind, location string, err error) {
+	if path, ok := strings.CutPrefix(c.Store, "file:"); ok {
+		if path == "" {
+			return "", "", errors.New("file: needs a path after it")
+		}
+		return "file", path, nil
+	}
+	if !strings.Contains(c.Store, "://") {
+		if c.Store == "" {
+			return "", "", errors.New("there is nowhere to keep results, give a file or a server")
+		}
+		return "file", c.Store, nil
+	}
+
+	u, err := url.Parse(c.Store)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("%q is not a store, give a file like file:game.db.json or a server like http://localhost:5000", c.Store)
+	}
+	return "server", c.Store, nil
+}
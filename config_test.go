@@ -0,0 +1,141 @@
+package poker
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	t.Run("it starts at the defaults", func(t *testing.T) {
+		config, err := LoadConfig("", env(nil))
+		assertNoError(t, err)
+		if config != DefaultConfig() {
+			t.Errorf("got %+v want the defaults %+v", config, DefaultConfig())
+		}
+		assertNoError(t, config.Validate())
+	})
+
+	t.Run("flags beat the environment, which beats the file, which beats the defaults", func(t *testing.T) {
+		path := writeConfig(t, "listen: \":6000\"\nstore: file:from-file.json\nlog_level: warn\ntimeouts:\n  read: 30s\n  idle: 2m\n")
+
+		config, err := LoadConfig(path, env(map[string]string{
+			"POKER_STORE":        "from-env.json",
+			"POKER_READ_TIMEOUT": "20s",
+		}))
+		assertNoError(t, err)
+
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		config.Flags(flags)
+		assertNoError(t, flags.Parse([]string{"-config", path, "-read-timeout", "15s"}))
+
+		if config.Listen != ":6000" || config.LogLevel != "warn" || config.Timeouts.Idle != 2*time.Minute {
+			t.Errorf("got %+v, want the listen address, log level and idle timeout from the file", config)
+		}
+		if file, _ := config.StoreFile(); file != "from-env.json" {
+			t.Errorf("got store %q want the one from the environment", file)
+		}
+		if config.Timeouts.Read != 15*time.Second {
+			t.Errorf("got read timeout %v want the flag's 15s", config.Timeouts.Read)
+		}
+		if config.Timeouts.Write != DefaultConfig().Timeouts.Write {
+			t.Errorf("got write timeout %v want the default", config.Timeouts.Write)
+		}
+	})
+
+	t.Run("it finds the file in the arguments or the environment", func(t *testing.T) {
+		getenv := env(map[string]string{"POKER_CONFIG": "env.yaml"})
+		cases := map[string][]string{
+			"flag.yaml": {"-db", "x.json", "-config", "flag.yaml"},
+			"eq.yaml":   {"--config=eq.yaml"},
+			"env.yaml":  {"-db", "x.json", "--", "-config", "after.yaml"},
+		}
+		for want, args := range cases {
+			if got := ConfigPath(args, getenv); got != want {
+				t.Errorf("%q: got %q want %q", args, got, want)
+			}
+		}
+	})
+
+	t.Run("it refuses a file with settings it does not know", func(t *testing.T) {
+		path := writeConfig(t, "listen: \":6000\"\nport: 6000\n")
+		_, err := LoadConfig(path, env(nil))
+		assertConfigError(t, err, "field port not found")
+	})
+
+	t.Run("it refuses a variable it cannot read", func(t *testing.T) {
+		_, err := LoadConfig("", env(map[string]string{"POKER_IDLE_TIMEOUT": "forever"}))
+		assertConfigError(t, err, "POKER_IDLE_TIMEOUT")
+	})
+
+	t.Run("it reports every setting it cannot use", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Listen = "5000"
+		config.Store = "ftp://example.com"
+		config.Templates = t.TempDir()
+		config.LogLevel = "loud"
+		config.Blinds = "no-such-blinds"
+		config.Timeouts.Shutdown = -time.Second
+
+		err := config.Validate()
+		for _, want := range []string{"listen:", "store:", "templates:", "log level:", "blinds:", "shutdown timeout:"} {
+			assertConfigError(t, err, want)
+		}
+	})
+
+	t.Run("the store is a file or a server", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Store = "http://localhost:5000"
+		assertNoError(t, config.Validate())
+		if server, ok := config.StoreServer(); !ok || server != "http://localhost:5000" {
+			t.Errorf("got %q, %v want the server", server, ok)
+		}
+		if _, ok := config.StoreFile(); ok {
+			t.Error("a server is not a file")
+		}
+	})
+}
+
+func TestUseTemplates(t *testing.T) {
+	dir := t.TempDir()
+	page := "<title>{{.T \"Let's play poker\"}}</title>"
+	assertNoError(t, os.WriteFile(filepath.Join(dir, "game.html"), []byte(page), 0o644))
+
+	server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+	assertNoError(t, server.UseTemplates(dir))
+
+	request := httptest.NewRequest(http.MethodGet, "/game", nil)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	AssertStatus(t, response.Code, http.StatusOK)
+	if got := response.Body.String(); got != "<title>Let's play poker</title>" {
+		t.Errorf("got %q want the page from the template directory", got)
+	}
+
+	assertConfigError(t, server.UseTemplates(t.TempDir()), "problem reading the game page")
+}
+
+func writeConfig(t testing.TB, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "poker.yaml")
+	assertNoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func assertConfigError(t testing.TB, err error, want string) {
+	t.Helper()
+
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want one mentioning %q", err, want)
+	}
+}
@@ -0,0 +1,220 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+type HandCategory uint8
+
+const (
+	HighCard HandCategory = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var categoryNames = []string{
+	"high card", "pair", "two pair", "three of a kind", "straight",
+	"flush", "full house", "four of a kind", "straight flush",
+}
+
+func (c HandCategory) String() string {
+	return categoryNames[c]
+}
+
+// HandValue compares directly: higher is stronger, but only within one variant.
+type HandValue uint32
+
+const (
+	rankBits     = 4
+	categoryBits = 4
+	categoryMask = 1<<categoryBits - 1
+)
+
+func newHandValue(category HandCategory, ranks ...Rank) HandValue {
+	value := HandValue(category)
+	for i := 0; i < 5; i++ {
+		value <<= rankBits
+		if i < len(ranks) {
+			value |= HandValue(ranks[i])
+		}
+	}
+	return value
+}
+
+func (v HandValue) Category() HandCategory {
+	return HandCategory(v>>(5*rankBits)) & categoryMask
+}
+
+// rank returns the i'th deciding rank of the hand.
+func (v HandValue) rank(i int) Rank {
+	return Rank(v>>((4-i)*rankBits)) & 0xf
+}
+
+// String describes the hand, e.g. "full house, Ks full of 7s".
+func (v HandValue) String() string {
+	switch v.Category() {
+	case HighCard:
+		return fmt.Sprintf("high card %v", v.rank(0))
+	case OnePair:
+		return fmt.Sprintf("pair of %vs", v.rank(0))
+	case TwoPair:
+		return fmt.Sprintf("two pair, %vs and %vs", v.rank(0), v.rank(1))
+	case ThreeOfAKind:
+		return fmt.Sprintf("three of a kind, %vs", v.rank(0))
+	case Straight:
+		return fmt.Sprintf("straight, %v high", v.rank(0))
+	case Flush:
+		return fmt.Sprintf("flush, %v high", v.rank(0))
+	case FullHouse:
+		return fmt.Sprintf("full house, %vs full of %vs", v.rank(0), v.rank(1))
+	case FourOfAKind:
+		return fmt.Sprintf("four of a kind, %vs", v.rank(0))
+	}
+	if v.rank(0) == Ace {
+		return "royal flush"
+	}
+	return fmt.Sprintf("straight flush, %v high", v.rank(0))
+}
+
+var ErrHandSize = errors.New("a hand needs 5 to 7 cards")
+
+// EvaluateHand values the best five card hand in 5 to 7 cards.
+func EvaluateHand(cards []Card) (HandValue, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, ErrHandSize
+	}
+
+	var seen uint64
+	for _, card := range cards {
+		if card.Rank < Two || card.Rank > Ace || card.Suit > Spades {
+			return 0, fmt.Errorf("%+v is not a card", card)
+		}
+		bit := uint64(1) << (uint(card.Suit)*16 + uint(card.Rank))
+		if seen&bit != 0 {
+			return 0, fmt.Errorf("%v is in the hand twice", card)
+		}
+		seen |= bit
+	}
+
+	return evaluate(cards), nil
+}
+
+// evaluate values any number of cards in one pass over rank and suit masks.
+func evaluate(cards []Card) HandValue {
+	return evaluateWithWheel(cards, wheel, Five)
+}
+
+// evaluateWithWheel values cards with lowest as the ace-low straight, lowestHigh high.
+func evaluateWithWheel(cards []Card, lowest uint16, lowestHigh Rank) HandValue {
+	var counts [Ace + 1]int
+	var suits [Spades + 1]uint16
+	var ranks uint16
+
+	for _, card := range cards {
+		counts[card.Rank]++
+		suits[card.Suit] |= 1 << card.Rank
+		ranks |= 1 << card.Rank
+	}
+
+	var flush uint16
+	for _, suited := range suits {
+		if bits.OnesCount16(suited) < 5 {
+			continue
+		}
+		if high, ok := straightHigh(suited, lowest, lowestHigh); ok {
+			return newHandValue(StraightFlush, high)
+		}
+		flush = suited
+	}
+
+	// group ranks by how many of each there are, highest rank first.
+	var quads, trips, pairs []Rank
+	for rank := Ace; rank >= Two; rank-- {
+		switch counts[rank] {
+		case 4:
+			quads = append(quads, rank)
+		case 3:
+			trips = append(trips, rank)
+		case 2:
+			pairs = append(pairs, rank)
+		}
+	}
+
+	if len(quads) > 0 {
+		return newHandValue(FourOfAKind, quads[0], highest(without(ranks, quads[0]), 1)[0])
+	}
+	if len(trips) > 0 {
+		// the pair is the best of the other trips and the pairs
+		var pair Rank
+		if len(trips) > 1 {
+			pair = trips[1]
+		}
+		if len(pairs) > 0 {
+			pair = max(pair, pairs[0])
+		}
+		if pair != 0 {
+			return newHandValue(FullHouse, trips[0], pair)
+		}
+	}
+
+	if flush != 0 {
+		return newHandValue(Flush, highest(flush, 5)...)
+	}
+
+	if high, ok := straightHigh(ranks, lowest, lowestHigh); ok {
+		return newHandValue(Straight, high)
+	}
+
+	switch {
+	case len(trips) > 0:
+		kickers := highest(without(ranks, trips[0]), 2)
+		return newHandValue(ThreeOfAKind, trips[0], kickers[0], kickers[1])
+	case len(pairs) > 1:
+		kickers := highest(without(without(ranks, pairs[0]), pairs[1]), 1)
+		return newHandValue(TwoPair, pairs[0], pairs[1], kickers[0])
+	case len(pairs) > 0:
+		kickers := highest(without(ranks, pairs[0]), 3)
+		return newHandValue(OnePair, pairs[0], kickers[0], kickers[1], kickers[2])
+	}
+
+	return newHandValue(HighCard, highest(ranks, 5)...)
+}
+
+const wheel = 1<<Ace | 1<<Two | 1<<Three | 1<<Four | 1<<Five
+
+// straightHigh finds the highest straight in a rank mask, the ace also playing low.
+func straightHigh(ranks uint16, lowest uint16, lowestHigh Rank) (Rank, bool) {
+	for high := Ace; high >= Six; high-- {
+		run := uint16(0x1f) << (high - 4)
+		if ranks&run == run {
+			return high, true
+		}
+	}
+	if ranks&lowest == lowest {
+		return lowestHigh, true
+	}
+	return 0, false
+}
+
+// highest lists the n highest ranks in a rank mask.
+func highest(ranks uint16, n int) []Rank {
+	found := make([]Rank, 0, n)
+	for rank := Ace; rank >= Two && len(found) < n; rank-- {
+		if ranks&(1<<rank) != 0 {
+			found = append(found, rank)
+		}
+	}
+	return found
+}
+
+func without(ranks uint16, rank Rank) uint16 {
+	return ranks &^ (1 << rank)
+}
@@ -0,0 +1,239 @@
+package poker
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func mustEvaluate(t testing.TB, cards string) HandValue {
+	t.Helper()
+
+	parsed, err := ParseCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := EvaluateHand(parsed)
+	if err != nil {
+		t.Fatalf("could not evaluate %s: %v", cards, err)
+	}
+	return value
+}
+
+func TestEvaluateHandCategories(t *testing.T) {
+	cases := []struct {
+		cards string
+		want  HandCategory
+		name  string
+	}{
+		{"As Ks Qs Js Ts", StraightFlush, "royal flush"},
+		{"9h 8h 7h 6h 5h", StraightFlush, "straight flush, 9 high"},
+		{"5d 4d 3d 2d Ad", StraightFlush, "straight flush, 5 high"},
+		{"7c 7d 7h 7s Kd", FourOfAKind, "four of a kind, 7s"},
+		{"Kc Kd Kh 7s 7d", FullHouse, "full house, Ks full of 7s"},
+		{"Ah Jh 8h 4h 2h", Flush, "flush, A high"},
+		{"Tc 9d 8h 7s 6c", Straight, "straight, T high"},
+		{"5c 4d 3h 2s Ac", Straight, "straight, 5 high"},
+		{"Qc Qd Qh 9s 2c", ThreeOfAKind, "three of a kind, Qs"},
+		{"Jc Jd 4h 4s Ac", TwoPair, "two pair, Js and 4s"},
+		{"9c 9d Ah 7s 2c", OnePair, "pair of 9s"},
+		{"Ac Jd 8h 4s 2c", HighCard, "high card A"},
+
+		{"As Ks Qs Js Ts 9s 2d", StraightFlush, "royal flush"},
+		{"Kc Kd Kh 7s 7d 7c 2d", FullHouse, "full house, Ks full of 7s"},
+		{"Kc Kd Kh 7s 7d 2c", FullHouse, "full house, Ks full of 7s"},
+		{"Ah Jh 8h 4h 2h Kc Qd", Flush, "flush, A high"},
+		{"Tc 9d 8h 7s 6c 5d 4h", Straight, "straight, T high"},
+		{"Jc Jd 4h 4s 2c 2d Ac", TwoPair, "two pair, Js and 4s"},
+		{"7c 7d 7h 7s Kd Kh Kc", FourOfAKind, "four of a kind, 7s"},
+		{"3h 4h 5h 6h 7h 8d 9c", StraightFlush, "straight flush, 7 high"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.cards, func(t *testing.T) {
+			value := mustEvaluate(t, c.cards)
+
+			if value.Category() != c.want {
+				t.Errorf("got %v want %v", value.Category(), c.want)
+			}
+			if value.String() != c.name {
+				t.Errorf("got %q want %q", value.String(), c.name)
+			}
+		})
+	}
+}
+
+func TestEvaluateHandOrdering(t *testing.T) {
+	// strongest first, each strictly beating the next.
+	ordered := []string{
+		"As Ks Qs Js Ts",
+		"Ks Qs Js Ts 9s",
+		"5d 4d 3d 2d Ad",
+		"Ac Ad Ah As 2d",
+		"Kc Kd Kh Ks Ad",
+		"Kc Kd Kh Ks Qd",
+		"Ac Ad Ah 2s 2d",
+		"Kc Kd Kh As Ad",
+		"Kc Kd Kh Qs Qd",
+		"Ah Kh Qh Jh 9h",
+		"Ah Kh Qh Jh 8h",
+		"Ah 7h 5h 4h 3h",
+		"Kh Qh Jh Th 8h",
+		"Ac Kd Qh Js Tc",
+		"6c 5d 4h 3s 2c",
+		"5c 4d 3h 2s Ac",
+		"Ac Ad Ah Ks Qd",
+		"Ac Ad Ah Ks Jd",
+		"Kc Kd Kh As Qd",
+		"Ac Ad Ks Kd Qh",
+		"Ac Ad Ks Kd Jh",
+		"Ac Ad Qs Qd Kh",
+		"Kc Kd Qs Qd Ah",
+		"Ac Ad Kh Qs Jd",
+		"Ac Ad Kh Qs Td",
+		"Ac Ad Kh Js Td",
+		"Kc Kd Ah Qs Jd",
+		"2c 2d Ah Ks Qd",
+		"Ac Kd Qh Js 9c",
+		"Ac Kd Qh Js 8c",
+		"Ac Kd Qh Ts 9c",
+		"Kc Qd Jh Ts 8c",
+		"7c 5d 4h 3s 2c",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		stronger, weaker := mustEvaluate(t, ordered[i-1]), mustEvaluate(t, ordered[i])
+		if stronger <= weaker {
+			t.Errorf("%s (%v) should beat %s (%v)", ordered[i-1], stronger, ordered[i], weaker)
+		}
+	}
+}
+
+func TestEvaluateHandTies(t *testing.T) {
+	ties := [][2]string{
+		{"As Kd Qh Jc 9s", "Ad Kc Qs Jh 9d"},
+		{"Tc 9d 8h 7s 6c", "Td 9c 8s 7h 6d"},
+		{"Ac Ad Kh Ks 2d", "Ah As Kc Kd 2c"},
+		// the sixth and seventh cards never count.
+		{"Ac Ad Kh Ks Qd 3c 2h", "Ah As Kc Kd Qc 5d 4s"},
+		{"Kc Kd Kh Ks Ad 2c", "Kc Kd Kh Ks Ac Qd"},
+		// a third pair only counts as a kicker.
+		{"Jc Jd 4h 4s 2c 2d Ac", "Jc Jd 4h 4s Ac"},
+		// so does the smaller of two sets.
+		{"Ac Ad Ah Ks Kd Kh 2c", "Ac Ad Ah Ks Kd"},
+	}
+
+	for _, tie := range ties {
+		first, second := mustEvaluate(t, tie[0]), mustEvaluate(t, tie[1])
+		if first != second {
+			t.Errorf("%s (%v) and %s (%v) should tie", tie[0], first, tie[1], second)
+		}
+	}
+}
+
+func TestEvaluateHandRejects(t *testing.T) {
+	cases := map[string]string{
+		"too few cards":  "As Kd Qh Jc",
+		"too many cards": "As Kd Qh Jc 9s 8s 7s 6s",
+		"duplicates":     "As As Qh Jc 9s",
+	}
+
+	for name, cards := range cases {
+		t.Run(name, func(t *testing.T) {
+			parsed, _ := ParseCards(cards)
+			if _, err := EvaluateHand(parsed); err == nil {
+				t.Errorf("expected an error for %s", cards)
+			}
+		})
+	}
+}
+
+// TestEvaluateEveryFiveCardHand checks the evaluator against the well known
+// number of five card hands in each category.
+func TestEvaluateEveryFiveCardHand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("deals all 2,598,960 hands")
+	}
+
+	want := map[HandCategory]int{
+		StraightFlush: 40,
+		FourOfAKind:   624,
+		FullHouse:     3744,
+		Flush:         5108,
+		Straight:      10200,
+		ThreeOfAKind:  54912,
+		TwoPair:       123552,
+		OnePair:       1098240,
+		HighCard:      1302540,
+	}
+
+	deck, _ := NewDeck().Draw(52)
+	got := map[HandCategory]int{}
+	hand := make([]Card, 5)
+
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			for c := b + 1; c < 52; c++ {
+				for d := c + 1; d < 52; d++ {
+					for e := d + 1; e < 52; e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = deck[a], deck[b], deck[c], deck[d], deck[e]
+						got[evaluate(hand).Category()]++
+					}
+				}
+			}
+		}
+	}
+
+	for category, count := range want {
+		if got[category] != count {
+			t.Errorf("got %d hands of %v, want %d", got[category], category, count)
+		}
+	}
+}
+
+// TestEvaluateSevenCardsPlaysTheBestFive checks random seven card hands
+// against the best of their 21 five card hands.
+func TestEvaluateSevenCardsPlaysTheBestFive(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 20000; i++ {
+		deck := NewDeck()
+		deck.Shuffle(rng)
+		cards, _ := deck.Draw(7)
+
+		best := HandValue(0)
+		for skipA := 0; skipA < 7; skipA++ {
+			for skipB := skipA + 1; skipB < 7; skipB++ {
+				var five []Card
+				for j, card := range cards {
+					if j != skipA && j != skipB {
+						five = append(five, card)
+					}
+				}
+				best = max(best, evaluate(five))
+			}
+		}
+
+		if got := evaluate(cards); got != best {
+			t.Fatalf("%s: got %v (%d), best five make %v (%d)", formatCards(cards), got, got, best, best)
+		}
+	}
+}
+
+func benchmarkEvaluate(b *testing.B, size int) {
+	rng := rand.New(rand.NewSource(1))
+	hands := make([][]Card, 1024)
+	for i := range hands {
+		deck := NewDeck()
+		deck.Shuffle(rng)
+		hands[i], _ = deck.Draw(size)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EvaluateHand(hands[i%len(hands)])
+	}
+}
+
+func BenchmarkEvaluateHand5(b *testing.B) { benchmarkEvaluate(b, 5) }
+func BenchmarkEvaluateHand6(b *testing.B) { benchmarkEvaluate(b, 6) }
+func BenchmarkEvaluateHand7(b *testing.B) { benchmarkEvaluate(b, 7) }
@@ -1,16 +1,40 @@
 package poker
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"sort"
+	"sync"
 )
 
+// FileSystemPlayerStore rewrites one JSON file on every change, under a lock.
 type FileSystemPlayerStore struct {
-	database *json.Encoder
-	league   League
+	mu          sync.Mutex
+	file        *os.File
+	database    io.Writer
+	saved       []byte
+	league      League
+	tournaments []TournamentResult
+	cashGames   []CashResult
+	balances    Balances
+	games       []Checkpoint
+	journal     []JournalEntry
+	merged      map[string]string
+	journaling  bool
+}
+
+// storedData is the file; old files hold the league on its own.
+type storedData struct {
+	League      League             `json:"league"`
+	Tournaments []TournamentResult `json:"tournaments,omitempty"`
+	CashGames   []CashResult       `json:"cash_games,omitempty"`
+	Balances    Balances           `json:"balances,omitempty"`
+	Games       []Checkpoint       `json:"games,omitempty"`
+	Journal     []JournalEntry     `json:"journal,omitempty"`
+	Merged      map[string]string  `json:"merged,omitempty"`
 }
 
 func NewFileSystemStore(file *os.File) (*FileSystemPlayerStore, error) {
@@ -19,16 +43,77 @@ func NewFileSystemStore(file *os.File) (*FileSystemPlayerStore, error) {
 		return nil, fmt.Errorf("problem loading playuer store from file %s %v", file.Name(), err)
 	}
 
-	league, err := NewLeague(file)
+	data, err := readStoredData(file)
+	if err != nil {
+		return nil, fmt.Errorf("problem loading player store from file system %v", err)
+	}
+
+	store := &FileSystemPlayerStore{file: file, database: &tape{file}}
+	store.use(data)
+	store.saved, err = json.Marshal(store.data())
+	if err != nil {
+		return nil, fmt.Errorf("problem loading player store from file system %v", err)
+	}
+	return store, nil
+}
+
+// use puts data in place of everything the store holds.
+func (f *FileSystemPlayerStore) use(data storedData) {
+	if data.Balances == nil {
+		data.Balances = Balances{}
+	}
+	f.league, f.tournaments, f.cashGames, f.balances, f.games = data.League, data.Tournaments, data.CashGames, data.Balances, data.Games
+	f.journal, f.merged = data.Journal, data.Merged
+}
+
+func readStoredData(rdr io.Reader) (storedData, error) {
+	content, err := io.ReadAll(rdr)
 	if err != nil {
+		return storedData{}, err
+	}
+
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		league, err := NewLeague(bytes.NewReader(trimmed))
+		return storedData{League: league}, err
+	}
 
-		return nil, fmt.Errorf("problem loading player store from file system")
+	var data storedData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return data, fmt.Errorf("problem parsing the player store %v", err)
 	}
+	return data, nil
+}
 
-	return &FileSystemPlayerStore{
-		database: json.NewEncoder(&tape{file}),
-		league:   league,
-	}, nil
+// save writes the file, going back to what was last saved when it cannot.
+func (f *FileSystemPlayerStore) save() error {
+	data, err := json.Marshal(f.data())
+	if err == nil {
+		_, err = f.database.Write(append(data, '\n'))
+	}
+	if err != nil {
+		if saved, readErr := readStoredData(bytes.NewReader(f.saved)); readErr == nil {
+			f.use(saved)
+		}
+		return fmt.Errorf("problem saving the player store %v", err)
+	}
+	f.saved = data
+	return nil
+}
+
+// Close saves, syncs and closes the file; nothing can be recorded after.
+func (f *FileSystemPlayerStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.save(); err != nil {
+		f.file.Close()
+		return err
+	}
+	if err := f.file.Sync(); err != nil {
+		f.file.Close()
+		return fmt.Errorf("problem saving the player store %v", err)
+	}
+	return f.file.Close()
 }
 
 func initialisePlayerDBFile(file *os.File) error {
@@ -47,6 +132,9 @@ func initialisePlayerDBFile(file *os.File) error {
 }
 
 func (f *FileSystemPlayerStore) GetPlayerScore(playerName string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	player := f.league.Find(playerName)
 	if player != nil {
 		return player.Wins
@@ -54,7 +142,16 @@ func (f *FileSystemPlayerStore) GetPlayerScore(playerName string) int {
 	return 0
 }
 
-func (f *FileSystemPlayerStore) RecordWin(playerName string) {
+func (f *FileSystemPlayerStore) RecordWin(playerName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.addWin(playerName)
+	f.note(JournalEntry{Win: playerName})
+	return f.save()
+}
+
+func (f *FileSystemPlayerStore) addWin(playerName string) {
 	player := f.league.Find(playerName)
 
 	if player != nil {
@@ -62,18 +159,143 @@ func (f *FileSystemPlayerStore) RecordWin(playerName string) {
 	}
 
 	if player == nil {
-		f.league = append(f.league, Player{playerName, 1})
+		f.league = append(f.league, Player{Name: playerName, Wins: 1})
+	}
+}
+
+// RecordTournament keeps the result, and the balances if everyone was named.
+func (f *FileSystemPlayerStore) RecordTournament(result TournamentResult) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.addTournament(result)
+	f.note(JournalEntry{Tournament: &result})
+	return f.save()
+}
+
+func (f *FileSystemPlayerStore) addTournament(result TournamentResult) {
+	f.tournaments = append(f.tournaments, result)
+	f.addWin(result.Winner())
+	if nets := result.Nets(); nets.Total() == 0 {
+		f.balances.Add(nets)
+	}
+}
+
+// Tournaments returns every tournament recorded, oldest first.
+func (f *FileSystemPlayerStore) Tournaments() []TournamentResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]TournamentResult{}, f.tournaments...)
+}
+
+// RecordCashGame keeps the session and adds each net to the league and balances.
+func (f *FileSystemPlayerStore) RecordCashGame(result CashResult) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.addCashGame(result)
+	f.note(JournalEntry{CashGame: &result})
+	return f.save()
+}
+
+func (f *FileSystemPlayerStore) addCashGame(result CashResult) {
+	f.cashGames = append(f.cashGames, result)
+	for _, stake := range result.Players {
+		player := f.league.Find(stake.Player)
+		if player == nil {
+			f.league = append(f.league, Player{Name: stake.Player})
+			player = &f.league[len(f.league)-1]
+		}
+		player.Net += stake.Net
+	}
+	f.balances.Add(result.Nets())
+}
+
+// CashGames returns every cash game recorded, oldest first.
+func (f *FileSystemPlayerStore) CashGames() []CashResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]CashResult{}, f.cashGames...)
+}
+
+// Balances returns what every player is still owed, or owes when negative.
+func (f *FileSystemPlayerStore) Balances() Balances {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	balances := Balances{}
+	balances.Add(f.balances)
+	return balances
+}
+
+// RecordPayment settles payment between two players.
+func (f *FileSystemPlayerStore) RecordPayment(payment Payment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.addPayment(payment); err != nil {
+		return err
 	}
+	f.note(JournalEntry{Payment: &payment})
+	return f.save()
+}
 
-	f.database.Encode(f.league)
+func (f *FileSystemPlayerStore) addPayment(payment Payment) error {
+	if err := payment.Validate(); err != nil {
+		return err
+	}
+	f.balances.Add(payment.settles())
+	return nil
 }
 
 func (f *FileSystemPlayerStore) GetLeague() League {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	sort.Slice(f.league, func(i, j int) bool {
 		return f.league[i].Wins > f.league[j].Wins
 	})
 
-	return f.league
+	return append(League{}, f.league...)
+}
+
+// SaveCheckpoint replaces any earlier checkpoint of the same game.
+func (f *FileSystemPlayerStore) SaveCheckpoint(checkpoint Checkpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, game := range f.games {
+		if game.ID == checkpoint.ID {
+			f.games[i] = checkpoint
+			return f.save()
+		}
+	}
+	f.games = append(f.games, checkpoint)
+	return f.save()
+}
+
+// RemoveCheckpoint forgets a game; one never saved is not an error.
+func (f *FileSystemPlayerStore) RemoveCheckpoint(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, game := range f.games {
+		if game.ID == id {
+			f.games = append(f.games[:i], f.games[i+1:]...)
+			return f.save()
+		}
+	}
+	return nil
+}
+
+// Checkpoints returns the games that were still being played, oldest first.
+func (f *FileSystemPlayerStore) Checkpoints() []Checkpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Checkpoint{}, f.games...)
 }
 
 func FileSystemPlayerStoreFromFile(path string) (*FileSystemPlayerStore, func(), error) {
@@ -83,16 +305,17 @@ func FileSystemPlayerStoreFromFile(path string) (*FileSystemPlayerStore, func(),
 		return nil, nil, fmt.Errorf("problem open %s %v", path, err)
 	}
 
-	closeFunc := func() {
-		db.Close()
-	}
-
 	store, err := NewFileSystemStore(db)
 
 	if err != nil {
+		db.Close()
 		return nil, nil, fmt.Errorf("problem creating file system player store, %v", err)
 
 	}
 
+	closeFunc := func() {
+		store.Close()
+	}
+
 	return store, closeFunc, nil
 }
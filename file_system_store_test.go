@@ -2,6 +2,8 @@ package poker
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -17,8 +19,8 @@ func TestFileSystemStore(t *testing.T) {
 		got := store.league
 
 		want := League{
-			{"Cleo", 10},
-			{"Chris", 33},
+			{Name: "Cleo", Wins: 10},
+			{Name: "Chris", Wins: 33},
 		}
 
 		assertNoError(t, err)
@@ -95,8 +97,8 @@ func TestFileSystemStore(t *testing.T) {
 		got := store.GetLeague()
 
 		want := League{
-			{"Chris", 33},
-			{"Cleo", 10},
+			{Name: "Chris", Wins: 33},
+			{Name: "Cleo", Wins: 10},
 		}
 
 		AssertLeague(t, got, want)
@@ -104,6 +106,175 @@ func TestFileSystemStore(t *testing.T) {
 		got = store.GetLeague()
 		AssertLeague(t, got, want)
 	})
+
+	t.Run("tournaments are kept alongside the league", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Cleo", "Wins": 10}]`)
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		result := TournamentResult{Entrants: 2, PrizePool: 40, Placings: []Placing{
+			{Place: 1, Player: "Cleo", BuyIns: 1, Prize: 40},
+			{Place: 2, Player: "Chris", BuyIns: 1},
+		}}
+		assertNoError(t, store.RecordTournament(result))
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		assertScoreEquals(t, reopened.GetPlayerScore("Cleo"), 11)
+		if got := reopened.Tournaments(); len(got) != 1 || got[0].Winner() != "Cleo" || got[0].PrizePool != 40 {
+			t.Errorf("got tournaments %+v", got)
+		}
+	})
+
+	t.Run("cash games add up each player's net", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Cleo", "Wins": 10}]`)
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		for _, net := range []int{50, 30} {
+			assertNoError(t, store.RecordCashGame(CashResult{Players: []CashStake{
+				{Player: "Cleo", BoughtIn: 100, CashedOut: 100 + net, Net: net},
+				{Player: "Chris", BoughtIn: 100, CashedOut: 100 - net, Net: -net},
+			}}))
+		}
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		want := []Player{
+			{Name: "Cleo", Wins: 10, Net: 80},
+			{Name: "Chris", Wins: 0, Net: -80},
+		}
+		AssertLeague(t, reopened.GetLeague(), want)
+		if got := reopened.CashGames(); len(got) != 2 {
+			t.Errorf("got cash games %+v", got)
+		}
+	})
+
+	t.Run("what is owed carries over until it is paid", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		assertNoError(t, store.RecordCashGame(CashResult{Players: []CashStake{
+			{Player: "Cleo", Net: 50},
+			{Player: "Chris", Net: -50},
+		}}))
+		assertNoError(t, store.RecordTournament(TournamentResult{Placings: []Placing{
+			{Place: 1, Player: "Chris", Paid: 20, Prize: 40},
+			{Place: 2, Player: "Cleo", Paid: 20},
+		}}))
+		assertNoError(t, store.RecordPayment(Payment{From: "Chris", To: "Cleo", Amount: 10}))
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		want := Balances{"Cleo": 20, "Chris": -20}
+		if got := reopened.Balances(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got balances %v want %v", got, want)
+		}
+		if err := reopened.RecordPayment(Payment{From: "Chris", To: "Chris", Amount: 10}); err == nil {
+			t.Error("expected a payment to yourself to be refused")
+		}
+	})
+
+	t.Run("results can be read while more are recorded", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				store.RecordTournament(TournamentResult{Placings: []Placing{{Place: 1, Player: "Cleo"}}})
+				store.RecordCashGame(CashResult{Players: []CashStake{{Player: "Chris", Net: 0}}})
+			}
+		}()
+
+		for i := 0; i < 20; i++ {
+			for _, result := range store.Tournaments() {
+				_ = result.Winner()
+			}
+			for _, result := range store.CashGames() {
+				_ = len(result.Players)
+			}
+		}
+		<-done
+
+		tournaments := store.Tournaments()
+		tournaments[0].Placings = nil
+		if store.Tournaments()[0].Winner() != "Cleo" {
+			t.Error("expected changing what was returned to leave the store alone")
+		}
+	})
+
+	t.Run("the league can be read while wins are recorded", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				store.RecordWin("Cleo")
+				store.RecordWin("Chris")
+			}
+		}()
+
+		for i := 0; i < 20; i++ {
+			for _, player := range store.GetLeague() {
+				_ = player.Wins
+			}
+		}
+		<-done
+
+		league := store.GetLeague()
+		league[0].Wins = 0
+		if store.GetLeague()[0].Wins != 20 {
+			t.Error("expected changing the league returned to leave the store alone")
+		}
+	})
+
+	t.Run("closing saves everything and nothing is kept after", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "game.db.json")
+		store, _, err := FileSystemPlayerStoreFromFile(path)
+		assertNoError(t, err)
+
+		store.RecordWin("Cleo")
+		assertNoError(t, store.Close())
+
+		if err := store.RecordPayment(Payment{From: "Chris", To: "Cleo", Amount: 10}); err == nil {
+			t.Error("expected nothing to be recorded once the store is closed")
+		}
+		if err := store.RecordWin("Cleo"); err == nil {
+			t.Error("expected the win not to be recorded once the store is closed")
+		}
+		assertScoreEquals(t, store.GetPlayerScore("Cleo"), 1)
+		if got := store.Balances(); len(got) != 0 {
+			t.Errorf("got balances %v, want the payment that was not saved undone", got)
+		}
+
+		reopened, close, err := FileSystemPlayerStoreFromFile(path)
+		assertNoError(t, err)
+		defer close()
+		assertScoreEquals(t, reopened.GetPlayerScore("Cleo"), 1)
+		if got := reopened.Balances(); len(got) != 0 {
+			t.Errorf("got balances %v from a payment after closing", got)
+		}
+	})
 }
 
 // Returns a temp file for persisting our data and the method the will do the garbage collection.
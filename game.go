@@ -1,35 +1,420 @@
 package poker
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Game interface {
-	Start(numberOfPlayers int)
+	Start(numberOfPlayers int, alertsDestination io.Writer)
 	Finish(winner string)
 }
 
+// PlayingGame has commands of its own; Play reports whether input was one.
+type PlayingGame interface {
+	Game
+	Play(input string) (handled bool, err error)
+}
+
+// DealingGame deals the cards and runs the betting itself.
+type DealingGame interface {
+	PlayingGame
+	DealCards(startingStack int, seed int64)
+}
+
+const (
+	DefaultStartingStack = 10000
+	DealCommandsHelp     = "deal, fold, check, call, bet <n>, raise <n>, all-in or table"
+)
+
 type TexasHoldem struct {
-	alerter BlindAlerter
-	store   PlayerStore
+	alerter    BlindAlerter
+	store      PlayerStore
+	timeSource Clock
+	clock      *TournamentClock
+	blinds     *BlindStructure
+	variant    Variant
+	names      []string
+	players    int
+	structure  BlindStructure
+
+	dealing       bool
+	startingStack int
+	seed          int64
+	table         *Table
+	out           io.Writer
+
+	rules      *TournamentRules
+	tournament *Tournament
 }
 
-func (p *TexasHoldem) Start(numberOfPlayers int) {
-	blindIncrement := time.Duration(5+numberOfPlayers) * time.Minute
-	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
+func (p *TexasHoldem) Start(numberOfPlayers int, alertsDestination io.Writer) {
+	if p.clock != nil {
+		p.clock.Stop()
+	}
+
+	blinds := p.variant.Blinds(numberOfPlayers)
+	if p.blinds != nil {
+		blinds = *p.blinds
+	}
+
+	p.players, p.structure = numberOfPlayers, blinds
+	p.clock = NewTournamentClock(blinds.Levels, p.alerter, alertsDestination, p.timeSource)
+	p.clock.Start()
+
+	p.table, p.tournament, p.out = nil, nil, alertsDestination
+	if p.rules != nil {
+		p.tournament = NewTournament(*p.rules, numberOfPlayers, p.timeSource, alertsDestination)
+	}
+	if p.dealing {
+		p.seatPlayers(numberOfPlayers)
+	}
+}
+
+func (p *TexasHoldem) seatPlayers(numberOfPlayers int) {
+	players := make([]string, numberOfPlayers)
+	for i := range players {
+		players[i] = "Player " + strconv.Itoa(i+1)
+		if i < len(p.names) {
+			players[i] = p.names[i]
+		}
+	}
+
+	table, err := NewTable(players, p.startingStack, p.seed, p.out)
+	if err != nil {
+		fmt.Fprintln(p.out, err)
+		return
+	}
+	table.Variant = p.variant
+	p.table = table
+
+	if err := p.deal(); err != nil {
+		fmt.Fprintln(p.out, err)
+	}
+}
+
+// Checkpoint is the game as it stands, for Resume.
+func (p *TexasHoldem) Checkpoint() Checkpoint {
+	checkpoint := Checkpoint{Players: p.players, Variant: p.variant.Name, Blinds: p.structure}
+	if p.clock != nil {
+		state := p.clock.State()
+		checkpoint.Level, checkpoint.Remaining, checkpoint.Paused = state.Level, state.Remaining, state.Paused
+	}
+	if p.table != nil {
+		table := p.table.Checkpoint()
+		checkpoint.Table, checkpoint.StartingStack = &table, p.startingStack
+	}
+	if p.tournament != nil {
+		tournament := p.tournament.Checkpoint()
+		checkpoint.Tournament = &tournament
+	}
+	return checkpoint
+}
+
+// Resume carries on a game from its checkpoint, dealing the next hand.
+func (p *TexasHoldem) Resume(checkpoint Checkpoint, alertsDestination io.Writer) error {
+	variant, ok := LookupVariant(checkpoint.Variant)
+	if !ok {
+		return fmt.Errorf("the game was %q, which is not a variant this game deals", checkpoint.Variant)
+	}
+	if err := checkpoint.Blinds.Validate(); err != nil {
+		return err
+	}
+
+	var tournament *Tournament
+	if checkpoint.Tournament != nil {
+		restored, err := RestoreTournament(*checkpoint.Tournament, p.timeSource, alertsDestination)
+		if err != nil {
+			return err
+		}
+		tournament = restored
+	}
+
+	var table *Table
+	if checkpoint.Table != nil {
+		restored, err := RestoreTable(*checkpoint.Table, alertsDestination)
+		if err != nil {
+			return err
+		}
+		table = restored
+		table.Variant = variant
+	}
 
-	blindTime := 0 * time.Second
-	for _, blind := range blinds {
-		p.alerter.ScheduleAlertAt(blindTime, blind)
-		blindTime = blindTime + blindIncrement
+	if p.clock != nil {
+		p.clock.Stop()
+	}
+	p.variant, p.blinds, p.structure, p.players = variant, &checkpoint.Blinds, checkpoint.Blinds, checkpoint.Players
+	p.table, p.tournament, p.out = table, tournament, alertsDestination
+	if tournament != nil {
+		p.rules = &checkpoint.Tournament.Rules
 	}
+	if table != nil {
+		p.dealing, p.startingStack = true, checkpoint.StartingStack
+	}
+
+	fmt.Fprintf(alertsDestination, "Resuming %s for %d players\n", variant, checkpoint.Players)
+	p.clock = NewTournamentClock(checkpoint.Blinds.Levels, p.alerter, alertsDestination, p.timeSource)
+	if err := p.clock.StartAt(checkpoint.Level, checkpoint.Remaining, checkpoint.Paused); err != nil {
+		return err
+	}
+
+	if p.table != nil {
+		if err := p.deal(); err != nil {
+			fmt.Fprintln(p.out, err)
+		}
+	}
+	return nil
 }
 
+// Finish stops the clock and records the win, or the tournament's result.
 func (p *TexasHoldem) Finish(winner string) {
-	p.store.RecordWin(winner)
+	if p.clock != nil {
+		p.clock.Stop()
+	}
+
+	if p.tournament == nil {
+		p.recordWin(winner)
+		return
+	}
+
+	result, err := p.tournament.Finish(winner)
+	if err != nil {
+		fmt.Fprintf(p.out, "%v, recording the win only\n", err)
+		p.recordWin(winner)
+		return
+	}
+
+	for _, placing := range result.Placings {
+		if placing.Prize > 0 {
+			fmt.Fprintf(p.out, "%s %s wins %d\n", Ordinal(placing.Place), placing.Player, placing.Prize)
+		}
+	}
+
+	store, ok := p.store.(TournamentStore)
+	if !ok {
+		p.recordWin(winner)
+		return
+	}
+	if err := store.RecordTournament(result); err != nil {
+		fmt.Fprintf(p.out, "problem saving the tournament %v\n", err)
+	}
+}
+
+func (p *TexasHoldem) recordWin(winner string) {
+	if err := p.store.RecordWin(winner); err != nil {
+		fmt.Fprintf(p.out, "problem saving the win %v\n", err)
+	}
+}
+
+// UsePlayerNames seats names in order instead of numbering the players.
+func (p *TexasHoldem) UsePlayerNames(names []string) {
+	p.names = append([]string{}, names...)
+}
+
+// PlayTournament makes the games that follow tournaments.
+func (p *TexasHoldem) PlayTournament(rules TournamentRules) {
+	p.rules = &rules
+}
+
+// Tournament returns the tournament in progress, or nil.
+func (p *TexasHoldem) Tournament() *Tournament {
+	return p.tournament
+}
+
+// PlayVariant deals variant, with its own blinds unless UseBlinds chose others.
+func (p *TexasHoldem) PlayVariant(variant Variant) {
+	p.variant = variant
+}
+
+// Variant is the variant the game deals.
+func (p *TexasHoldem) Variant() Variant {
+	return p.variant
+}
+
+// UseBlinds replaces the variant's default schedule.
+func (p *TexasHoldem) UseBlinds(structure BlindStructure) {
+	p.blinds = &structure
+}
+
+// Clock returns the clock of the game in progress, or nil before Start.
+func (p *TexasHoldem) Clock() *TournamentClock {
+	return p.clock
+}
+
+// DealCards makes the games that follow deal real hands, shuffled from seed.
+func (p *TexasHoldem) DealCards(startingStack int, seed int64) {
+	p.dealing, p.startingStack, p.seed = true, startingStack, seed
+}
+
+// ClockView counts the players still in the tournament or with chips.
+func (p *TexasHoldem) ClockView() ClockView {
+	view := ClockView{Title: p.variant.Title, Players: p.players}
+	if p.clock != nil {
+		view.State = p.clock.State()
+		view.Next, view.HasNext = p.clock.Next()
+	}
+
+	switch {
+	case p.tournament != nil:
+		view.Players, view.AverageStack = p.tournament.Remaining(), p.tournament.AverageStack()
+	case p.table != nil:
+		view.Players = len(p.table.PlayersWithChips())
+		if view.Players > 0 {
+			view.AverageStack = p.startingStack * len(p.table.Seats) / view.Players
+		}
+	}
+	return view
+}
+
+// Table returns the table in progress, or nil when not dealing.
+func (p *TexasHoldem) Table() *Table {
+	return p.table
+}
+
+// Play runs the dealer's commands and moves, reporting whether input was one.
+func (p *TexasHoldem) Play(input string) (bool, error) {
+	if p.table == nil {
+		return false, nil
+	}
+
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "deal":
+		return true, p.deal()
+	case "table":
+		p.writeTable()
+		return true, nil
+	}
+
+	move, err := ParseMove(input)
+	if err == ErrNotAMove {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	hand := p.table.Hand
+	if hand == nil || hand.Finished() {
+		return true, errors.New("no hand is being played, type deal")
+	}
+	if err := hand.Act(hand.ToAct(), move); err != nil {
+		return true, err
+	}
+	p.eliminateBusted()
+	p.announceWinner()
+	return true, nil
+}
+
+// eliminateBusted knocks out the busted, the shorter stack finishing lower.
+func (p *TexasHoldem) eliminateBusted() {
+	hand := p.table.Hand
+	if p.tournament == nil || !hand.Finished() {
+		return
+	}
+
+	var busted []*Seat
+	for _, seat := range hand.Seats {
+		if seat.Stack == 0 {
+			busted = append(busted, seat)
+		}
+	}
+	sort.SliceStable(busted, func(i, j int) bool { return busted[i].InPot < busted[j].InPot })
+
+	for _, seat := range busted {
+		if _, err := p.tournament.Eliminate(seat.Player); err != nil {
+			fmt.Fprintln(p.out, err)
+		}
+	}
+}
+
+func (p *TexasHoldem) deal() error {
+	_, err := p.table.DealHand(p.clock.State().Blinds)
+	return err
+}
+
+func (p *TexasHoldem) announceWinner() {
+	if !p.table.Hand.Finished() {
+		return
+	}
+	if left := p.table.PlayersWithChips(); len(left) == 1 {
+		fmt.Fprintf(p.out, "%s has all the chips, type \"%s wins\" to record it\n", left[0], left[0])
+	}
+}
+
+func (p *TexasHoldem) writeTable() {
+	hand := p.table.Hand
+	for i, seat := range p.table.Seats {
+		var notes []string
+		if i == p.table.Button && !p.variant.Stud {
+			notes = append(notes, "button")
+		}
+		if hand != nil && !hand.Finished() && seat.Folded {
+			notes = append(notes, "folded")
+		}
+		if hand != nil && !hand.Finished() && seat.AllIn {
+			notes = append(notes, "all-in")
+		}
+
+		line := fmt.Sprintf("Seat %d: %s %d", i+1, seat.Player, seat.Stack)
+		if hand != nil && !seat.Folded && len(seat.Upcards) > 0 {
+			line += " showing " + formatCards(seat.Upcards)
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		fmt.Fprintln(p.out, line)
+	}
+
+	if hand != nil && !hand.Finished() {
+		if len(hand.Board) > 0 || !p.variant.Stud {
+			fmt.Fprintf(p.out, "Board: %s, pot %d\n", formatCards(hand.Board), hand.Pot+hand.bets())
+		} else {
+			fmt.Fprintf(p.out, "Pot %d\n", hand.Pot+hand.bets())
+		}
+
+		if pots := hand.Pots(); len(pots) > 1 {
+			for i, pot := range pots[1:] {
+				fmt.Fprintf(p.out, "Side pot %d: %d\n", i+1, pot.Amount)
+			}
+		}
+	}
+}
+
+// gameCommand offers input to the clock, the tournament and the game in turn.
+func gameCommand(game Game, input string) (bool, error) {
+	if handled, err := controlClock(game, input); handled {
+		return true, err
+	}
+	if handled, err := tournamentCommand(game, input); handled {
+		return true, err
+	}
+	if playing, ok := game.(PlayingGame); ok {
+		return playing.Play(input)
+	}
+	return false, nil
+}
+
+// NewSeed picks a seed for games that do not need to replay their cards.
+func NewSeed() int64 {
+	return time.Now().UnixNano()
 }
 
-func NewTexasHoldem(store PlayerStore, alerter BlindAlerter) *TexasHoldem {
+// NewTexasHoldem needs the same clock as the alerter so levels change together.
+func NewTexasHoldem(store PlayerStore, alerter BlindAlerter, clock Clock) *TexasHoldem {
 	return &TexasHoldem{
-		store:   store,
-		alerter: alerter,
+		store:      store,
+		alerter:    alerter,
+		timeSource: clock,
+		variant:    TexasHoldemVariant,
 	}
 }
@@ -0,0 +1,648 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GameState is where a managed game is in its life.
+type GameState string
+
+const (
+	GamePending  GameState = "pending"
+	GameRunning  GameState = "running"
+	GamePaused   GameState = "paused"
+	GameFinished GameState = "finished"
+)
+
+var (
+	ErrGameNotStarted = errors.New("the game has not started")
+	ErrGameStarted    = errors.New("the game has already started")
+	ErrGameFinished   = errors.New("the game is over")
+	ErrNoWinner       = errors.New("the game needs a winner")
+	ErrSessionOpen    = errors.New("the cash game cannot end yet")
+)
+
+// GameInfo is what anyone can see of a managed game.
+type GameInfo struct {
+	ID       string    `json:"id"`
+	State    GameState `json:"state"`
+	Seats    int       `json:"seats"`
+	Players  []string  `json:"players"`
+	Options  string    `json:"options,omitempty"`
+	Created  time.Time `json:"created"`
+	Blinds   string    `json:"blinds,omitempty"`
+	Winner   string    `json:"winner,omitempty"`
+	Watchers int       `json:"watchers"`
+}
+
+// NamedPlayersGame can seat players by name.
+type NamedPlayersGame interface {
+	Game
+	UsePlayerNames(names []string)
+}
+
+// ManagedGame plays commands one at a time and sends what the game writes to every watcher.
+type ManagedGame struct {
+	ID string
+
+	mu       sync.Mutex
+	game     Game
+	seats    int
+	options  string
+	players  []string
+	state    GameState
+	created  time.Time
+	winner   string
+	watchers *broadcaster
+
+	store  CheckpointStore
+	clock  Clock
+	saving Timer
+
+	// history is the game as it was before each command, for Undo.
+	history []played
+}
+
+// played is a command and the game as it was just before it.
+type played struct {
+	input  string
+	before Checkpoint
+}
+
+// undoLimit is how many commands can be taken back in a row.
+const undoLimit = 20
+
+var ErrNothingToUndo = errors.New("there is nothing to undo")
+
+// Info reports the game as it is now, paused when its clock is.
+func (g *ManagedGame) Info() GameInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info := GameInfo{
+		ID:       g.ID,
+		State:    g.state,
+		Seats:    g.seats,
+		Players:  append([]string{}, g.players...),
+		Options:  g.options,
+		Created:  g.created,
+		Winner:   g.winner,
+		Watchers: g.watchers.count(),
+	}
+
+	if clocked, ok := g.game.(ClockedGame); ok && g.state == GameRunning && clocked.Clock() != nil {
+		state := clocked.Clock().State()
+		info.Blinds = state.Blinds.String()
+		if state.Paused {
+			info.State = GamePaused
+		}
+	}
+	return info
+}
+
+// Join takes a seat for player in a game that has not started yet.
+func (g *ManagedGame) Join(player string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case g.state != GamePending:
+		return ErrGameStarted
+	case player == "":
+		return errors.New("a player needs a name to join")
+	case len(g.players) >= g.seats:
+		return fmt.Errorf("all %d seats are taken", g.seats)
+	}
+	for _, joined := range g.players {
+		if joined == player {
+			return fmt.Errorf("%s has already joined", player)
+		}
+	}
+
+	g.players = append(g.players, player)
+	fmt.Fprintf(g.watchers, "%s joins the game\n", player)
+	return nil
+}
+
+// Start deals the game in, seating whoever has joined by name.
+func (g *ManagedGame) Start() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state != GamePending {
+		return ErrGameStarted
+	}
+	if named, ok := g.game.(NamedPlayersGame); ok && len(g.players) > 0 {
+		named.UsePlayerNames(g.players)
+	}
+
+	g.state = GameRunning
+	g.game.Start(g.seats, g.watchers)
+	g.keepSaving()
+	return nil
+}
+
+// Play runs input if the game knows it, reporting whether it did.
+func (g *ManagedGame) Play(input string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.play(input)
+}
+
+// Command plays input, finishing on "winner <name>" or "end" for a cash game.
+func (g *ManagedGame) Command(input string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	handled, err := g.play(input)
+	if handled || errors.Is(err, ErrGameNotStarted) || errors.Is(err, ErrGameFinished) {
+		return err
+	}
+
+	input = strings.TrimSpace(input)
+	if isCashGame(g.game) && strings.EqualFold(input, "end") {
+		g.finish("")
+		return nil
+	}
+
+	verb, winner, _ := strings.Cut(input, " ")
+	if !strings.EqualFold(verb, "winner") {
+		return fmt.Errorf("%q is not a command, type winner and their name to finish the game", input)
+	}
+	winner = strings.TrimSpace(winner)
+	if err := g.checkWinner(winner); err != nil {
+		return err
+	}
+	g.finish(winner)
+	return nil
+}
+
+func (g *ManagedGame) play(input string) (bool, error) {
+	switch g.state {
+	case GamePending:
+		return false, ErrGameNotStarted
+	case GameFinished:
+		return false, ErrGameFinished
+	}
+
+	resumable, undoable := g.game.(ResumableGame)
+	var before Checkpoint
+	if undoable {
+		before = resumable.Checkpoint()
+	}
+
+	handled, err := gameCommand(g.game, input)
+	if handled && err == nil && undoable {
+		g.history = append(g.history, played{input: input, before: before})
+		if len(g.history) > undoLimit {
+			g.history = g.history[1:]
+		}
+	}
+	if handled {
+		g.checkpoint()
+	}
+	return handled, err
+}
+
+// Undo takes back the last command, redealing any hand being played.
+func (g *ManagedGame) Undo() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case GamePending:
+		return "", ErrGameNotStarted
+	case GameFinished:
+		return "", ErrGameFinished
+	}
+	resumable, ok := g.game.(ResumableGame)
+	if !ok || len(g.history) == 0 {
+		return "", ErrNothingToUndo
+	}
+
+	last := g.history[len(g.history)-1]
+	checkpoint := last.before
+	if !isClockCommand(last.input) {
+		now := resumable.Checkpoint()
+		checkpoint.Level, checkpoint.Remaining, checkpoint.Paused = now.Level, now.Remaining, now.Paused
+	}
+
+	if err := resumable.Resume(checkpoint, g.watchers); err != nil {
+		return "", err
+	}
+	g.history = g.history[:len(g.history)-1]
+	g.checkpoint()
+	return last.input, nil
+}
+
+// End finishes a running game with winner.
+func (g *ManagedGame) End(winner string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case GamePending:
+		return ErrGameNotStarted
+	case GameFinished:
+		return ErrGameFinished
+	}
+	if err := g.checkWinner(winner); err != nil {
+		return err
+	}
+
+	g.finish(winner)
+	return nil
+}
+
+// checkWinner refuses blank or unseated winners, and cash games that do not balance.
+func (g *ManagedGame) checkWinner(winner string) error {
+	if cash, ok := g.game.(*CashGame); ok {
+		if err := cash.Balanced(); err != nil {
+			return fmt.Errorf("%w, %v", ErrSessionOpen, err)
+		}
+		return nil
+	}
+	if strings.TrimSpace(winner) == "" {
+		return ErrNoWinner
+	}
+	if tournamentGame, ok := g.game.(TournamentGame); ok && tournamentGame.Tournament() != nil {
+		if err := tournamentGame.Tournament().canFinish(winner); err != nil {
+			return err
+		}
+	}
+	if len(g.players) == 0 {
+		return nil
+	}
+
+	for _, player := range g.players {
+		if player == winner {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not playing, the players are %s", winner, strings.Join(g.players, ", "))
+}
+
+func (g *ManagedGame) finish(winner string) {
+	g.game.Finish(winner)
+	g.state, g.winner = GameFinished, winner
+	g.forget()
+}
+
+// Abandon stops a game nobody will finish, keeping no result.
+func (g *ManagedGame) Abandon() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state != GameFinished {
+		stopClock(g.game)
+		g.state = GameFinished
+		g.forget()
+	}
+}
+
+// keepSaving checkpoints the game every CheckpointInterval while it can.
+func (g *ManagedGame) keepSaving() {
+	if g.store == nil || g.state != GameRunning {
+		return
+	}
+	if _, ok := g.game.(ResumableGame); !ok {
+		return
+	}
+
+	g.checkpoint()
+	g.saving = g.clock.AfterFunc(CheckpointInterval, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.keepSaving()
+	})
+}
+
+func (g *ManagedGame) checkpoint() {
+	resumable, ok := g.game.(ResumableGame)
+	if g.store == nil || !ok || g.state != GameRunning {
+		return
+	}
+
+	checkpoint := resumable.Checkpoint()
+	checkpoint.ID, checkpoint.Options, checkpoint.Saved = g.ID, g.options, g.clock.Now()
+	if err := g.store.SaveCheckpoint(checkpoint); err != nil {
+		fmt.Fprintf(g.watchers, "problem saving the game %v\n", err)
+	}
+}
+
+// forget stops saving a game that is over and throws its checkpoint away.
+func (g *ManagedGame) forget() {
+	if g.saving != nil {
+		g.saving.Stop()
+		g.saving = nil
+	}
+	if g.store == nil {
+		return
+	}
+	if err := g.store.RemoveCheckpoint(g.ID); err != nil {
+		fmt.Fprintf(g.watchers, "problem forgetting the saved game %v\n", err)
+	}
+}
+
+// ClockView is what to show on the clock screen.
+func (g *ManagedGame) ClockView() (ClockView, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case GamePending:
+		return ClockView{}, ErrGameNotStarted
+	case GameFinished:
+		return ClockView{}, ErrGameFinished
+	}
+	screen, ok := g.game.(ScreenGame)
+	if !ok || screen.Clock() == nil {
+		return ClockView{}, errors.New("this game has no clock to show")
+	}
+	return screen.ClockView(), nil
+}
+
+// Suspend saves and stops the game, keeping its checkpoint to resume.
+func (g *ManagedGame) Suspend() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state == GameFinished {
+		return
+	}
+	g.checkpoint()
+	if g.saving != nil {
+		g.saving.Stop()
+		g.saving = nil
+	}
+	stopClock(g.game)
+	g.state = GameFinished
+}
+
+func (g *ManagedGame) Finished() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state == GameFinished
+}
+
+// Subscribe sends w the recent backlog and then everything written; call the result to stop.
+func (g *ManagedGame) Subscribe(w io.Writer) func() {
+	return g.watchers.add(w)
+}
+
+// broadcasterBacklog is how many messages a latecomer is sent.
+const broadcasterBacklog = 50
+
+// broadcaster writes every message to all its subscribers.
+type broadcaster struct {
+	mu     sync.Mutex
+	to     map[int]io.Writer
+	next   int
+	recent [][]byte
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{to: map[int]io.Writer{}}
+}
+
+// Write sends p to everyone, dropping and closing subscribers whose write fails.
+func (b *broadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	message := append([]byte{}, p...)
+	b.recent = append(b.recent, message)
+	if len(b.recent) > broadcasterBacklog {
+		b.recent = b.recent[1:]
+	}
+
+	for id, w := range b.to {
+		if _, err := w.Write(message); err != nil {
+			delete(b.to, id)
+			drop(w)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *broadcaster) add(w io.Writer) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, message := range b.recent {
+		if _, err := w.Write(message); err != nil {
+			drop(w)
+			return func() {}
+		}
+	}
+
+	id := b.next
+	b.next++
+	b.to[id] = w
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.to, id)
+	}
+}
+
+// drop closes w if it can, so its reader knows it was dropped.
+func drop(w io.Writer) {
+	if closer, ok := w.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (b *broadcaster) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.to)
+}
+
+// finishedGamesKept is how many finished games are kept; older ones are forgotten.
+const finishedGamesKept = 20
+
+// GameManager keeps track of every game the server is running.
+type GameManager struct {
+	mu    sync.Mutex
+	games map[string]*ManagedGame
+	next  int
+	clock Clock
+	store CheckpointStore
+}
+
+func NewGameManager(clock Clock) *GameManager {
+	return &GameManager{games: map[string]*ManagedGame{}, clock: clock}
+}
+
+// KeepCheckpoints saves the games that follow to store as they are played.
+func (m *GameManager) KeepCheckpoints(store CheckpointStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store = store
+	for _, checkpoint := range store.Checkpoints() {
+		m.skipPast(checkpoint.ID)
+	}
+}
+
+// Checkpoints are the unfinished games that could be resumed.
+func (m *GameManager) Checkpoints() []Checkpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store == nil {
+		return nil
+	}
+	return m.store.Checkpoints()
+}
+
+// Discard throws away the checkpoint of a game nobody wants to resume.
+func (m *GameManager) Discard(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store == nil {
+		return nil
+	}
+	return m.store.RemoveCheckpoint(id)
+}
+
+// Create takes charge of game for seats players, pending until started.
+func (m *GameManager) Create(game Game, seats int, options string) *ManagedGame {
+	m.mu.Lock()
+
+	m.next++
+	managed := m.add(strconv.Itoa(m.next), game, seats, options)
+	managed.state = GamePending
+	m.mu.Unlock()
+
+	m.forgetFinished()
+	return managed
+}
+
+// Resume plays checkpoint's game with game, under the same ID.
+func (m *GameManager) Resume(checkpoint Checkpoint, game Game) (*ManagedGame, error) {
+	resumable, ok := game.(ResumableGame)
+	if !ok {
+		return nil, errors.New("this game cannot be resumed")
+	}
+
+	m.mu.Lock()
+	if _, ok := m.games[checkpoint.ID]; ok {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("game %s is already being played", checkpoint.ID)
+	}
+	m.skipPast(checkpoint.ID)
+	managed := m.add(checkpoint.ID, game, checkpoint.Players, checkpoint.Options)
+	m.mu.Unlock()
+
+	managed.mu.Lock()
+	defer managed.mu.Unlock()
+
+	if err := resumable.Resume(checkpoint, managed.watchers); err != nil {
+		m.mu.Lock()
+		delete(m.games, checkpoint.ID)
+		m.mu.Unlock()
+		return nil, fmt.Errorf("could not resume game %s, %v", checkpoint.ID, err)
+	}
+	managed.state = GameRunning
+	managed.keepSaving()
+	return managed, nil
+}
+
+func (m *GameManager) add(id string, game Game, seats int, options string) *ManagedGame {
+	managed := &ManagedGame{
+		ID:       id,
+		game:     game,
+		seats:    seats,
+		options:  options,
+		created:  m.clock.Now(),
+		watchers: newBroadcaster(),
+		store:    m.store,
+		clock:    m.clock,
+	}
+	m.games[id] = managed
+	return managed
+}
+
+// skipPast makes sure new games are not given id, or one before it.
+func (m *GameManager) skipPast(id string) {
+	if n, err := strconv.Atoi(id); err == nil {
+		m.next = max(m.next, n)
+	}
+}
+
+// SuspendAll suspends every game still being played.
+func (m *GameManager) SuspendAll() {
+	m.mu.Lock()
+	games := make([]*ManagedGame, 0, len(m.games))
+	for _, game := range m.games {
+		games = append(games, game)
+	}
+	m.mu.Unlock()
+
+	for _, game := range games {
+		game.Suspend()
+	}
+}
+
+// forgetFinished drops all but the newest finishedGamesKept finished games.
+func (m *GameManager) forgetFinished() {
+	var finished []string
+	for _, info := range m.List() {
+		if info.State == GameFinished {
+			finished = append(finished, info.ID)
+		}
+	}
+	if len(finished) <= finishedGamesKept {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range finished[:len(finished)-finishedGamesKept] {
+		delete(m.games, id)
+	}
+}
+
+func (m *GameManager) Get(id string) (*ManagedGame, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	game, ok := m.games[id]
+	return game, ok
+}
+
+// List returns every game, oldest first.
+func (m *GameManager) List() []GameInfo {
+	m.mu.Lock()
+	games := make([]*ManagedGame, 0, len(m.games))
+	for _, game := range m.games {
+		games = append(games, game)
+	}
+	m.mu.Unlock()
+
+	infos := make([]GameInfo, len(games))
+	for i, game := range games {
+		infos[i] = game.Info()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		a, _ := strconv.Atoi(infos[i].ID)
+		b, _ := strconv.Atoi(infos[j].ID)
+		if a != b {
+			return a < b
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
@@ -0,0 +1,221 @@
+package poker
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGameManager(t *testing.T) {
+	t.Run("games get their own ids and are listed oldest first", func(t *testing.T) {
+		manager := NewGameManager(NewManualClock(time.Time{}))
+
+		first := manager.Create(&GameSpy{}, 3, "")
+		second := manager.Create(&GameSpy{}, 5, "turbo")
+
+		if first.ID == second.ID {
+			t.Fatalf("both games got id %s", first.ID)
+		}
+		got, ok := manager.Get(second.ID)
+		if !ok || got != second {
+			t.Errorf("could not get game %s back", second.ID)
+		}
+
+		games := manager.List()
+		if len(games) != 2 || games[0].ID != first.ID || games[1].Options != "turbo" {
+			t.Errorf("got games %+v", games)
+		}
+	})
+
+	t.Run("only the newest finished games are kept", func(t *testing.T) {
+		manager := NewGameManager(NewManualClock(time.Time{}))
+
+		running := manager.Create(&GameSpy{}, 3, "")
+		running.Start()
+
+		var finished []*ManagedGame
+		for range finishedGamesKept + 2 {
+			game := manager.Create(&GameSpy{}, 3, "")
+			game.Start()
+			game.End("Ruth")
+			finished = append(finished, game)
+		}
+		abandoned := manager.Create(&GameSpy{}, 3, "")
+		abandoned.Abandon()
+		manager.Create(&GameSpy{}, 3, "")
+
+		for _, game := range []*ManagedGame{finished[0], finished[1], finished[2]} {
+			if _, ok := manager.Get(game.ID); ok {
+				t.Errorf("game %s finished long ago but is still kept", game.ID)
+			}
+		}
+		for _, game := range []*ManagedGame{running, finished[3], abandoned} {
+			if _, ok := manager.Get(game.ID); !ok {
+				t.Errorf("game %s was forgotten", game.ID)
+			}
+		}
+		if got := len(manager.List()); got != finishedGamesKept+2 {
+			t.Errorf("got %d games want %d finished, one running and one pending", got, finishedGamesKept)
+		}
+	})
+
+	t.Run("a game is pending until it starts and finished when it ends", func(t *testing.T) {
+		spy := &GameSpy{}
+		game := NewGameManager(NewManualClock(time.Time{})).Create(spy, 3, "")
+
+		assertGameState(t, game, GamePending)
+		if err := game.Command("winner Ruth"); !errors.Is(err, ErrGameNotStarted) {
+			t.Errorf("got %v playing a game that has not started", err)
+		}
+
+		game.Start()
+		assertGameState(t, game, GameRunning)
+		if spy.startedWith() != 3 {
+			t.Errorf("started with %d players, want 3", spy.startedWith())
+		}
+
+		if err := game.End("Ruth"); err != nil {
+			t.Fatal(err)
+		}
+		assertGameState(t, game, GameFinished)
+		if spy.finishedWith() != "Ruth" || game.Info().Winner != "Ruth" {
+			t.Errorf("got winner %q", spy.finishedWith())
+		}
+		if err := game.End("Chris"); !errors.Is(err, ErrGameFinished) {
+			t.Errorf("got %v ending a game twice", err)
+		}
+	})
+
+	t.Run("a game only ends on a winner who is playing", func(t *testing.T) {
+		spy := &GameSpy{}
+		game := NewGameManager(NewManualClock(time.Time{})).Create(spy, 2, "")
+		game.Join("Ruth")
+		game.Join("Cleo")
+		game.Start()
+
+		for _, input := range []string{"hello", "winner", "winner Chris"} {
+			if err := game.Command(input); err == nil {
+				t.Errorf("%q was taken as a command", input)
+			}
+		}
+		if err := game.End(" "); !errors.Is(err, ErrNoWinner) {
+			t.Errorf("got %v ending with no winner", err)
+		}
+		assertGameState(t, game, GameRunning)
+
+		if err := game.Command("winner Cleo"); err != nil {
+			t.Fatal(err)
+		}
+		assertGameState(t, game, GameFinished)
+		if spy.finishedWith() != "Cleo" {
+			t.Errorf("got winner %q want Cleo", spy.finishedWith())
+		}
+	})
+
+	t.Run("a running game is paused while its clock is", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		game := NewGameManager(clock).Create(NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, clock), 3, "")
+		game.Start()
+
+		game.Command("pause")
+		assertGameState(t, game, GamePaused)
+
+		game.Command("resume")
+		assertGameState(t, game, GameRunning)
+	})
+
+	t.Run("players join before the game starts and are seated by name", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		holdem := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, clock)
+		holdem.DealCards(DefaultStartingStack, 1)
+		game := NewGameManager(clock).Create(holdem, 2, "deal")
+
+		for _, name := range []string{"Chris", "Cleo"} {
+			if err := game.Join(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := game.Join("Ruth"); err == nil {
+			t.Error("expected the third player to be refused two seats")
+		}
+
+		game.Start()
+		if got := holdem.Table().PlayersWithChips(); !reflect.DeepEqual(got, []string{"Chris", "Cleo"}) {
+			t.Errorf("got players %v", got)
+		}
+		if err := game.Join("Ruth"); !errors.Is(err, ErrGameStarted) {
+			t.Errorf("got %v joining a game under way", err)
+		}
+	})
+
+	t.Run("everyone watching sees the game, latecomers included", func(t *testing.T) {
+		game := NewGameManager(NewManualClock(time.Time{})).Create(&GameSpy{BlindAlert: []byte("Blinds are now 50/100\n")}, 3, "")
+
+		var early, gone, late bytes.Buffer
+		game.Subscribe(&early)
+		stop := game.Subscribe(&gone)
+		stop()
+
+		game.Join("Ruth")
+		game.Start()
+		game.Subscribe(&late)
+
+		want := "Ruth joins the game\nBlinds are now 50/100\n"
+		if early.String() != want || late.String() != want {
+			t.Errorf("got %q and %q want %q", early.String(), late.String(), want)
+		}
+		if gone.Len() != 0 {
+			t.Errorf("an unsubscribed watcher was written %q", gone.String())
+		}
+		if game.Info().Watchers != 2 {
+			t.Errorf("got %d watchers want 2", game.Info().Watchers)
+		}
+	})
+
+	t.Run("a watcher that cannot be written to is dropped and closed", func(t *testing.T) {
+		game := NewGameManager(NewManualClock(time.Time{})).Create(&GameSpy{}, 3, "")
+
+		var watching bytes.Buffer
+		stuck := &stuckWatcher{}
+		game.Subscribe(&watching)
+		game.Subscribe(stuck)
+
+		game.Join("Ruth")
+		game.Join("Cleo")
+
+		if watching.String() != "Ruth joins the game\nCleo joins the game\n" {
+			t.Errorf("got %q", watching.String())
+		}
+		if stuck.writes != 1 || !stuck.closed {
+			t.Errorf("the stuck watcher was written %d times and closed %v, want once and closed", stuck.writes, stuck.closed)
+		}
+		if game.Info().Watchers != 1 {
+			t.Errorf("got %d watchers want 1", game.Info().Watchers)
+		}
+	})
+}
+
+func assertGameState(t testing.TB, game *ManagedGame, want GameState) {
+	t.Helper()
+	if got := game.Info().State; got != want {
+		t.Errorf("got state %s want %s", got, want)
+	}
+}
+
+// stuckWatcher fails every write, as a browser past its write deadline does.
+type stuckWatcher struct {
+	writes int
+	closed bool
+}
+
+func (s *stuckWatcher) Write(p []byte) (int, error) {
+	s.writes++
+	return 0, errors.New("write deadline exceeded")
+}
+
+func (s *stuckWatcher) Close() error {
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,703 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Action int
+
+const (
+	Fold Action = iota
+	Check
+	Call
+	Bet
+	Raise
+	AllIn
+)
+
+var actionNames = []string{"fold", "check", "call", "bet", "raise", "all-in"}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// Move is a player's turn. Amount is the total bet on the street: "raise 600" raises to 600.
+type Move struct {
+	Action Action
+	Amount int
+}
+
+var ErrNotAMove = errors.New("not a move")
+
+// ParseMove reads "fold", "call", "bet 200", "raise 600" or "all-in", else ErrNotAMove.
+func ParseMove(input string) (Move, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) == 0 {
+		return Move{}, ErrNotAMove
+	}
+
+	verb := fields[0]
+	if verb == "all" && len(fields) == 2 && fields[1] == "in" {
+		verb, fields = "all-in", fields[:1]
+	}
+
+	switch verb {
+	case "fold", "check", "call":
+		if len(fields) != 1 {
+			return Move{}, fmt.Errorf("%s does not take an amount", verb)
+		}
+		return Move{Action: Action(indexOf(actionNames, verb))}, nil
+	case "all-in", "allin":
+		if len(fields) != 1 {
+			return Move{}, fmt.Errorf("%s does not take an amount", verb)
+		}
+		return Move{Action: AllIn}, nil
+	case "bet", "raise":
+		if len(fields) != 2 {
+			return Move{}, fmt.Errorf("%s needs an amount, e.g. %s 200", verb, verb)
+		}
+		amount, err := strconv.Atoi(fields[1])
+		if err != nil || amount <= 0 {
+			return Move{}, fmt.Errorf("%q is not an amount to %s", fields[1], verb)
+		}
+		return Move{Action: Action(indexOf(actionNames, verb)), Amount: amount}, nil
+	}
+
+	return Move{}, ErrNotAMove
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type Street int
+
+const (
+	Preflop Street = iota
+	Flop
+	Turn
+	River
+	Showdown
+	ThirdStreet
+	FourthStreet
+	FifthStreet
+	SixthStreet
+	SeventhStreet
+)
+
+var (
+	streetNames = []string{
+		"preflop", "flop", "turn", "river", "showdown",
+		"third street", "fourth street", "fifth street", "sixth street", "seventh street",
+	}
+	streetTitles = []string{
+		"Preflop", "Flop", "Turn", "River", "Showdown",
+		"Third street", "Fourth street", "Fifth street", "Sixth street", "Seventh street",
+	}
+
+	boardStreets = []Street{Preflop, Flop, Turn, River}
+	studStreets  = []Street{ThirdStreet, FourthStreet, FifthStreet, SixthStreet, SeventhStreet}
+)
+
+func (s Street) String() string {
+	return streetNames[s]
+}
+
+// Seat is a player at the table and what they have done in the current hand.
+type Seat struct {
+	Player string
+	Stack  int
+	Cards  []Card
+
+	// Upcards are a stud player's face up cards, also in Cards.
+	Upcards []Card
+
+	// Bet is in front of the player this street; InPot is all they put in this hand.
+	Bet    int
+	InPot  int
+	Folded bool
+	AllIn  bool
+}
+
+// Wager is a move and what it cost; Forced ones are antes and blinds.
+type Wager struct {
+	Street Street
+	Player string
+	Action Action
+	Chips  int
+	Forced bool
+}
+
+// Wagered is what went in on a street, uncalled bets included.
+func (h *Hand) Wagered(street Street) int {
+	total := 0
+	for _, w := range h.Wagers {
+		if w.Street == street {
+			total += w.Chips
+		}
+	}
+	return total
+}
+
+func (s *Seat) canAct() bool {
+	return !s.Folded && !s.AllIn
+}
+
+// Hand is one deal. An all-in for less than a full raise does not reopen the betting.
+type Hand struct {
+	Number int
+	Seats  []*Seat
+	Button int
+	Blinds BlindLevel
+	Board  []Card
+	Street Street
+	Pot    int
+
+	// Wagers is every bet of the hand in order, blinds and antes included.
+	Wagers []Wager
+
+	variant    Variant
+	streets    []Street
+	deck       *Deck
+	out        io.Writer
+	toAct      int
+	currentBet int
+	minRaise   int
+	acted      []bool
+	finished   bool
+}
+
+func newHand(number int, variant Variant, seats []*Seat, button int, blinds BlindLevel, deck *Deck, out io.Writer) (*Hand, error) {
+	h := &Hand{
+		Number:   number,
+		Seats:    seats,
+		Button:   button,
+		Blinds:   blinds,
+		variant:  variant,
+		streets:  boardStreets,
+		deck:     deck,
+		out:      out,
+		minRaise: blinds.BigBlind,
+		acted:    make([]bool, len(seats)),
+	}
+	if variant.Stud {
+		h.streets = studStreets
+	}
+	h.Street = h.streets[0]
+
+	for _, seat := range seats {
+		seat.Cards, seat.Upcards, seat.Bet, seat.InPot, seat.Folded, seat.AllIn = nil, nil, 0, 0, false, false
+	}
+
+	if variant.Stud {
+		fmt.Fprintf(out, "Hand #%d of %s: antes %d, bring-in %d\n", number, variant, blinds.Ante, blinds.SmallBlind)
+	} else {
+		fmt.Fprintf(out, "Hand #%d of %s: %s has the button, blinds %v\n", number, variant, seats[button].Player, blinds)
+	}
+
+	if blinds.Ante > 0 {
+		for _, seat := range seats {
+			ante := h.put(seat, min(blinds.Ante, seat.Stack))
+			h.Pot += ante
+			h.Wagers = append(h.Wagers, Wager{h.Street, seat.Player, Bet, ante, true})
+		}
+		fmt.Fprintf(out, "Everyone posts an ante of %d\n", blinds.Ante)
+	}
+
+	if variant.Stud {
+		return h, h.startStud()
+	}
+
+	smallBlind, bigBlind := h.blindSeats()
+	h.postBlind(smallBlind, blinds.SmallBlind, "small blind")
+	h.postBlind(bigBlind, blinds.BigBlind, "big blind")
+	h.currentBet = max(seats[smallBlind].Bet, seats[bigBlind].Bet)
+
+	if err := h.dealHoleCards(variant.HoleCards); err != nil {
+		return nil, err
+	}
+
+	h.toAct = bigBlind
+	h.moveOn()
+
+	return h, nil
+}
+
+// startStud deals two down and one up, and the lowest card showing brings it in.
+func (h *Hand) startStud() error {
+	for round := 0; round < 3; round++ {
+		if err := h.dealRound(round == 2); err != nil {
+			return err
+		}
+	}
+	for _, seat := range h.Seats {
+		fmt.Fprintf(h.out, "Dealt to %s: %s, showing %s\n", seat.Player, formatCards(seat.Cards[:2]), formatCards(seat.Upcards))
+	}
+
+	seat, err := bringIn(h.Seats)
+	if err != nil {
+		return err
+	}
+	h.postBlind(seat, h.Blinds.SmallBlind, "the bring-in")
+	h.currentBet = h.Seats[seat].Bet
+	h.minRaise = max(h.Blinds.BigBlind-h.currentBet, 1)
+	h.acted[seat] = true
+
+	h.toAct = seat
+	h.moveOn()
+	return nil
+}
+
+// dealRound deals one card to everyone still in, left of the button first.
+func (h *Hand) dealRound(faceUp bool) error {
+	for i := range h.Seats {
+		seat := h.Seats[h.next(h.Button+i)]
+		if seat.Folded {
+			continue
+		}
+		card, err := h.deck.Draw(1)
+		if err != nil {
+			return err
+		}
+		seat.Cards = append(seat.Cards, card...)
+		if faceUp {
+			seat.Upcards = append(seat.Upcards, card...)
+		}
+	}
+	return nil
+}
+
+// blindSeats follows the heads-up rule that the button posts the small blind.
+func (h *Hand) blindSeats() (int, int) {
+	if len(h.Seats) == 2 {
+		return h.Button, h.next(h.Button)
+	}
+	smallBlind := h.next(h.Button)
+	return smallBlind, h.next(smallBlind)
+}
+
+func (h *Hand) postBlind(seat int, amount int, name string) {
+	s := h.Seats[seat]
+	posted := h.put(s, min(amount, s.Stack))
+	s.Bet += posted
+	h.Wagers = append(h.Wagers, Wager{h.Street, s.Player, Bet, posted, true})
+	fmt.Fprintf(h.out, "%s posts %s %d%s\n", s.Player, name, posted, allInNote(s))
+}
+
+// put moves chips from a player's stack towards the pot.
+func (h *Hand) put(s *Seat, amount int) int {
+	s.Stack -= amount
+	s.InPot += amount
+	if s.Stack == 0 {
+		s.AllIn = true
+	}
+	return amount
+}
+
+func (h *Hand) dealHoleCards(count int) error {
+	for round := 0; round < count; round++ {
+		for i := range h.Seats {
+			seat := h.Seats[h.next(h.Button+i)]
+			card, err := h.deck.Draw(1)
+			if err != nil {
+				return err
+			}
+			seat.Cards = append(seat.Cards, card...)
+		}
+	}
+
+	for _, seat := range h.Seats {
+		fmt.Fprintf(h.out, "Dealt to %s: %s\n", seat.Player, formatCards(seat.Cards))
+	}
+	return nil
+}
+
+func (h *Hand) next(seat int) int {
+	return (seat + 1) % len(h.Seats)
+}
+
+// ToAct is the seat whose turn it is, or -1 when nobody can act.
+func (h *Hand) ToAct() int {
+	if h.finished || h.Street == Showdown {
+		return -1
+	}
+	return h.toAct
+}
+
+func (h *Hand) Finished() bool {
+	return h.finished
+}
+
+// ToCall is what the player to act needs to put in to stay in the hand.
+func (h *Hand) ToCall() int {
+	if h.ToAct() < 0 {
+		return 0
+	}
+	s := h.Seats[h.toAct]
+	return min(h.currentBet-s.Bet, s.Stack)
+}
+
+// Act plays move for the given seat, which must be the one to act.
+func (h *Hand) Act(seat int, move Move) error {
+	if h.finished {
+		return errors.New("the hand is over")
+	}
+	if h.Street == Showdown {
+		return errors.New("the hand is waiting for the showdown")
+	}
+	if seat != h.toAct {
+		return fmt.Errorf("it is %s's turn", h.Seats[h.toAct].Player)
+	}
+
+	s := h.Seats[seat]
+	toCall := h.currentBet - s.Bet
+	before := s.InPot
+
+	switch move.Action {
+	case Fold:
+		s.Folded = true
+		fmt.Fprintf(h.out, "%s folds\n", s.Player)
+	case Check:
+		if toCall > 0 {
+			return fmt.Errorf("%s cannot check, it is %d to call", s.Player, toCall)
+		}
+		fmt.Fprintf(h.out, "%s checks\n", s.Player)
+	case Call:
+		if toCall == 0 {
+			return fmt.Errorf("there is nothing for %s to call, check instead", s.Player)
+		}
+		h.call(s, toCall)
+	case Bet:
+		if h.currentBet > 0 {
+			return fmt.Errorf("there is already a bet of %d, raise instead", h.currentBet)
+		}
+		if err := h.raiseTo(seat, move.Amount); err != nil {
+			return err
+		}
+	case Raise:
+		if h.currentBet == 0 {
+			return errors.New("there is no bet to raise, bet instead")
+		}
+		// going all in for no more than the bet is only a call
+		if move.Amount == s.Bet+s.Stack && move.Amount <= h.currentBet {
+			move.Action = Call
+			h.call(s, toCall)
+			break
+		}
+		if err := h.raiseTo(seat, move.Amount); err != nil {
+			return err
+		}
+	case AllIn:
+		if s.Bet+s.Stack <= h.currentBet || h.acted[seat] {
+			h.call(s, toCall)
+			break
+		}
+		if err := h.raiseTo(seat, h.most(s)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%v is not a move", move.Action)
+	}
+
+	h.Wagers = append(h.Wagers, Wager{h.Street, s.Player, move.Action, s.InPot - before, false})
+	h.acted[seat] = true
+	h.moveOn()
+	return nil
+}
+
+func (h *Hand) call(s *Seat, toCall int) {
+	called := h.put(s, min(toCall, s.Stack))
+	s.Bet += called
+	fmt.Fprintf(h.out, "%s calls %d%s\n", s.Player, called, allInNote(s))
+}
+
+// most is the biggest total s can bet to, capped by the pot at pot limit.
+func (h *Hand) most(s *Seat) int {
+	everything := s.Bet + s.Stack
+	if h.variant.Limit != PotLimit {
+		return everything
+	}
+	toCall := h.currentBet - s.Bet
+	return min(everything, h.currentBet+h.Pot+h.bets()+toCall)
+}
+
+func (h *Hand) raiseTo(seat int, to int) error {
+	s := h.Seats[seat]
+
+	if to > s.Bet+s.Stack {
+		return fmt.Errorf("%s only has %d", s.Player, s.Bet+s.Stack)
+	}
+	if most := h.most(s); to > most {
+		return fmt.Errorf("the pot limit is %d", most)
+	}
+
+	allIn := to == s.Bet+s.Stack
+	minimum := h.currentBet + h.minRaise
+	if to < minimum && !allIn {
+		return fmt.Errorf("the minimum is %d", minimum)
+	}
+	if h.acted[seat] {
+		return fmt.Errorf("the betting has not been reopened, %s can only call or fold", s.Player)
+	}
+
+	verb := "raises to"
+	if h.currentBet == 0 {
+		verb = "bets"
+	}
+
+	s.Bet += h.put(s, to-s.Bet)
+
+	// only a full raise reopens the betting for everyone else.
+	if increase := to - h.currentBet; increase >= h.minRaise {
+		h.minRaise = max(increase, h.Blinds.BigBlind)
+		for i := range h.acted {
+			h.acted[i] = false
+		}
+	}
+	if to > h.currentBet {
+		h.currentBet = to
+	}
+
+	fmt.Fprintf(h.out, "%s %s %d%s\n", s.Player, verb, to, allInNote(s))
+	return nil
+}
+
+// moveOn finds the next to act, or deals the next street.
+func (h *Hand) moveOn() {
+	for {
+		if h.playersIn() == 1 {
+			h.collectBets()
+			h.winUncontested()
+			return
+		}
+
+		if next := h.nextToAct(h.toAct); next >= 0 {
+			h.toAct = next
+			h.prompt()
+			return
+		}
+
+		h.collectBets()
+		if h.Street == h.lastStreet() || h.playersAbleToBet() < 2 {
+			h.runOut()
+			return
+		}
+
+		h.nextStreet()
+		h.toAct = h.actsBefore(h.opener())
+	}
+}
+
+func (h *Hand) lastStreet() Street {
+	return h.streets[len(h.streets)-1]
+}
+
+// opener is who bets first after the first street.
+func (h *Hand) opener() int {
+	if !h.variant.Stud {
+		return h.next(h.Button)
+	}
+
+	best := -1
+	for i := range h.Seats {
+		seat := h.next(h.Button + i)
+		if h.Seats[seat].canAct() && (best < 0 || showingValue(h.Seats[seat].Upcards) > showingValue(h.Seats[best].Upcards)) {
+			best = seat
+		}
+	}
+	return best
+}
+
+// actsBefore is the seat to the right of seat.
+func (h *Hand) actsBefore(seat int) int {
+	return (seat + len(h.Seats) - 1) % len(h.Seats)
+}
+
+// nextToAct is the first seat after from that still has to act.
+func (h *Hand) nextToAct(from int) int {
+	for i := 1; i <= len(h.Seats); i++ {
+		seat := (from + i) % len(h.Seats)
+		s := h.Seats[seat]
+		if s.canAct() && (!h.acted[seat] || s.Bet < h.currentBet) {
+			return seat
+		}
+	}
+	return -1
+}
+
+func (h *Hand) playersIn() int {
+	count := 0
+	for _, s := range h.Seats {
+		if !s.Folded {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *Hand) playersAbleToBet() int {
+	count := 0
+	for _, s := range h.Seats {
+		if s.canAct() {
+			count++
+		}
+	}
+	return count
+}
+
+// collectBets returns any unmatched bet and gathers the rest into the pot.
+func (h *Hand) collectBets() {
+	top := 0
+	for i, s := range h.Seats {
+		if s.Bet > h.Seats[top].Bet {
+			top = i
+		}
+	}
+
+	second := 0
+	for i, s := range h.Seats {
+		if i != top {
+			second = max(second, s.Bet)
+		}
+	}
+
+	if uncalled := h.Seats[top].Bet - second; uncalled > 0 {
+		s := h.Seats[top]
+		s.Bet -= uncalled
+		s.InPot -= uncalled
+		s.Stack += uncalled
+		s.AllIn = false
+		fmt.Fprintf(h.out, "%d uncalled is returned to %s\n", uncalled, s.Player)
+	}
+
+	for _, s := range h.Seats {
+		h.Pot += s.Bet
+		s.Bet = 0
+	}
+}
+
+func (h *Hand) nextStreet() {
+	h.Street++
+	h.currentBet = 0
+	h.minRaise = h.Blinds.BigBlind
+	for i := range h.acted {
+		h.acted[i] = false
+	}
+
+	if h.variant.Stud {
+		h.dealStudStreet()
+		return
+	}
+
+	cards := 1
+	if h.Street == Flop {
+		cards = 3
+	}
+
+	h.deck.Draw(1) // burn
+	dealt, _ := h.deck.Draw(cards)
+	h.Board = append(h.Board, dealt...)
+
+	fmt.Fprintf(h.out, "%s: %s (pot %d)\n", streetTitles[h.Street], formatCards(h.Board), h.Pot)
+}
+
+// dealStudStreet deals a shared card to the board when the deck cannot go round.
+func (h *Hand) dealStudStreet() {
+	title := streetTitles[h.Street]
+	faceUp := h.Street != SeventhStreet
+
+	if h.deck.Len() < h.playersIn() {
+		dealt, _ := h.deck.Draw(1)
+		h.Board = append(h.Board, dealt...)
+		fmt.Fprintf(h.out, "%s: %s is dealt to the board for everyone (pot %d)\n", title, formatCards(dealt), h.Pot)
+		return
+	}
+
+	h.dealRound(faceUp)
+	fmt.Fprintf(h.out, "%s (pot %d)\n", title, h.Pot)
+	for i := range h.Seats {
+		seat := h.Seats[h.next(h.Button+i)]
+		switch {
+		case seat.Folded:
+		case faceUp:
+			fmt.Fprintf(h.out, "%s shows %s\n", seat.Player, formatCards(seat.Upcards))
+		default:
+			fmt.Fprintf(h.out, "Dealt to %s: %s\n", seat.Player, formatCards(seat.Cards[len(seat.Cards)-1:]))
+		}
+	}
+}
+
+func (h *Hand) runOut() {
+	for h.Street != h.lastStreet() {
+		h.nextStreet()
+	}
+	h.Street = Showdown
+	h.showdown()
+}
+
+// value is the best hand a player makes with the board.
+func (h *Hand) value(s *Seat) HandValue {
+	return h.variant.High(s.Cards, h.Board)
+}
+
+func (h *Hand) winUncontested() {
+	for _, s := range h.Seats {
+		if !s.Folded {
+			s.Stack += h.Pot
+			fmt.Fprintf(h.out, "%s wins %d\n", s.Player, h.Pot)
+		}
+	}
+	h.Pot = 0
+	h.finished = true
+}
+
+func (h *Hand) prompt() {
+	s := h.Seats[h.toAct]
+	toCall := h.currentBet - s.Bet
+
+	var options string
+	switch {
+	case toCall == 0 && h.currentBet == 0:
+		options = "check or bet " + h.sizes(s, min(h.Blinds.BigBlind, s.Stack))
+	case toCall == 0:
+		options = "check or raise " + h.sizes(s, min(h.currentBet+h.minRaise, s.Bet+s.Stack))
+	case h.acted[h.toAct] || s.Stack <= toCall:
+		options = fmt.Sprintf("fold or call %d", min(toCall, s.Stack))
+	default:
+		options = fmt.Sprintf("fold, call %d or raise %s", toCall, h.sizes(s, min(h.currentBet+h.minRaise, s.Bet+s.Stack)))
+	}
+
+	fmt.Fprintf(h.out, "%s to act: %s (stack %d, pot %d)\n", s.Player, options, s.Stack, h.Pot+h.bets())
+}
+
+// sizes writes the amounts s can bet, like "200+" or "200-700".
+func (h *Hand) sizes(s *Seat, least int) string {
+	if most := h.most(s); h.variant.Limit == PotLimit && most > least {
+		return fmt.Sprintf("%d-%d", least, most)
+	}
+	return fmt.Sprintf("%d+", least)
+}
+
+func (h *Hand) bets() int {
+	total := 0
+	for _, s := range h.Seats {
+		total += s.Bet
+	}
+	return total
+}
+
+func allInNote(s *Seat) string {
+	if s.AllIn {
+		return " and is all-in"
+	}
+	return ""
+}
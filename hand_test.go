@@ -0,0 +1,338 @@
+package poker
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var blinds50100 = BlindLevel{SmallBlind: 50, BigBlind: 100, Duration: 10 * time.Minute}
+
+// newTestHand deals from an unshuffled deck with the button on the first seat.
+func newTestHand(t testing.TB, blinds BlindLevel, stacks ...int) (*Hand, *bytes.Buffer) {
+	t.Helper()
+	return newHandFrom(t, NewDeck(), blinds, stacks...)
+}
+
+func newHandFrom(t testing.TB, deck *Deck, blinds BlindLevel, stacks ...int) (*Hand, *bytes.Buffer) {
+	t.Helper()
+	return newVariantHand(t, TexasHoldemVariant, deck, blinds, stacks...)
+}
+
+func newVariantHand(t testing.TB, variant Variant, deck *Deck, blinds BlindLevel, stacks ...int) (*Hand, *bytes.Buffer) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	var seats []*Seat
+	for i, stack := range stacks {
+		seats = append(seats, &Seat{Player: string(rune('A' + i)), Stack: stack})
+	}
+
+	hand, err := newHand(1, variant, seats, 0, blinds, deck, out)
+	if err != nil {
+		t.Fatalf("could not deal the hand: %v", err)
+	}
+	return hand, out
+}
+
+func play(t testing.TB, hand *Hand, moves ...string) {
+	t.Helper()
+
+	for _, input := range moves {
+		move, err := ParseMove(input)
+		if err != nil {
+			t.Fatalf("could not parse %q: %v", input, err)
+		}
+		if err := hand.Act(hand.ToAct(), move); err != nil {
+			t.Fatalf("%s could not %s: %v", hand.Seats[hand.toAct].Player, input, err)
+		}
+	}
+}
+
+func TestHandBlinds(t *testing.T) {
+	t.Run("the two players after the button post and the next one acts", func(t *testing.T) {
+		hand, out := newTestHand(t, blinds50100, 1000, 1000, 1000, 1000)
+
+		assertStacks(t, hand, 1000, 950, 900, 1000)
+		assertToAct(t, hand, 3)
+		if !strings.Contains(out.String(), "B posts small blind 50\nC posts big blind 100\n") {
+			t.Errorf("blinds were not announced, got %q", out.String())
+		}
+	})
+
+	t.Run("heads up the button posts the small blind and acts first", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000)
+
+		assertStacks(t, hand, 950, 900)
+		assertToAct(t, hand, 0)
+
+		play(t, hand, "call", "check")
+		assertStreet(t, hand, Flop)
+		assertToAct(t, hand, 1)
+	})
+
+	t.Run("antes go straight into the pot", func(t *testing.T) {
+		blinds := blinds50100
+		blinds.Ante = 10
+		hand, _ := newTestHand(t, blinds, 1000, 1000, 1000)
+
+		assertStacks(t, hand, 990, 940, 890)
+		if hand.Pot != 30 {
+			t.Errorf("got pot %d want 30", hand.Pot)
+		}
+	})
+
+	t.Run("everyone is dealt two cards", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		for _, seat := range hand.Seats {
+			if len(seat.Cards) != 2 {
+				t.Errorf("%s got %v", seat.Player, seat.Cards)
+			}
+		}
+	})
+}
+
+func TestHandBetting(t *testing.T) {
+	t.Run("checking and calling through to the showdown", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		play(t, hand, "call", "call", "check")
+		assertStreet(t, hand, Flop)
+		assertBoard(t, hand, 3)
+		assertToAct(t, hand, 1)
+
+		play(t, hand, "check", "bet 200", "call", "call")
+		assertStreet(t, hand, Turn)
+		assertBoard(t, hand, 4)
+
+		play(t, hand, "check", "check", "check")
+		assertStreet(t, hand, River)
+		assertBoard(t, hand, 5)
+
+		play(t, hand, "check", "check", "check")
+		assertStreet(t, hand, Showdown)
+		assertToAct(t, hand, -1)
+		if !hand.Finished() {
+			t.Error("expected the showdown to settle the hand")
+		}
+		assertChipsKept(t, hand, []int{1000, 1000, 1000})
+	})
+
+	t.Run("the last player left wins and gets the uncalled bet back", func(t *testing.T) {
+		hand, out := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		play(t, hand, "raise 300", "fold", "fold")
+
+		if !hand.Finished() {
+			t.Fatal("expected the hand to be over")
+		}
+		assertStacks(t, hand, 1150, 950, 900)
+		if !strings.Contains(out.String(), "200 uncalled is returned to A\nA wins 250\n") {
+			t.Errorf("got %q", out.String())
+		}
+	})
+
+	t.Run("an all-in with nobody left to bet runs out the board", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 500)
+
+		play(t, hand, "all-in", "call")
+
+		assertStreet(t, hand, Showdown)
+		assertBoard(t, hand, 5)
+		if got := hand.Wagered(Preflop); got != 1500 {
+			t.Errorf("got %d wagered, want 1500 before the uncalled 500 went back", got)
+		}
+		assertChipsKept(t, hand, []int{1000, 500})
+	})
+
+	t.Run("a short all-in does not reopen the betting", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 250)
+
+		play(t, hand, "raise 200", "call", "all-in")
+
+		assertToAct(t, hand, 0)
+		assertHandError(t, hand.Act(0, Move{Raise, 600}), "can only call or fold")
+
+		play(t, hand, "call", "call")
+		assertStreet(t, hand, Flop)
+		if hand.Pot != 750 {
+			t.Errorf("got pot %d want 750", hand.Pot)
+		}
+	})
+
+	t.Run("raising all in for less than the bet is a call", func(t *testing.T) {
+		hand, out := newTestHand(t, blinds50100, 1000, 150, 1000)
+
+		play(t, hand, "raise 400", "raise 150")
+
+		assertToAct(t, hand, 2)
+		if !strings.Contains(out.String(), "B calls 100 and is all-in\nC to act: fold, call 300 or raise 700+") {
+			t.Errorf("expected B's all in to be a call leaving 300 for C to call, got %q", out.String())
+		}
+		if got := hand.Wagers[len(hand.Wagers)-1]; got.Action != Call || got.Chips != 100 {
+			t.Errorf("got B's move kept as %+v, want a call of 100", got)
+		}
+	})
+
+	t.Run("a full raise sets the next minimum", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 5000, 5000, 5000)
+
+		play(t, hand, "raise 400")
+		assertHandError(t, hand.Act(1, Move{Raise, 600}), "the minimum is 700")
+		play(t, hand, "raise 700")
+	})
+
+	t.Run("moves that break the rules are refused", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		assertHandError(t, hand.Act(1, Move{Action: Call}), "it is A's turn")
+		assertHandError(t, hand.Act(0, Move{Action: Check}), "cannot check, it is 100 to call")
+		assertHandError(t, hand.Act(0, Move{Bet, 300}), "raise instead")
+		assertHandError(t, hand.Act(0, Move{Raise, 150}), "the minimum is 200")
+		assertHandError(t, hand.Act(0, Move{Raise, 2000}), "A only has 1000")
+
+		play(t, hand, "call", "call", "check")
+		assertHandError(t, hand.Act(1, Move{Action: Call}), "nothing for B to call")
+		assertHandError(t, hand.Act(1, Move{Raise, 200}), "bet instead")
+		assertHandError(t, hand.Act(1, Move{Bet, 50}), "the minimum is 100")
+	})
+}
+
+func TestParseMove(t *testing.T) {
+	cases := map[string]Move{
+		"fold":      {Action: Fold},
+		"Check":     {Action: Check},
+		"call":      {Action: Call},
+		"bet 200":   {Bet, 200},
+		"raise 600": {Raise, 600},
+		"all-in":    {Action: AllIn},
+		"all in":    {Action: AllIn},
+	}
+
+	for input, want := range cases {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseMove(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %+v want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("other input is not a move", func(t *testing.T) {
+		if _, err := ParseMove("Chris wins"); err != ErrNotAMove {
+			t.Errorf("got %v want %v", err, ErrNotAMove)
+		}
+	})
+
+	t.Run("bets need an amount", func(t *testing.T) {
+		if _, err := ParseMove("bet lots"); err == nil || err == ErrNotAMove {
+			t.Errorf("expected a bad amount error, got %v", err)
+		}
+	})
+}
+
+func TestTable(t *testing.T) {
+	t.Run("seats 2 to 10 players", func(t *testing.T) {
+		for _, players := range [][]string{{"A"}, strings.Split("ABCDEFGHIJK", "")} {
+			if _, err := NewTable(players, 1000, 1, io.Discard); err == nil {
+				t.Errorf("expected an error seating %d players", len(players))
+			}
+		}
+	})
+
+	t.Run("the button moves on and skips players with no chips", func(t *testing.T) {
+		table, _ := NewTable([]string{"A", "B", "C"}, 1000, 1, io.Discard)
+
+		hand, _ := table.DealHand(blinds50100)
+		assertButton(t, table, hand, "A")
+
+		hand.Act(hand.ToAct(), Move{Action: Fold})
+		hand.Act(hand.ToAct(), Move{Action: Fold})
+		table.Seats[2].Stack = 0
+
+		hand, err := table.DealHand(blinds50100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertButton(t, table, hand, "B")
+		if len(hand.Seats) != 2 {
+			t.Errorf("got %d players dealt in, want 2", len(hand.Seats))
+		}
+	})
+
+	t.Run("waits for the hand to finish before dealing again", func(t *testing.T) {
+		table, _ := NewTable([]string{"A", "B"}, 1000, 1, io.Discard)
+		table.DealHand(blinds50100)
+
+		if _, err := table.DealHand(blinds50100); err == nil {
+			t.Error("expected an error dealing over a hand in progress")
+		}
+	})
+
+	t.Run("does not deal on a break", func(t *testing.T) {
+		table, _ := NewTable([]string{"A", "B"}, 1000, 1, io.Discard)
+
+		if _, err := table.DealHand(BlindLevel{Break: true, Duration: time.Minute}); err == nil {
+			t.Error("expected an error dealing on a break")
+		}
+	})
+}
+
+func assertButton(t testing.TB, table *Table, hand *Hand, want string) {
+	t.Helper()
+
+	if got := table.Seats[table.Button].Player; got != want {
+		t.Errorf("got the button on %s want %s", got, want)
+	}
+	if got := hand.Seats[hand.Button].Player; got != want {
+		t.Errorf("the hand has the button on %s want %s", got, want)
+	}
+}
+
+func assertStacks(t testing.TB, hand *Hand, want ...int) {
+	t.Helper()
+
+	for i, seat := range hand.Seats {
+		if seat.Stack != want[i] {
+			t.Errorf("%s has %d chips, want %d", seat.Player, seat.Stack, want[i])
+		}
+	}
+}
+
+func assertToAct(t testing.TB, hand *Hand, want int) {
+	t.Helper()
+
+	if got := hand.ToAct(); got != want {
+		t.Errorf("got seat %d to act, want %d", got, want)
+	}
+}
+
+func assertStreet(t testing.TB, hand *Hand, want Street) {
+	t.Helper()
+
+	if hand.Street != want {
+		t.Errorf("got street %v want %v", hand.Street, want)
+	}
+}
+
+func assertBoard(t testing.TB, hand *Hand, want int) {
+	t.Helper()
+
+	if len(hand.Board) != want {
+		t.Errorf("got board %v, want %d cards", hand.Board, want)
+	}
+}
+
+func assertHandError(t testing.TB, err error, want string) {
+	t.Helper()
+
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want one mentioning %q", err, want)
+	}
+}
@@ -0,0 +1,246 @@
+package poker
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// JournalEntry is a result kept offline. Exactly one of Win, Tournament, CashGame and Payment is set.
+type JournalEntry struct {
+	ID         string            `json:"id"`
+	Recorded   time.Time         `json:"recorded"`
+	Win        string            `json:"win,omitempty"`
+	Tournament *TournamentResult `json:"tournament,omitempty"`
+	CashGame   *CashResult       `json:"cash_game,omitempty"`
+	Payment    *Payment          `json:"payment,omitempty"`
+	Synced     bool              `json:"synced,omitempty"`
+}
+
+// fingerprint is the entry's result as JSON, shared by the same result.
+func (e JournalEntry) fingerprint() string {
+	e.ID, e.Recorded, e.Synced = "", time.Time{}, false
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+func newEntryID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// SyncStatus is what a server did with a journal entry.
+type SyncStatus string
+
+const (
+	// SyncMerged is an entry the server did not have and now does.
+	SyncMerged SyncStatus = "merged"
+	// SyncDuplicate is an entry the server already had.
+	SyncDuplicate SyncStatus = "duplicate"
+	// SyncConflict is a different result under the same id or start time; it is not merged.
+	SyncConflict SyncStatus = "conflict"
+	// SyncRejected is an entry the server cannot take.
+	SyncRejected SyncStatus = "rejected"
+)
+
+// SyncResult is what happened to one entry, and why if not merged.
+type SyncResult struct {
+	ID     string       `json:"id"`
+	Status SyncStatus   `json:"status"`
+	Reason string       `json:"reason,omitempty"`
+	Entry  JournalEntry `json:"entry"`
+}
+
+// Done reports whether the entry need not be sent again.
+func (r SyncResult) Done() bool {
+	return r.Status == SyncMerged || r.Status == SyncDuplicate
+}
+
+// SyncStore takes journal entries, each only once.
+type SyncStore interface {
+	PlayerStore
+	Merge([]JournalEntry) ([]SyncResult, error)
+}
+
+var errEmptyEntry = errors.New("the entry has no result in it")
+
+// KeepJournal journals everything recorded from now on, for Unsynced.
+func (f *FileSystemPlayerStore) KeepJournal() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.journaling = true
+}
+
+// note adds entry to the journal when one is being kept.
+func (f *FileSystemPlayerStore) note(entry JournalEntry) {
+	if !f.journaling {
+		return
+	}
+	entry.ID, entry.Recorded = newEntryID(), time.Now()
+	f.journal = append(f.journal, entry)
+}
+
+// Journal returns every entry kept, synced or not, oldest first.
+func (f *FileSystemPlayerStore) Journal() []JournalEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]JournalEntry{}, f.journal...)
+}
+
+// Unsynced returns the entries no server has yet, oldest first.
+func (f *FileSystemPlayerStore) Unsynced() []JournalEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var entries []JournalEntry
+	for _, entry := range f.journal {
+		if !entry.Synced {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// MarkSynced notes that a server has the entries with ids.
+func (f *FileSystemPlayerStore) MarkSynced(ids ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	synced := map[string]bool{}
+	for _, id := range ids {
+		synced[id] = true
+	}
+	for i := range f.journal {
+		if synced[f.journal[i].ID] {
+			f.journal[i].Synced = true
+		}
+	}
+	return f.save()
+}
+
+// Merge records entries not seen before, so sending them again changes nothing.
+func (f *FileSystemPlayerStore) Merge(entries []JournalEntry) ([]SyncResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.merged == nil {
+		f.merged = map[string]string{}
+	}
+
+	results := make([]SyncResult, 0, len(entries))
+	for _, entry := range entries {
+		entry.Synced = false
+		result := SyncResult{ID: entry.ID, Entry: entry}
+		result.Status, result.Reason = f.merge(entry)
+		results = append(results, result)
+	}
+	return results, f.save()
+}
+
+func (f *FileSystemPlayerStore) merge(entry JournalEntry) (SyncStatus, string) {
+	if entry.ID == "" {
+		return SyncRejected, "the entry has no id"
+	}
+	if fingerprint, ok := f.merged[entry.ID]; ok {
+		if fingerprint == entry.fingerprint() {
+			return SyncDuplicate, "it was synced before"
+		}
+		return SyncConflict, "something else was synced with this id"
+	}
+
+	if status, reason, found := f.kept(entry); found {
+		if status == SyncDuplicate {
+			f.merged[entry.ID] = entry.fingerprint()
+		}
+		return status, reason
+	}
+
+	if err := f.apply(entry); err != nil {
+		return SyncRejected, err.Error()
+	}
+	f.merged[entry.ID] = entry.fingerprint()
+	return SyncMerged, ""
+}
+
+// kept finds a result kept that started when entry's did.
+func (f *FileSystemPlayerStore) kept(entry JournalEntry) (SyncStatus, string, bool) {
+	switch {
+	case entry.Tournament != nil && !entry.Tournament.Started.IsZero():
+		for _, result := range f.tournaments {
+			if result.Started.Equal(entry.Tournament.Started) {
+				if sameJSON(result, *entry.Tournament) {
+					return SyncDuplicate, "the tournament is already kept", true
+				}
+				return SyncConflict, "a different tournament started at the same time", true
+			}
+		}
+	case entry.CashGame != nil && !entry.CashGame.Started.IsZero():
+		for _, result := range f.cashGames {
+			if result.Started.Equal(entry.CashGame.Started) {
+				if sameJSON(result, *entry.CashGame) {
+					return SyncDuplicate, "the cash game is already kept", true
+				}
+				return SyncConflict, "a different cash game started at the same time", true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// apply records entry as if it had been recorded here.
+func (f *FileSystemPlayerStore) apply(entry JournalEntry) error {
+	switch {
+	case entry.Win != "":
+		f.addWin(entry.Win)
+	case entry.Tournament != nil:
+		if entry.Tournament.Winner() == "" {
+			return errors.New("the tournament has no winner")
+		}
+		f.addTournament(*entry.Tournament)
+	case entry.CashGame != nil:
+		f.addCashGame(*entry.CashGame)
+	case entry.Payment != nil:
+		return f.addPayment(*entry.Payment)
+	default:
+		return errEmptyEntry
+	}
+	return nil
+}
+
+func sameJSON(a, b any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// String says what the entry recorded, in English.
+func (e JournalEntry) String() string {
+	return English.Entry(e)
+}
+
+// Entry says what a journal entry recorded, in l.
+func (l *Locale) Entry(e JournalEntry) string {
+	const when = "2 Jan 15:04"
+	switch {
+	case e.Win != "":
+		return l.Sprintf("a win for %s", e.Win)
+	case e.Tournament != nil:
+		return l.Sprintf("the tournament of %s won by %s", e.Tournament.Started.Format(when), e.Tournament.Winner())
+	case e.CashGame != nil:
+		var players []string
+		for _, stake := range e.CashGame.Players {
+			players = append(players, stake.Player)
+		}
+		return l.Sprintf("the cash game of %s with %s", e.CashGame.Started.Format(when), strings.Join(players, ", "))
+	case e.Payment != nil:
+		return l.Sprintf("%s paying %s %s", e.Payment.From, e.Payment.To, l.Number(e.Payment.Amount))
+	}
+	return l.Text(errEmptyEntry.Error())
+}
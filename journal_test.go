@@ -0,0 +1,187 @@
+package poker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournal(t *testing.T) {
+	started := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	tournament := TournamentResult{Started: started, Finished: started.Add(3 * time.Hour), Entrants: 2, Placings: []Placing{
+		{Place: 1, Player: "Cleo"}, {Place: 2, Player: "Chris"},
+	}}
+
+	t.Run("it notes what is recorded once asked to", func(t *testing.T) {
+		database, clean := createTempFile(t, "")
+		defer clean()
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		store.RecordWin("Before")
+		store.KeepJournal()
+		store.RecordWin("Chris")
+		assertNoError(t, store.RecordTournament(tournament))
+		assertNoError(t, store.RecordPayment(Payment{From: "Chris", To: "Cleo", Amount: 10}))
+
+		entries := store.Unsynced()
+		if len(entries) != 3 || entries[0].Win != "Chris" || entries[1].Tournament == nil || entries[2].Payment == nil {
+			t.Fatalf("got %+v, want Chris's win, the tournament and the payment", entries)
+		}
+		if entries[0].ID == "" || entries[0].ID == entries[1].ID {
+			t.Errorf("entries need ids of their own, got %q and %q", entries[0].ID, entries[1].ID)
+		}
+
+		assertNoError(t, store.MarkSynced(entries[0].ID))
+		if got := len(store.Unsynced()); got != 2 {
+			t.Errorf("got %d unsynced want 2", got)
+		}
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+		if got := len(reopened.Journal()); got != 3 {
+			t.Errorf("got %d entries after reopening want 3", got)
+		}
+	})
+
+	t.Run("merging the same entries twice changes nothing the second time", func(t *testing.T) {
+		journal := newJournal(t)
+		journal.RecordWin("Chris")
+		assertNoError(t, journal.RecordTournament(tournament))
+
+		server := newJournal(t)
+		results, err := server.Merge(journal.Unsynced())
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncMerged, SyncMerged)
+
+		results, err = server.Merge(journal.Unsynced())
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncDuplicate, SyncDuplicate)
+
+		assertScoreEquals(t, server.GetPlayerScore("Chris"), 1)
+		assertScoreEquals(t, server.GetPlayerScore("Cleo"), 1)
+		if got := len(server.Tournaments()); got != 1 {
+			t.Errorf("got %d tournaments want 1", got)
+		}
+	})
+
+	t.Run("a tournament already recorded on the server is a duplicate", func(t *testing.T) {
+		journal := newJournal(t)
+		assertNoError(t, journal.RecordTournament(tournament))
+
+		server := newJournal(t)
+		assertNoError(t, server.RecordTournament(tournament))
+
+		results, err := server.Merge(journal.Unsynced())
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncDuplicate)
+		assertScoreEquals(t, server.GetPlayerScore("Cleo"), 1)
+	})
+
+	t.Run("a different result for the same game is a conflict and is not merged", func(t *testing.T) {
+		journal := newJournal(t)
+		other := tournament
+		other.Placings = []Placing{{Place: 1, Player: "Chris"}, {Place: 2, Player: "Cleo"}}
+		assertNoError(t, journal.RecordTournament(other))
+
+		server := newJournal(t)
+		assertNoError(t, server.RecordTournament(tournament))
+
+		results, err := server.Merge(journal.Unsynced())
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncConflict)
+		assertScoreEquals(t, server.GetPlayerScore("Chris"), 0)
+	})
+
+	t.Run("an id sent again with something else in it is a conflict", func(t *testing.T) {
+		server := newJournal(t)
+		_, err := server.Merge([]JournalEntry{{ID: "1", Win: "Chris"}})
+		assertNoError(t, err)
+
+		results, err := server.Merge([]JournalEntry{{ID: "1", Win: "Cleo"}})
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncConflict)
+		assertScoreEquals(t, server.GetPlayerScore("Cleo"), 0)
+	})
+
+	t.Run("it rejects what it cannot record", func(t *testing.T) {
+		server := newJournal(t)
+		results, err := server.Merge([]JournalEntry{
+			{ID: "1", Payment: &Payment{From: "Chris", To: "Chris", Amount: 10}},
+			{ID: "2"},
+			{Win: "Chris"},
+		})
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncRejected, SyncRejected, SyncRejected)
+	})
+
+	t.Run("the server remembers what it merged", func(t *testing.T) {
+		database, clean := createTempFile(t, "")
+		defer clean()
+		server, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+
+		_, err = server.Merge([]JournalEntry{{ID: "1", Win: "Chris"}})
+		assertNoError(t, err)
+
+		reopened, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+		results, err := reopened.Merge([]JournalEntry{{ID: "1", Win: "Chris"}})
+		assertNoError(t, err)
+		assertStatuses(t, results, SyncDuplicate)
+	})
+}
+
+func TestSyncHandler(t *testing.T) {
+	t.Run("it answers with what it did with each entry", func(t *testing.T) {
+		server := NewPlayerServer(newJournal(t), gameFactory(dummyGame))
+		body := `[{"id": "1", "win": "Chris"}, {"id": "1", "win": "Chris"}]`
+
+		request := httptest.NewRequest(http.MethodPut, "/journal", strings.NewReader(body))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusOK)
+		AssertContentType(t, response, jsonContentType)
+		for _, want := range []string{`"status":"merged"`, `"status":"duplicate"`} {
+			if !strings.Contains(response.Body.String(), want) {
+				t.Errorf("expected %s in %s", want, response.Body.String())
+			}
+		}
+	})
+
+	t.Run("it says when the store cannot take a journal", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+		request := httptest.NewRequest(http.MethodPut, "/journal", strings.NewReader(`[]`))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusNotImplemented)
+	})
+}
+
+func newJournal(t testing.TB) *FileSystemPlayerStore {
+	t.Helper()
+	database, clean := createTempFile(t, "")
+	t.Cleanup(clean)
+
+	store, err := NewFileSystemStore(database)
+	assertNoError(t, err)
+	store.KeepJournal()
+	return store
+}
+
+func assertStatuses(t testing.TB, results []SyncResult, want ...SyncStatus) {
+	t.Helper()
+	if len(results) != len(want) {
+		t.Fatalf("got %d results want %d", len(results), len(want))
+	}
+	for i, result := range results {
+		if result.Status != want[i] {
+			t.Errorf("entry %d: got %s (%s) want %s", i+1, result.Status, result.Reason, want[i])
+		}
+	}
+}
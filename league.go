@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 )
 
 type League []Player
@@ -17,6 +18,31 @@ func (l League) Find(name string) *Player {
 	return nil
 }
 
+// ByNet returns a copy of the league ordered by cash game results.
+func (l League) ByNet() League {
+	ranked := make(League, len(l))
+	copy(ranked, l)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Net > ranked[j].Net
+	})
+	return ranked
+}
+
+// Write prints the league a line a player.
+func (l League) Write(w io.Writer) {
+	l.write(w, English)
+}
+
+func (l League) write(w io.Writer, locale *Locale) {
+	for i, player := range l {
+		line := fmt.Sprintf("%d. %s: %s", i+1, player.Name, locale.Plural(player.Wins, "%s win", "%s wins"))
+		if player.Net != 0 {
+			line += locale.Sprintf(", net %s", locale.Signed(player.Net))
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
 func NewLeague(rdr io.Reader) (League, error) {
 	var league League
 	err := json.NewDecoder(rdr).Decode(&league)
@@ -0,0 +1,238 @@
+package poker
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// Locale looks messages up by their English text. Game messages stay English for the web page.
+type Locale struct {
+	Name     string
+	Language string
+
+	messages  map[string]string
+	plurals   map[string][2]string
+	one       func(n int) bool
+	ordinal   func(n int) string
+	separator string
+}
+
+// catalog is how a locale's messages are kept in its file.
+type catalog struct {
+	Messages map[string]string    `yaml:"messages"`
+	Plurals  map[string][2]string `yaml:"plurals"`
+}
+
+var (
+	English = &Locale{Name: "en", Language: "English", one: isOne, ordinal: Ordinal, separator: ","}
+	French  = &Locale{Name: "fr", Language: "Français", one: func(n int) bool { return n == 0 || n == 1 }, ordinal: frenchOrdinal, separator: "\u202f"}
+	Dutch   = &Locale{Name: "nl", Language: "Nederlands", one: isOne, ordinal: dutchOrdinal, separator: "."}
+)
+
+// Locales is every language there is a catalog for, English first.
+var Locales = []*Locale{English, French, Dutch}
+
+func init() {
+	for _, locale := range Locales[1:] {
+		data, err := localeFiles.ReadFile(path.Join("locales", locale.Name+".yaml"))
+		if err != nil {
+			panic(err)
+		}
+		var c catalog
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("problem reading the %s catalog %v", locale.Name, err))
+		}
+		locale.messages, locale.plurals = c.Messages, c.Plurals
+	}
+}
+
+func isOne(n int) bool {
+	return n == 1
+}
+
+func frenchOrdinal(n int) string {
+	if n == 1 {
+		return "1er"
+	}
+	return strconv.Itoa(n) + "e"
+}
+
+func dutchOrdinal(n int) string {
+	return strconv.Itoa(n) + "e"
+}
+
+// LookupLocale finds the locale for a tag like fr, nl-BE or fr_FR.UTF-8.
+func LookupLocale(tag string) (*Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if end := strings.IndexAny(tag, ".@"); end >= 0 {
+		tag = tag[:end]
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+
+	for _, locale := range Locales {
+		if locale.Name == language {
+			return locale, true
+		}
+	}
+	return nil, false
+}
+
+// LocaleFromEnv is the locale LC_ALL, LC_MESSAGES or LANG asks for, or English.
+func LocaleFromEnv(getenv func(string) string) *Locale {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := getenv(name); value != "" {
+			if locale, ok := LookupLocale(value); ok {
+				return locale
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// MatchLocale picks from Accept-Language, or fallback.
+func MatchLocale(acceptLanguage string, fallback *Locale) *Locale {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if tag != "" && quality > 0 {
+			preferences = append(preferences, preference{tag, quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+
+	for _, p := range preferences {
+		if p.tag == "*" {
+			break
+		}
+		if locale, ok := LookupLocale(p.tag); ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+func (l *Locale) orEnglish() *Locale {
+	if l == nil {
+		return English
+	}
+	return l
+}
+
+// Text is message in l, or as it is when there is no translation.
+func (l *Locale) Text(message string) string {
+	if translated, ok := l.orEnglish().messages[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf translates format, and any errors in a, then fills it in.
+func (l *Locale) Sprintf(format string, a ...any) string {
+	for i, arg := range a {
+		if err, ok := arg.(error); ok {
+			a[i] = l.Error(err)
+		}
+	}
+	return fmt.Sprintf(l.Text(format), a...)
+}
+
+// Errorf is an error with a message already in l.
+func (l *Locale) Errorf(format string, a ...any) error {
+	return errors.New(l.Sprintf(format, a...))
+}
+
+// Error translates err's message, for errors whose message never changes.
+func (l *Locale) Error(err error) string {
+	return l.Text(err.Error())
+}
+
+// Plural is n in words, like Plural(3, "%s win", "%s wins").
+func (l *Locale) Plural(n int, one, other string) string {
+	l = l.orEnglish()
+	forms, ok := l.plurals[other]
+	if !ok {
+		forms = [2]string{one, other}
+	}
+	if l.one(n) {
+		return fmt.Sprintf(forms[0], l.Number(n))
+	}
+	return fmt.Sprintf(forms[1], l.Number(n))
+}
+
+// Number writes n with the locale's thousands separator.
+func (l *Locale) Number(n int) string {
+	digits := strconv.Itoa(n)
+	separator := l.orEnglish().separator
+	for i := len(digits) - 3; i > 0 && digits[i-1] != '-'; i -= 3 {
+		digits = digits[:i] + separator + digits[i:]
+	}
+	return digits
+}
+
+// Lines translates text a line at a time.
+func (l *Locale) Lines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = l.Text(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Signed is Number with a + in front of anything that is not negative.
+func (l *Locale) Signed(n int) string {
+	if n < 0 {
+		return l.Number(n)
+	}
+	return "+" + l.Number(n)
+}
+
+// Ordinal is 1st, 2nd, 3rd and so on in l.
+func (l *Locale) Ordinal(n int) string {
+	return l.orEnglish().ordinal(n)
+}
+
+// Blinds is a level's blinds with the chips written the locale's way.
+func (l *Locale) Blinds(level BlindLevel) string {
+	if level.Break {
+		return l.Text("break")
+	}
+	if level.Ante > 0 {
+		return l.Sprintf("%s/%s ante %s", l.Number(level.SmallBlind), l.Number(level.BigBlind), l.Number(level.Ante))
+	}
+	return l.Number(level.SmallBlind) + "/" + l.Number(level.BigBlind)
+}
+
+// Alert is what BlindAlert.String says, in l.
+func (l *Locale) Alert(alert BlindAlert) string {
+	var text string
+	if alert.Level.Break {
+		text = l.Text("Break time")
+	} else {
+		text = l.Sprintf("Blinds are now %s", l.Blinds(alert.Level))
+	}
+	if alert.HasNext() {
+		text += l.Sprintf(", next %s in %v", l.Blinds(alert.Next), alert.NextIn)
+	}
+	return text
+}
@@ -0,0 +1,245 @@
+package poker
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	cases := map[string]*Locale{
+		"en":          English,
+		"fr":          French,
+		"FR":          French,
+		"nl-BE":       Dutch,
+		"fr_FR.UTF-8": French,
+		"nl_NL@euro":  Dutch,
+	}
+	for tag, want := range cases {
+		if got, ok := LookupLocale(tag); !ok || got != want {
+			t.Errorf("got %v for %q want %s", got, tag, want.Name)
+		}
+	}
+	if _, ok := LookupLocale("de"); ok {
+		t.Error("didn't expect to speak German")
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	cases := []struct {
+		vars map[string]string
+		want *Locale
+	}{
+		{map[string]string{}, English},
+		{map[string]string{"LANG": "nl_NL.UTF-8"}, Dutch},
+		{map[string]string{"LANG": "nl_NL.UTF-8", "LC_MESSAGES": "fr_FR.UTF-8"}, French},
+		{map[string]string{"LANG": "fr_FR.UTF-8", "LC_ALL": "C"}, English},
+	}
+	for _, c := range cases {
+		if got := LocaleFromEnv(env(c.vars)); got != c.want {
+			t.Errorf("got %s for %v want %s", got.Name, c.vars, c.want.Name)
+		}
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	cases := map[string]*Locale{
+		"":                           English,
+		"fr-FR,fr;q=0.9,en;q=0.8":    French,
+		"de-DE,de;q=0.9,nl;q=0.5":    Dutch,
+		"en;q=0.2,nl;q=0.9":          Dutch,
+		"de, *;q=0.5":                English,
+		"fr;q=0, nl;q=0.1":           Dutch,
+		"es-ES,es;q=0.9,fr-CA;q=0.7": French,
+	}
+	for header, want := range cases {
+		if got := MatchLocale(header, English); got != want {
+			t.Errorf("got %s for %q want %s", got.Name, header, want.Name)
+		}
+	}
+}
+
+func TestLocaleNumbers(t *testing.T) {
+	cases := []struct {
+		locale *Locale
+		want   string
+	}{
+		{English, "12,500 -1,000"},
+		{French, "12 500 -1 000"},
+		{Dutch, "12.500 -1.000"},
+		{nil, "12,500 -1,000"},
+	}
+	for _, c := range cases {
+		if got := c.locale.Number(12500) + " " + c.locale.Number(-1000); got != c.want {
+			t.Errorf("got %q want %q", got, c.want)
+		}
+	}
+
+	if got := French.Ordinal(1) + " " + French.Ordinal(2) + " " + Dutch.Ordinal(3); got != "1er 2e 3e" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestLocalePlurals(t *testing.T) {
+	cases := []struct {
+		locale *Locale
+		n      int
+		want   string
+	}{
+		{English, 0, "0 wins"},
+		{English, 1, "1 win"},
+		{French, 0, "0 victoire"},
+		{French, 1, "1 victoire"},
+		{French, 2, "2 victoires"},
+		{Dutch, 0, "0 overwinningen"},
+		{Dutch, 1, "1 overwinning"},
+	}
+	for _, c := range cases {
+		if got := c.locale.Plural(c.n, "%s win", "%s wins"); got != c.want {
+			t.Errorf("got %q want %q", got, c.want)
+		}
+	}
+
+	if got := French.Plural(3, "%s thing", "%s things"); got != "3 things" {
+		t.Errorf("got %q for something not translated, want it in English", got)
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+	for _, locale := range Locales[1:] {
+		t.Run(locale.Name, func(t *testing.T) {
+			for english, translated := range locale.messages {
+				if got, want := verbs.FindAllString(translated, -1), verbs.FindAllString(english, -1); strings.Join(got, " ") != strings.Join(want, " ") {
+					t.Errorf("%q has %v in it, %q needs %v", translated, got, english, want)
+				}
+			}
+			for other, forms := range locale.plurals {
+				for _, form := range forms {
+					if strings.Count(form, "%s") != 1 {
+						t.Errorf("%q for %q needs one %%s", form, other)
+					}
+				}
+			}
+
+			for _, other := range Locales[1:] {
+				for english := range other.messages {
+					if _, ok := locale.messages[english]; !ok {
+						t.Errorf("%q is translated in %s but not in %s", english, other.Name, locale.Name)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("everything on the game page is translated", func(t *testing.T) {
+		page, err := os.ReadFile("game.html")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range regexp.MustCompile(`\{\{\s*\.T "([^"]+)"`).FindAllSubmatch(page, -1) {
+			for _, locale := range Locales[1:] {
+				if _, ok := locale.messages[string(match[1])]; !ok {
+					t.Errorf("%q on the game page is not in the %s catalog", match[1], locale.Name)
+				}
+			}
+		}
+	})
+}
+
+func TestLocalisedAlerts(t *testing.T) {
+	alert := BlindAlert{
+		Level:  BlindLevel{SmallBlind: 1000, BigBlind: 2000, Ante: 200},
+		Next:   BlindLevel{Break: true},
+		NextIn: 10 * time.Minute,
+	}
+
+	if got, want := French.Alert(alert), "Les blindes passent à 1 000/2 000 ante 200, ensuite pause dans 10m0s"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got, want := Dutch.Alert(alert), "De blinds zijn nu 1.000/2.000 ante 200, daarna pauze over 10m0s"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestLocalisedCLI(t *testing.T) {
+	store := &StubPlayerStore{scores: map[string]int{"Cleo": 2}}
+	in := strings.NewReader("pause\nscore Cleo\nstart\nsix\n")
+	var out bytes.Buffer
+
+	cli := NewCLI(in, &out, &GameSpy{})
+	cli.UseStore(store)
+	cli.UseLocale(French)
+	cli.PlayPoker()
+
+	for _, want := range []string{
+		"Entrez le nombre de joueurs : ",
+		"aucune partie en cours, tapez start <players> pour commencer",
+		"Cleo a 2 victoires",
+		`"six" n'est pas un nombre de joueurs`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in\n%s", want, out.String())
+		}
+	}
+}
+
+func TestLocalisedServer(t *testing.T) {
+	server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+	t.Run("errors are in the browser's language", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/league?sort=luck", nil)
+		request.Header.Set("Accept-Language", "nl-BE,nl;q=0.9,en;q=0.5")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusBadRequest)
+		if got := response.Header().Get("content-language"); got != "nl" {
+			t.Errorf("got content-language %q want nl", got)
+		}
+		if want := `kan de ranglijst niet sorteren op "luck", gebruik wins of net`; strings.TrimSpace(response.Body.String()) != want {
+			t.Errorf("got %q want %q", response.Body.String(), want)
+		}
+	})
+
+	t.Run("the game page is in the browser's language", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/game", nil)
+		request.Header.Set("Accept-Language", "fr")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusOK)
+		for _, want := range []string{`<html lang="fr">`, "Nombre de joueurs", "Déclarer le gagnant"} {
+			if !strings.Contains(response.Body.String(), want) {
+				t.Errorf("expected %q on the page", want)
+			}
+		}
+	})
+
+	t.Run("it falls back to its own language", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+		server.UseLocale(Dutch)
+
+		request := httptest.NewRequest(http.MethodGet, "/game", nil)
+		request.Header.Set("Accept-Language", "de")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		if !strings.Contains(response.Body.String(), "Aantal spelers") {
+			t.Error("expected the page in Dutch")
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output is how the CLI prints: text, a table or JSON.
+type Output string
+
+const (
+	OutputPlain Output = "plain"
+	OutputTable Output = "table"
+	OutputJSON  Output = "json"
+)
+
+// ParseOutput reads one of plain, table or json.
+func ParseOutput(name string) (Output, error) {
+	switch output := Output(strings.ToLower(name)); output {
+	case OutputPlain, OutputTable, OutputJSON:
+		return output, nil
+	}
+	return "", fmt.Errorf("%q is not an output, use plain, table or json", name)
+}
+
+func (o Output) String() string {
+	return string(o)
+}
+
+// Set lets an Output be a flag.
+func (o *Output) Set(name string) error {
+	output, err := ParseOutput(name)
+	if err != nil {
+		return err
+	}
+	*o = output
+	return nil
+}
+
+// Events are the JSON lines of -output json; fields are added, never renamed. See testdata/output.
+type messageEvent struct {
+	Event   string `json:"event"`
+	Message string `json:"message"`
+}
+
+type promptEvent struct {
+	Event    string `json:"event"`
+	Question string `json:"question"`
+	Prompt   string `json:"prompt"`
+}
+
+type errorEvent struct {
+	Event string `json:"event"`
+	Error string `json:"error"`
+}
+
+// blindsEvent is a BlindAlert, with the seconds until the next level.
+type blindsEvent struct {
+	Event         string      `json:"event"`
+	Blinds        BlindLevel  `json:"blinds"`
+	Next          *BlindLevel `json:"next,omitempty"`
+	NextInSeconds int         `json:"next_in_seconds,omitempty"`
+}
+
+// clockEvent is a ClockView. Level counts from 1.
+type clockEvent struct {
+	Event            string      `json:"event"`
+	Title            string      `json:"title,omitempty"`
+	Level            int         `json:"level"`
+	Blinds           BlindLevel  `json:"blinds"`
+	RemainingSeconds int         `json:"remaining_seconds"`
+	Paused           bool        `json:"paused"`
+	Next             *BlindLevel `json:"next,omitempty"`
+	Players          int         `json:"players"`
+	AverageStack     int         `json:"average_stack,omitempty"`
+}
+
+type leagueEvent struct {
+	Event  string `json:"event"`
+	League League `json:"league"`
+}
+
+type scoreEvent struct {
+	Event  string `json:"event"`
+	Player string `json:"player"`
+	Wins   int    `json:"wins"`
+}
+
+type winnerEvent struct {
+	Event  string `json:"event"`
+	Player string `json:"player"`
+}
+
+type undoneEvent struct {
+	Event string `json:"event"`
+	Input string `json:"input"`
+}
+
+func (b BlindAlert) event() blindsEvent {
+	event := blindsEvent{Event: "blinds", Blinds: b.Level}
+	if b.HasNext() {
+		next := b.Next
+		event.Next, event.NextInSeconds = &next, int(b.NextIn/time.Second)
+	}
+	return event
+}
+
+func (v ClockView) event() clockEvent {
+	event := clockEvent{
+		Event:            "clock",
+		Title:            v.Title,
+		Level:            v.State.Level + 1,
+		Blinds:           v.State.Blinds,
+		RemainingSeconds: int(v.State.Remaining / time.Second),
+		Paused:           v.State.Paused,
+		Players:          v.Players,
+		AverageStack:     v.AverageStack,
+	}
+	if v.HasNext {
+		next := v.Next
+		event.Next = &next
+	}
+	return event
+}
+
+// jsonLines wraps each line in a message event, passing JSON lines on as they are.
+type jsonLines struct {
+	out     io.Writer
+	partial []byte
+}
+
+func (w *jsonLines) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		end := bytes.IndexByte(w.partial, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		line := bytes.TrimSpace(w.partial[:end])
+		w.partial = w.partial[end+1:]
+
+		var err error
+		switch {
+		case len(line) == 0:
+		case line[0] == '{' && json.Valid(line):
+			_, err = w.out.Write(append(line, '\n'))
+		default:
+			err = encodeJSON(w.out, messageEvent{Event: "message", Message: string(line)})
+		}
+		if err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// WriteLeague prints the league in locale as output asks.
+func WriteLeague(w io.Writer, league League, output Output, locale *Locale) error {
+	switch output {
+	case OutputJSON:
+		if league == nil {
+			league = League{}
+		}
+		return encodeJSON(w, league)
+	case OutputTable:
+		table := newTable(w)
+		header(table, locale, "", "Player", "Wins", "Net")
+		for i, player := range league {
+			fmt.Fprintf(table, "%d.\t%s\t%s\t%s\t\n", i+1, player.Name, locale.Number(player.Wins), locale.Signed(player.Net))
+		}
+		return table.Flush()
+	}
+
+	if len(league) == 0 {
+		fmt.Fprintln(w, locale.Text("Nobody has played yet"))
+	}
+	league.write(w, locale)
+	return nil
+}
+
+// WriteStats prints each player's record in locale as output asks.
+func WriteStats(w io.Writer, records []PlayerStats, output Output, locale *Locale) error {
+	switch output {
+	case OutputJSON:
+		if records == nil {
+			records = []PlayerStats{}
+		}
+		return encodeJSON(w, records)
+	case OutputTable:
+		table := newTable(w)
+		header(table, locale, "Player", "Wins", "Tournaments", "Won", "Best", "Cashed", "Winnings", "Cash games", "Net", "Owed")
+		for _, r := range records {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+				r.Player, locale.Number(r.Wins), locale.Number(r.Tournaments), locale.Number(r.TournamentWins), r.best(locale),
+				locale.Number(r.InTheMoney), locale.Signed(r.Winnings), locale.Number(r.CashGames), locale.Signed(r.Net), locale.Signed(r.Owed))
+		}
+		return table.Flush()
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintln(w, locale.Text("Nobody has played yet"))
+	}
+	for _, r := range records {
+		line := locale.Sprintf("%s: %s, %s", r.Player, locale.Plural(r.Wins, "%s win", "%s wins"), locale.Plural(r.Tournaments, "%s tournament", "%s tournaments"))
+		if r.Tournaments > 0 {
+			line += locale.Sprintf(" (won %s, best %s, cashed %s for %s)", locale.Number(r.TournamentWins), r.best(locale), locale.Number(r.InTheMoney), locale.Signed(r.Winnings))
+		}
+		line += ", " + locale.Plural(r.CashGames, "%s cash game", "%s cash games")
+		if r.CashGames > 0 {
+			line += locale.Sprintf(" (net %s)", locale.Signed(r.Net))
+		}
+		switch {
+		case r.Owed > 0:
+			line += locale.Sprintf(", owed %s", locale.Number(r.Owed))
+		case r.Owed < 0:
+			line += locale.Sprintf(", owes %s", locale.Number(-r.Owed))
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+func (s PlayerStats) best(locale *Locale) string {
+	if s.BestPlace == 0 {
+		return "-"
+	}
+	return locale.Ordinal(s.BestPlace)
+}
+
+// WritePayments prints who pays whom in locale as output asks.
+func WritePayments(w io.Writer, payments []Payment, output Output, locale *Locale) error {
+	switch output {
+	case OutputJSON:
+		if payments == nil {
+			payments = []Payment{}
+		}
+		return encodeJSON(w, payments)
+	case OutputTable:
+		table := newTable(w)
+		header(table, locale, "From", "To", "Amount")
+		for _, payment := range payments {
+			fmt.Fprintf(table, "%s\t%s\t%s\t\n", payment.From, payment.To, locale.Number(payment.Amount))
+		}
+		return table.Flush()
+	}
+
+	if len(payments) == 0 {
+		fmt.Fprintln(w, locale.Text("Everyone is square"))
+	}
+	for _, payment := range payments {
+		fmt.Fprintln(w, locale.Sprintf("%s pays %s %s", payment.From, payment.To, locale.Number(payment.Amount)))
+	}
+	return nil
+}
+
+// WriteBlindStructure prints structure; its JSON reads back with LoadBlindStructure.
+func WriteBlindStructure(w io.Writer, structure BlindStructure, output Output, locale *Locale) error {
+	switch output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(structure)
+	case OutputTable:
+		table := newTable(w)
+		header(table, locale, "Level", "Small blind", "Big blind", "Ante", "Minutes")
+		level := 0
+		for _, l := range structure.Levels {
+			minutes := locale.Number(int(l.Duration.Minutes()))
+			if l.Break {
+				fmt.Fprintf(table, "%s\t\t\t\t%s\t\n", locale.Text("Break"), minutes)
+				continue
+			}
+			level++
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t\n", level, locale.Number(l.SmallBlind), locale.Number(l.BigBlind), locale.Number(l.Ante), minutes)
+		}
+		return table.Flush()
+	}
+
+	if structure.Name != "" {
+		fmt.Fprintln(w, structure.Name)
+	}
+	level := 0
+	for _, l := range structure.Levels {
+		minutes := locale.Plural(int(l.Duration.Minutes()), "%s minute", "%s minutes")
+		if l.Break {
+			fmt.Fprintln(w, locale.Sprintf("Break for %s", minutes))
+			continue
+		}
+		level++
+		fmt.Fprintln(w, locale.Sprintf("Level %d: %s for %s", level, locale.Blinds(l), minutes))
+	}
+	return nil
+}
+
+// WriteSyncResults prints what a server did with each journal entry.
+func WriteSyncResults(w io.Writer, results []SyncResult, output Output, locale *Locale) error {
+	switch output {
+	case OutputJSON:
+		if results == nil {
+			results = []SyncResult{}
+		}
+		return encodeJSON(w, results)
+	case OutputTable:
+		table := newTable(w)
+		header(table, locale, "Status", "Entry", "Reason")
+		for _, r := range results {
+			fmt.Fprintf(table, "%s\t%s\t%s\t\n", locale.Text(string(r.Status)), locale.Entry(r.Entry), locale.Text(r.Reason))
+		}
+		return table.Flush()
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(w, locale.Text("Nothing to sync"))
+		return nil
+	}
+	counts := map[SyncStatus]int{}
+	for _, r := range results {
+		line := locale.Sprintf("%s: %s", locale.Text(string(r.Status)), locale.Entry(r.Entry))
+		if r.Reason != "" {
+			line += " (" + locale.Text(r.Reason) + ")"
+		}
+		fmt.Fprintln(w, line)
+		counts[r.Status]++
+	}
+	fmt.Fprintln(w, locale.Sprintf("%s merged, %s duplicate, %s in conflict, %s rejected",
+		locale.Number(counts[SyncMerged]), locale.Number(counts[SyncDuplicate]), locale.Number(counts[SyncConflict]), locale.Number(counts[SyncRejected])))
+	return nil
+}
+
+// header writes a table's column names in locale.
+func header(table io.Writer, locale *Locale, columns ...string) {
+	for _, column := range columns {
+		fmt.Fprintf(table, "%s\t", locale.Text(column))
+	}
+	fmt.Fprintln(table)
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+}
+
+// encodeJSON writes v as one line of JSON without escaping < > and &.
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
@@ -0,0 +1,165 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/output")
+
+func TestWriteOutput(t *testing.T) {
+	league := League{{"Cleo", 3, -20}, {"Chris", 1, 20}}
+	records := []PlayerStats{
+		{Player: "Cleo", Wins: 3, Tournaments: 4, TournamentWins: 3, BestPlace: 1, InTheMoney: 4, Winnings: 450, CashGames: 1, Net: -20, Owed: -20},
+		{Player: "Chris", Wins: 1, CashGames: 1, Net: 20, Owed: 20},
+	}
+	payments := []Payment{{From: "Cleo", To: "Chris", Amount: 20}}
+	started := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	synced := []SyncResult{
+		{ID: "1", Status: SyncMerged, Entry: JournalEntry{ID: "1", Win: "Chris"}},
+		{ID: "2", Status: SyncDuplicate, Reason: "it was synced before", Entry: JournalEntry{ID: "2", Payment: &Payment{From: "Cleo", To: "Chris", Amount: 1500}}},
+		{ID: "3", Status: SyncConflict, Reason: "a different tournament started at the same time", Entry: JournalEntry{ID: "3", Tournament: &TournamentResult{
+			Started: started, Placings: []Placing{{Place: 1, Player: "Cleo"}},
+		}}},
+	}
+	structure := BlindStructure{Name: "short", Levels: []BlindLevel{
+		{SmallBlind: 25, BigBlind: 50, Duration: 20 * time.Minute},
+		{SmallBlind: 50, BigBlind: 100, Duration: 20 * time.Minute},
+		{Break: true, Duration: 10 * time.Minute},
+		{SmallBlind: 100, BigBlind: 200, Ante: 25, Duration: 20 * time.Minute},
+	}}
+
+	writers := map[string]func(io.Writer, Output) error{
+		"league":   func(w io.Writer, o Output) error { return WriteLeague(w, league, o, English) },
+		"stats":    func(w io.Writer, o Output) error { return WriteStats(w, records, o, English) },
+		"payments": func(w io.Writer, o Output) error { return WritePayments(w, payments, o, English) },
+		"blinds":   func(w io.Writer, o Output) error { return WriteBlindStructure(w, structure, o, English) },
+		"sync":     func(w io.Writer, o Output) error { return WriteSyncResults(w, synced, o, English) },
+	}
+
+	for name, write := range writers {
+		for _, output := range []Output{OutputPlain, OutputTable, OutputJSON} {
+			t.Run(name+" as "+string(output), func(t *testing.T) {
+				var got bytes.Buffer
+				if err := write(&got, output); err != nil {
+					t.Fatal(err)
+				}
+				assertGolden(t, name+"."+string(output), got.Bytes())
+			})
+		}
+	}
+
+	t.Run("nothing is an empty array in JSON", func(t *testing.T) {
+		var got bytes.Buffer
+		WriteLeague(&got, nil, OutputJSON, nil)
+		WriteStats(&got, nil, OutputJSON, nil)
+		WritePayments(&got, nil, OutputJSON, nil)
+
+		if got.String() != "[]\n[]\n[]\n" {
+			t.Errorf("got %q", got.String())
+		}
+	})
+}
+
+func TestParseOutput(t *testing.T) {
+	for _, name := range []string{"plain", "table", "JSON"} {
+		if _, err := ParseOutput(name); err != nil {
+			t.Errorf("didn't expect an error for %q, %v", name, err)
+		}
+	}
+	if _, err := ParseOutput("yaml"); err == nil {
+		t.Error("expected an error for yaml")
+	}
+}
+
+func TestCLIEvents(t *testing.T) {
+	clock := NewManualClock(time.Time{})
+	store := &StubPlayerStore{league: League{{"Cleo", 2, 0}}}
+	game := NewTexasHoldem(store, NewJSONAlerter(clock), clock)
+
+	in := &script{clock: clock, lines: []string{
+		"league",
+		"pause",
+		"start Chris Cleo",
+		"score Cleo",
+		"# advance 10m",
+		"pause",
+		"screen",
+		"undo",
+		"Clio wins",
+		"y",
+		"n",
+		"Cleo wins",
+		"y",
+		"quit",
+	}}
+	var out bytes.Buffer
+
+	cli := NewCLI(in, &out, game)
+	cli.UseStore(store)
+	cli.UseOutput(OutputJSON)
+	cli.PlayPoker()
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event struct {
+			Event string `json:"event"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Event == "" {
+			t.Errorf("%q is not an event", line)
+		}
+	}
+	assertGolden(t, "play.ndjson", out.Bytes())
+}
+
+// script gives the CLI a line at a time, moving the clock on at each
+// "# advance" line instead.
+type script struct {
+	clock *ManualClock
+	lines []string
+}
+
+func (s *script) Read(p []byte) (int, error) {
+	for len(s.lines) > 0 {
+		line := s.lines[0]
+		s.lines = s.lines[1:]
+
+		if d, ok := strings.CutPrefix(line, "# advance "); ok {
+			duration, _ := time.ParseDuration(d)
+			s.clock.Advance(duration)
+			continue
+		}
+		return copy(p, line+"\n"), nil
+	}
+	return 0, io.EOF
+}
+
+// assertGolden compares got with testdata/output/name.golden, or rewrites
+// the file when the tests are run with -update.
+func assertGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "output", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("problem reading the golden file, run go test -update to write it, %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is out of date, got\n%s\nwant\n%s", path, got, want)
+	}
+}
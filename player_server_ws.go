@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// writeWait is how long a browser has to take each message.
+const writeWait = 10 * time.Second
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// playerServerWS lets a game write straight to a browser.
+type playerServerWS struct {
+	mu sync.Mutex
+	*websocket.Conn
+}
+
+func newPlayerServerWS(w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &playerServerWS{Conn: conn}, nil
+}
+
+func (w *playerServerWS) WaitForMsg() (string, error) {
+	_, msg, err := w.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+	return string(msg), nil
+}
+
+// Write sends p as one message, serialised and failing after writeWait.
+func (w *playerServerWS) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.SetWriteDeadline(time.Now().Add(writeWait))
+	err = w.WriteMessage(websocket.TextMessage, p)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// goingAway sends the browser a going away close and closes the connection.
+func (w *playerServerWS) goingAway(deadline time.Time) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "the server is shutting down")
+	w.WriteControl(websocket.CloseMessage, message, deadline)
+	w.Close()
+}
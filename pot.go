@@ -0,0 +1,164 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Pot is the main pot or a side pot, and the seats that can win it.
+type Pot struct {
+	Amount   int
+	Eligible []int
+}
+
+// Pots splits the chips into the main pot and a side pot for each all-in.
+func (h *Hand) Pots() []Pot {
+	var caps []int
+	top := 0
+	for _, s := range h.Seats {
+		if !s.Folded && s.AllIn {
+			caps = append(caps, s.InPot)
+		}
+		top = max(top, s.InPot)
+	}
+	caps = append(caps, top)
+	sort.Ints(caps)
+
+	var pots []Pot
+	previous := 0
+	for _, limit := range caps {
+		if limit == previous {
+			continue
+		}
+
+		pot := Pot{}
+		for i, s := range h.Seats {
+			pot.Amount += max(min(s.InPot, limit)-previous, 0)
+			if !s.Folded && s.InPot >= limit {
+				pot.Eligible = append(pot.Eligible, i)
+			}
+		}
+		previous = limit
+
+		// a pot nobody new can win belongs with the one below it.
+		if last := len(pots) - 1; last >= 0 && (len(pot.Eligible) == 0 || sameSeats(pots[last].Eligible, pot.Eligible)) {
+			pots[last].Amount += pot.Amount
+			continue
+		}
+		pots = append(pots, pot)
+	}
+
+	return pots
+}
+
+func sameSeats(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// showdown pays each pot, giving the odd chip of a split pot to the high hand.
+func (h *Hand) showdown() {
+	values := make([]HandValue, len(h.Seats))
+	lows := make([]LowHand, len(h.Seats))
+	hasLow := make([]bool, len(h.Seats))
+	for i, s := range h.Seats {
+		if s.Folded {
+			continue
+		}
+		values[i] = h.value(s)
+		shows := values[i].String()
+		if h.variant.Low != nil {
+			if lows[i], hasLow[i] = h.variant.Low(s.Cards, h.Board); hasLow[i] {
+				shows += ", " + lows[i].String()
+			}
+		}
+		fmt.Fprintf(h.out, "%s shows %s (%s)\n", s.Player, formatCards(s.Cards), shows)
+	}
+
+	pots := h.Pots()
+	for i, pot := range pots {
+		best := HandValue(0)
+		for _, seat := range pot.Eligible {
+			best = max(best, values[seat])
+		}
+
+		var winners []int
+		for _, seat := range pot.Eligible {
+			if values[seat] == best {
+				winners = append(winners, seat)
+			}
+		}
+
+		name := ""
+		if len(pots) > 1 {
+			name = " from the main pot"
+			if i > 0 {
+				name = fmt.Sprintf(" from side pot %d", i)
+			}
+		}
+
+		lowWinners, low := h.lowestHands(pot, lows, hasLow)
+		if len(lowWinners) == 0 {
+			h.splitPot(pot.Amount, winners, fmt.Sprintf("%s with %v", name, best))
+			continue
+		}
+		half := pot.Amount / 2
+		h.splitPot(pot.Amount-half, winners, fmt.Sprintf("%s with %v", name, best))
+		h.splitPot(half, lowWinners, fmt.Sprintf("%s with %v", name, low))
+	}
+
+	h.Pot = 0
+	h.finished = true
+}
+
+// lowestHands finds who has the best qualifying low of those who can win pot.
+func (h *Hand) lowestHands(pot Pot, lows []LowHand, hasLow []bool) ([]int, LowHand) {
+	var winners []int
+	var best LowHand
+	for _, seat := range pot.Eligible {
+		switch {
+		case !hasLow[seat]:
+		case len(winners) == 0 || lows[seat] < best:
+			winners, best = []int{seat}, lows[seat]
+		case lows[seat] == best:
+			winners = append(winners, seat)
+		}
+	}
+	return winners, best
+}
+
+// splitPot gives the odd chips to the winners closest left of the button.
+func (h *Hand) splitPot(amount int, winners []int, how string) {
+	share, oddChips := amount/len(winners), amount%len(winners)
+
+	for i := 1; i <= len(h.Seats); i++ {
+		seat := (h.Button + i) % len(h.Seats)
+		if !containsSeat(winners, seat) {
+			continue
+		}
+
+		won := share
+		if oddChips > 0 {
+			won++
+			oddChips--
+		}
+		h.Seats[seat].Stack += won
+		fmt.Fprintf(h.out, "%s wins %d%s\n", h.Seats[seat].Player, won, how)
+	}
+}
+
+func containsSeat(seats []int, seat int) bool {
+	for _, s := range seats {
+		if s == seat {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package poker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// riggedDeck stacks a deck so the seats, button first, get the hole cards
+// given and the board comes out as written. The burn cards are whatever is
+// left.
+func riggedDeck(t testing.TB, holes []string, board string) *Deck {
+	t.Helper()
+
+	used := map[Card]bool{}
+	parse := func(text string) []Card {
+		cards, err := ParseCards(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, card := range cards {
+			if used[card] {
+				t.Fatalf("%v is rigged twice", card)
+			}
+			used[card] = true
+		}
+		return cards
+	}
+
+	hands := make([][]Card, len(holes))
+	for i, hole := range holes {
+		hands[i] = parse(hole)
+	}
+	runout := parse(board)
+
+	var spare []Card
+	for _, card := range NewDeck().cards {
+		if !used[card] {
+			spare = append(spare, card)
+		}
+	}
+
+	// hole cards go round one at a time starting left of the button.
+	var cards []Card
+	for round := 0; round < len(hands[0]); round++ {
+		for i := range hands {
+			cards = append(cards, hands[(i+1)%len(hands)][round])
+		}
+	}
+	cards = append(cards, spare[0], runout[0], runout[1], runout[2], spare[1], runout[3], spare[2], runout[4])
+
+	return &Deck{cards: append(cards, spare[3:]...)}
+}
+
+func TestPots(t *testing.T) {
+	t.Run("each all-in caps a pot", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 2000, 500, 1000, 2000)
+
+		hand.Seats[0].InPot, hand.Seats[1].InPot, hand.Seats[2].InPot, hand.Seats[3].InPot = 2000, 500, 1000, 2000
+		hand.Seats[1].AllIn, hand.Seats[2].AllIn = true, true
+
+		assertPots(t, hand.Pots(), []Pot{
+			{Amount: 2000, Eligible: []int{0, 1, 2, 3}},
+			{Amount: 1500, Eligible: []int{0, 2, 3}},
+			{Amount: 2000, Eligible: []int{0, 3}},
+		})
+	})
+
+	t.Run("folded chips stay in but cannot be won by the folder", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		hand.Seats[0].InPot, hand.Seats[1].InPot, hand.Seats[2].InPot = 600, 300, 400
+		hand.Seats[1].AllIn = true
+		hand.Seats[2].Folded = true
+
+		assertPots(t, hand.Pots(), []Pot{
+			{Amount: 900, Eligible: []int{0, 1}},
+			{Amount: 400, Eligible: []int{0}},
+		})
+	})
+
+	t.Run("all-ins for the same amount share a pot", func(t *testing.T) {
+		hand, _ := newTestHand(t, blinds50100, 1000, 1000, 1000)
+
+		hand.Seats[0].InPot, hand.Seats[1].InPot, hand.Seats[2].InPot = 300, 300, 300
+		hand.Seats[0].AllIn, hand.Seats[1].AllIn = true, true
+
+		assertPots(t, hand.Pots(), []Pot{{Amount: 900, Eligible: []int{0, 1, 2}}})
+	})
+}
+
+func TestReplayedHands(t *testing.T) {
+	cases := []struct {
+		name   string
+		blinds BlindLevel
+		stacks []int
+		holes  []string
+		board  string
+		moves  []string
+		want   []int
+	}{
+		{
+			name:   "a four way all-in makes a main pot and two side pots",
+			blinds: blinds50100,
+			stacks: []int{2000, 500, 1000, 2000},
+			holes:  []string{"Qs Qd", "As Ad", "Ks Kd", "4h 5d"},
+			board:  "2c 7d 9h Js 3c",
+			moves:  []string{"all-in", "call", "all-in", "all-in"},
+			want:   []int{2000, 2000, 1500, 0},
+		},
+		{
+			name:   "the big stack wins everything it covers",
+			blinds: blinds50100,
+			stacks: []int{2000, 500, 1000, 2000},
+			holes:  []string{"Qs Qd", "As Ad", "Ks Kd", "Jh Jd"},
+			board:  "2c 7d 9h Js 3c",
+			moves:  []string{"all-in", "call", "all-in", "all-in"},
+			want:   []int{0, 0, 0, 5500},
+		},
+		{
+			name:   "a folded player's chips go to the winner",
+			blinds: blinds50100,
+			stacks: []int{1000, 1000, 1000},
+			holes:  []string{"Ah Kh", "Qc Jc", "7s 2d"},
+			board:  "As 8d 4c 3h Td",
+			moves:  []string{"raise 300", "call", "fold", "all-in", "call"},
+			want:   []int{2100, 0, 900},
+		},
+		{
+			name:   "a short all-in only wins what it covered",
+			blinds: blinds50100,
+			stacks: []int{1000, 1000, 250},
+			holes:  []string{"Kc Kh", "Qc Qh", "Ac Ah"},
+			board:  "2s 5d 8c 9h Jd",
+			moves:  []string{"raise 200", "call", "all-in", "call", "call", "bet 400", "call", "check", "check", "check", "check"},
+			want:   []int{1150, 350, 750},
+		},
+		{
+			name:   "a split pot gives the odd chip to the first winner left of the button",
+			blinds: BlindLevel{SmallBlind: 50, BigBlind: 100, Ante: 5},
+			stacks: []int{1000, 1000, 1000},
+			holes:  []string{"6h Kd", "Kh Kc", "6d Qc"},
+			board:  "2c 3d 4h 5s 9c",
+			moves:  []string{"call", "call", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check"},
+			want:   []int{895 + 157, 895, 895 + 158},
+		},
+		{
+			name:   "a split side pot under a main pot won outright",
+			blinds: blinds50100,
+			stacks: []int{1000, 400, 1000},
+			holes:  []string{"Th 9h", "As Ad", "Td 9d"},
+			board:  "2c 3d 4h 5s Kc",
+			moves:  []string{"all-in", "call", "call"},
+			want:   []int{600, 1200, 600},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hand, out := newHandFrom(t, riggedDeck(t, c.holes, c.board), c.blinds, c.stacks...)
+
+			play(t, hand, c.moves...)
+
+			if !hand.Finished() {
+				t.Fatalf("expected the hand to be over, got %s", out.String())
+			}
+			assertStacks(t, hand, c.want...)
+			assertChipsKept(t, hand, c.stacks)
+		})
+	}
+}
+
+func TestWagers(t *testing.T) {
+	hand, _ := newHandFrom(t, riggedDeck(t, []string{"Ah Kh", "Qc Jc", "7s 2d"}, "As 8d 4c 3h Td"), blinds50100, 1000, 1000, 1000)
+
+	play(t, hand, "raise 300", "call", "fold", "all-in", "call")
+
+	if got := hand.Wagered(Preflop); got != 700 {
+		t.Errorf("got %d wagered preflop, want 700", got)
+	}
+	if got := hand.Wagered(Flop); got != 1400 {
+		t.Errorf("got %d wagered on the flop, want 1400", got)
+	}
+
+	want := Wager{Flop, "B", AllIn, 700, false}
+	if !containsWager(hand.Wagers, want) {
+		t.Errorf("expected %+v in %+v", want, hand.Wagers)
+	}
+}
+
+func TestShowdownAnnouncesPots(t *testing.T) {
+	deck := riggedDeck(t, []string{"Qs Qd", "As Ad", "Ks Kd", "4h 5d"}, "2c 7d 9h Js 3c")
+	hand, out := newHandFrom(t, deck, blinds50100, 2000, 500, 1000, 2000)
+
+	play(t, hand, "all-in", "call", "all-in", "all-in")
+
+	for _, want := range []string{
+		"B shows As Ad (pair of As)\n",
+		"B wins 2000 from the main pot with pair of As\n",
+		"C wins 1500 from side pot 1 with pair of Ks\n",
+		"A wins 2000 from side pot 2 with pair of Qs\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in %q", want, out.String())
+		}
+	}
+}
+
+func containsWager(wagers []Wager, want Wager) bool {
+	for _, w := range wagers {
+		if w == want {
+			return true
+		}
+	}
+	return false
+}
+
+func assertPots(t testing.TB, got, want []Pot) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got pots %+v want %+v", got, want)
+	}
+}
+
+func assertChipsKept(t testing.TB, hand *Hand, stacks []int) {
+	t.Helper()
+
+	before, after := 0, 0
+	for i, seat := range hand.Seats {
+		before += stacks[i]
+		after += seat.Stack
+	}
+	if before != after {
+		t.Errorf("started with %d chips and ended with %d", before, after)
+	}
+}
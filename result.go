@@ -0,0 +1,123 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResultCommandsHelp is how results are typed.
+const ResultCommandsHelp = `{Name} wins, {Name} 1st or {Name} out, with "quotes" around a name with spaces`
+
+// ErrNotAResult is for input that may be something else.
+var ErrNotAResult = errors.New("not a result")
+
+// ResultLine is a result typed at the table: who won, or who went out.
+type ResultLine struct {
+	Player string
+	Out    bool
+}
+
+func (r ResultLine) String() string {
+	if r.Out {
+		return r.Player + " out"
+	}
+	return r.Player + " wins"
+}
+
+// ParseResult reads "<name> wins", "<name> 1st" or "<name> out"; the name may be quoted.
+func ParseResult(input string) (ResultLine, error) {
+	input = strings.TrimSpace(input)
+
+	name, keyword := "", input
+	if space := strings.LastIndexAny(input, " \t"); space >= 0 {
+		name, keyword = strings.TrimSpace(input[:space]), input[space+1:]
+	}
+
+	var result ResultLine
+	switch strings.ToLower(keyword) {
+	case "wins", "1st":
+	case "out":
+		result.Out = true
+	default:
+		return ResultLine{}, ErrNotAResult
+	}
+
+	if strings.HasPrefix(name, `"`) {
+		if len(name) < 2 || !strings.HasSuffix(name, `"`) {
+			return ResultLine{}, fmt.Errorf("%s has no closing quote", name)
+		}
+		name = name[1 : len(name)-1]
+	}
+	if strings.Contains(name, `"`) {
+		return ResultLine{}, fmt.Errorf(`put quotes around the whole name, like "Mary Ann" %s`, keyword)
+	}
+
+	result.Player = strings.Join(strings.Fields(name), " ")
+	if result.Player == "" {
+		return ResultLine{}, fmt.Errorf("who? type {Name} %s", strings.ToLower(keyword))
+	}
+	return result, nil
+}
+
+// MatchPlayer finds name ignoring case, or else the likely typos, closest first.
+func MatchPlayer(name string, players []string) (string, []string) {
+	for _, player := range players {
+		if strings.EqualFold(player, name) {
+			return player, nil
+		}
+	}
+
+	type suggestion struct {
+		player   string
+		distance int
+	}
+	var close []suggestion
+	lower := strings.ToLower(name)
+	for _, player := range players {
+		candidate := strings.ToLower(player)
+		distance := editDistance(lower, candidate)
+		if distance <= typos(candidate) || strings.HasPrefix(candidate, lower) {
+			close = append(close, suggestion{player, distance})
+		}
+	}
+	sort.SliceStable(close, func(i, j int) bool { return close[i].distance < close[j].distance })
+
+	var suggestions []string
+	for _, s := range close {
+		suggestions = append(suggestions, s.player)
+	}
+	return "", suggestions
+}
+
+// typos is how many slips a name can take and still be recognised.
+func typos(name string) int {
+	if len([]rune(name)) < 5 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance is the letters to add, remove or change to turn a into b.
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	previous := make([]int, len(y)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		current := make([]int, len(y)+1)
+		current[0] = i
+		for j := 1; j <= len(y); j++ {
+			change := previous[j-1]
+			if x[i-1] != y[j-1] {
+				change++
+			}
+			current[j] = min(change, previous[j]+1, current[j-1]+1)
+		}
+		previous = current
+	}
+	return previous[len(y)]
+}
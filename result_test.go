@@ -0,0 +1,73 @@
+package poker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseResult(t *testing.T) {
+	cases := []struct {
+		input string
+		want  ResultLine
+	}{
+		{"Chris wins", ResultLine{Player: "Chris"}},
+		{"Chris WINS", ResultLine{Player: "Chris"}},
+		{"Cleo 1st", ResultLine{Player: "Cleo"}},
+		{"Dan out", ResultLine{Player: "Dan", Out: true}},
+		{`"Mary Ann" wins`, ResultLine{Player: "Mary Ann"}},
+		{"  Mary   Ann  wins ", ResultLine{Player: "Mary Ann"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			got, err := ParseResult(c.input)
+			assertNoError(t, err)
+			if got != c.want {
+				t.Errorf("got %+v want %+v", got, c.want)
+			}
+		})
+	}
+
+	t.Run("anything else is not a result", func(t *testing.T) {
+		for _, input := range []string{"", "Chris", "pause", "raise 300", "Chris rebuys"} {
+			if _, err := ParseResult(input); !errors.Is(err, ErrNotAResult) {
+				t.Errorf("%q: got %v want ErrNotAResult", input, err)
+			}
+		}
+	})
+
+	t.Run("results without a proper name are refused", func(t *testing.T) {
+		for _, input := range []string{"wins", `"" wins`, `"Mary Ann wins`, `Mary "Ann" wins`} {
+			_, err := ParseResult(input)
+			if err == nil || errors.Is(err, ErrNotAResult) {
+				t.Errorf("%q: got %v", input, err)
+			}
+		}
+	})
+}
+
+func TestMatchPlayer(t *testing.T) {
+	players := []string{"Chris", "Cleo", "Christine", "Dan"}
+
+	cases := []struct {
+		name        string
+		player      string
+		suggestions []string
+	}{
+		{"chris", "Chris", nil},
+		{"Chirs", "", []string{"Chris"}},
+		{"Clo", "", []string{"Cleo"}},
+		{"Chri", "", []string{"Chris", "Christine"}},
+		{"Bob", "", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			player, suggestions := MatchPlayer(c.name, players)
+			if player != c.player || !reflect.DeepEqual(suggestions, c.suggestions) {
+				t.Errorf("got %q and %v want %q and %v", player, suggestions, c.player, c.suggestions)
+			}
+		})
+	}
+}
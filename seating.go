@@ -0,0 +1,342 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// DefaultTableSize is the seats at a table when a tournament does not say.
+const DefaultTableSize = 9
+
+// SeatAssignment is where a player sits. Tables and seats count from one.
+type SeatAssignment struct {
+	Player string `json:"player"`
+	Table  int    `json:"table"`
+	Seat   int    `json:"seat"`
+}
+
+func (a SeatAssignment) String() string {
+	return fmt.Sprintf("%s: table %d seat %d", a.Player, a.Table, a.Seat)
+}
+
+// TableMove is a player moved to balance the tables or from a broken one.
+type TableMove struct {
+	Player    string `json:"player"`
+	FromTable int    `json:"from_table"`
+	FromSeat  int    `json:"from_seat"`
+	ToTable   int    `json:"to_table"`
+	ToSeat    int    `json:"to_seat"`
+	Broken    bool   `json:"broken,omitempty"`
+}
+
+func (m TableMove) String() string {
+	move := fmt.Sprintf("move %s from table %d seat %d to table %d seat %d", m.Player, m.FromTable, m.FromSeat, m.ToTable, m.ToSeat)
+	if m.Broken {
+		return fmt.Sprintf("Table %d breaks, %s", m.FromTable, move)
+	}
+	return "To balance the tables, " + move
+}
+
+// SeatingTable is one table of a draw; an empty seat is "".
+type SeatingTable struct {
+	Number int      `json:"number"`
+	Seats  []string `json:"seats"`
+	Button int      `json:"button"`
+}
+
+// Players is how many seats are taken.
+func (t *SeatingTable) Players() int {
+	players := 0
+	for _, player := range t.Seats {
+		if player != "" {
+			players++
+		}
+	}
+	return players
+}
+
+// after returns the nth taken seat clockwise from seat.
+func (t *SeatingTable) after(seat, n int) int {
+	for i := 1; i <= len(t.Seats); i++ {
+		next := (seat + i) % len(t.Seats)
+		if t.Seats[next] != "" {
+			if n--; n == 0 {
+				return next
+			}
+		}
+	}
+	return seat
+}
+
+// emptySeat is the first empty seat after the button, or -1.
+func (t *SeatingTable) emptySeat() int {
+	for i := 1; i <= len(t.Seats); i++ {
+		seat := (t.Button + i) % len(t.Seats)
+		if t.Seats[seat] == "" {
+			return seat
+		}
+	}
+	return -1
+}
+
+func (t *SeatingTable) String() string {
+	var seats []string
+	for i, player := range t.Seats {
+		if player == "" {
+			continue
+		}
+		seat := fmt.Sprintf("seat %d %s", i+1, player)
+		if i == t.Button {
+			seat += " (button)"
+		}
+		seats = append(seats, seat)
+	}
+	return fmt.Sprintf("Table %d: %s", t.Number, strings.Join(seats, ", "))
+}
+
+// Seating balances tables as players go out; movers are whoever has the big blind next.
+type Seating struct {
+	TableSize int              `json:"table_size"`
+	Tables    []*SeatingTable  `json:"tables"`
+	Draw      []SeatAssignment `json:"draw"`
+	Moves     []TableMove      `json:"moves,omitempty"`
+}
+
+// DrawSeats seats players at random at as few tables as they fit.
+func DrawSeats(players []string, tableSize int, rng *rand.Rand) (*Seating, error) {
+	if tableSize < MinSeats || tableSize > MaxSeats {
+		return nil, fmt.Errorf("a table seats %d to %d players, not %d", MinSeats, MaxSeats, tableSize)
+	}
+	if len(players) < MinSeats {
+		return nil, fmt.Errorf("a seat draw needs at least %d players", MinSeats)
+	}
+	seen := map[string]bool{}
+	for _, player := range players {
+		if player == "" {
+			return nil, errors.New("every player in the draw needs a name")
+		}
+		if seen[player] {
+			return nil, fmt.Errorf("%s is in the draw twice", player)
+		}
+		seen[player] = true
+	}
+
+	shuffled := append([]string{}, players...)
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	tables := (len(shuffled) + tableSize - 1) / tableSize
+	seating := &Seating{TableSize: tableSize}
+	for number := 1; number <= tables; number++ {
+		seating.Tables = append(seating.Tables, &SeatingTable{Number: number, Seats: make([]string, tableSize)})
+	}
+
+	seats := make([][]int, tables)
+	for i := range seats {
+		seats[i] = rng.Perm(tableSize)
+	}
+	for i, player := range shuffled {
+		table := seating.Tables[i%tables]
+		table.Seats[seats[i%tables][i/tables]] = player
+	}
+
+	for _, table := range seating.Tables {
+		taken := table.Players()
+		table.Button = table.after(table.Button, 1+rng.Intn(taken))
+		for seat, player := range table.Seats {
+			if player != "" {
+				seating.Draw = append(seating.Draw, SeatAssignment{Player: player, Table: table.Number, Seat: seat + 1})
+			}
+		}
+	}
+	return seating, nil
+}
+
+// Find reports where player is sitting.
+func (s *Seating) Find(player string) (SeatAssignment, bool) {
+	for _, table := range s.Tables {
+		for seat, seated := range table.Seats {
+			if seated == player {
+				return SeatAssignment{Player: player, Table: table.Number, Seat: seat + 1}, true
+			}
+		}
+	}
+	return SeatAssignment{}, false
+}
+
+// Players is how many players are still seated.
+func (s *Seating) Players() int {
+	players := 0
+	for _, table := range s.Tables {
+		players += table.Players()
+	}
+	return players
+}
+
+func (s *Seating) table(number int) (*SeatingTable, error) {
+	for _, table := range s.Tables {
+		if table.Number == number {
+			return table, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no table %d", number)
+}
+
+// Remove takes a player out and returns the moves that rebalance the tables.
+func (s *Seating) Remove(player string) ([]TableMove, error) {
+	at, ok := s.Find(player)
+	if !ok {
+		return nil, fmt.Errorf("%s is not seated", player)
+	}
+
+	table, _ := s.table(at.Table)
+	table.Seats[at.Seat-1] = ""
+	return s.balance(), nil
+}
+
+// Add seats a rebuy at the shortest table, opening one if all are full.
+func (s *Seating) Add(player string) (SeatAssignment, []TableMove, error) {
+	if _, ok := s.Find(player); ok {
+		return SeatAssignment{}, nil, fmt.Errorf("%s is already seated", player)
+	}
+
+	table := s.shortest(nil)
+	seat := table.emptySeat()
+	if seat < 0 {
+		table = &SeatingTable{Number: s.Tables[len(s.Tables)-1].Number + 1, Seats: make([]string, s.TableSize)}
+		s.Tables = append(s.Tables, table)
+		seat = 0
+	}
+	table.Seats[seat] = player
+
+	return SeatAssignment{Player: player, Table: table.Number, Seat: seat + 1}, s.balance(), nil
+}
+
+// MoveButton moves the button at table on and returns who has it.
+func (s *Seating) MoveButton(number int) (string, error) {
+	table, err := s.table(number)
+	if err != nil {
+		return "", err
+	}
+	if table.Players() == 0 {
+		return "", fmt.Errorf("nobody is sitting at table %d", number)
+	}
+
+	table.Button = table.after(table.Button, 1)
+	return table.Seats[table.Button], nil
+}
+
+// balance breaks and evens up the tables, recording each move.
+func (s *Seating) balance() []TableMove {
+	var moves []TableMove
+	for {
+		if len(s.Tables) > 1 && s.Players() <= (len(s.Tables)-1)*s.TableSize {
+			moves = append(moves, s.breakTable()...)
+			continue
+		}
+
+		longest, shortest := s.longest(), s.shortest(nil)
+		if longest.Players()-shortest.Players() <= 1 {
+			break
+		}
+		// three seats after the button is the big blind of the next hand.
+		moves = append(moves, s.move(longest, longest.after(longest.Button, 3), shortest, false))
+	}
+
+	s.Moves = append(s.Moves, moves...)
+	return moves
+}
+
+// breakTable moves everyone from the shortest table, highest numbered on a tie.
+func (s *Seating) breakTable() []TableMove {
+	broken := s.Tables[0]
+	for _, table := range s.Tables[1:] {
+		if table.Players() <= broken.Players() {
+			broken = table
+		}
+	}
+
+	var moves []TableMove
+	for seat, player := range broken.Seats {
+		if player != "" {
+			moves = append(moves, s.move(broken, seat, s.shortest(broken), true))
+		}
+	}
+
+	for i, table := range s.Tables {
+		if table == broken {
+			s.Tables = append(s.Tables[:i], s.Tables[i+1:]...)
+			break
+		}
+	}
+	return moves
+}
+
+func (s *Seating) move(from *SeatingTable, seat int, to *SeatingTable, broken bool) TableMove {
+	player := from.Seats[seat]
+	empty := to.emptySeat()
+	from.Seats[seat], to.Seats[empty] = "", player
+
+	return TableMove{Player: player, FromTable: from.Number, FromSeat: seat + 1, ToTable: to.Number, ToSeat: empty + 1, Broken: broken}
+}
+
+// shortest is the table with the fewest players but skip, lowest numbered on a tie.
+func (s *Seating) shortest(skip *SeatingTable) *SeatingTable {
+	var shortest *SeatingTable
+	for _, table := range s.Tables {
+		if table != skip && (shortest == nil || table.Players() < shortest.Players()) {
+			shortest = table
+		}
+	}
+	return shortest
+}
+
+// longest is the table with the most players, lowest numbered on a tie.
+func (s *Seating) longest() *SeatingTable {
+	longest := s.Tables[0]
+	for _, table := range s.Tables[1:] {
+		if table.Players() > longest.Players() {
+			longest = table
+		}
+	}
+	return longest
+}
+
+// write prints every table, then each player's seat by name.
+func (s *Seating) write(out io.Writer) {
+	for _, table := range s.Tables {
+		fmt.Fprintln(out, table)
+	}
+
+	var seated []SeatAssignment
+	for _, table := range s.Tables {
+		for seat, player := range table.Seats {
+			if player != "" {
+				seated = append(seated, SeatAssignment{Player: player, Table: table.Number, Seat: seat + 1})
+			}
+		}
+	}
+	sort.Slice(seated, func(i, j int) bool { return seated[i].Player < seated[j].Player })
+	for _, seat := range seated {
+		fmt.Fprintln(out, seat)
+	}
+}
+
+// ParseNames splits on commas, or on spaces when there are none.
+func ParseNames(input string) []string {
+	fields := strings.Fields(input)
+	if strings.Contains(input, ",") {
+		fields = strings.Split(input, ",")
+	}
+
+	var names []string
+	for _, field := range fields {
+		if name := strings.TrimSpace(field); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,205 @@
+package poker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDrawSeats(t *testing.T) {
+	t.Run("spreads everyone as evenly as it can", func(t *testing.T) {
+		players := namedPlayers(20)
+		seating, err := DrawSeats(players, 9, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertTableSizes(t, seating, 7, 7, 6)
+		if len(seating.Draw) != 20 {
+			t.Errorf("got %d players in the draw", len(seating.Draw))
+		}
+		for _, player := range players {
+			if _, ok := seating.Find(player); !ok {
+				t.Errorf("%s was not seated", player)
+			}
+		}
+		for _, table := range seating.Tables {
+			if table.Seats[table.Button] == "" {
+				t.Errorf("the button at table %d is on an empty seat", table.Number)
+			}
+		}
+	})
+
+	t.Run("the same seed draws the same seats", func(t *testing.T) {
+		first, _ := DrawSeats(namedPlayers(12), 6, rand.New(rand.NewSource(7)))
+		second, _ := DrawSeats(namedPlayers(12), 6, rand.New(rand.NewSource(7)))
+
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("got two different draws %v and %v", first.Draw, second.Draw)
+		}
+	})
+
+	t.Run("refuses draws that cannot be seated", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		for _, players := range [][]string{{"Alice"}, {"Alice", "Alice"}, {"Alice", ""}} {
+			if _, err := DrawSeats(players, 9, rng); err == nil {
+				t.Errorf("expected an error drawing seats for %q", players)
+			}
+		}
+		if _, err := DrawSeats(namedPlayers(4), 11, rng); err == nil {
+			t.Error("expected an error with eleven seats a table")
+		}
+	})
+}
+
+func TestSeating(t *testing.T) {
+	t.Run("moves the next big blind from the longest table to the shortest", func(t *testing.T) {
+		seating := &Seating{TableSize: 4, Tables: []*SeatingTable{
+			{Number: 1, Seats: []string{"A", "B", "C", "D"}, Button: 0},
+			{Number: 2, Seats: []string{"E", "F", "G", "H"}, Button: 1},
+		}}
+
+		moves, err := seating.Remove("F")
+		assertNoError(t, err)
+		if len(moves) != 0 {
+			t.Errorf("got moves %v with tables of 4 and 3", moves)
+		}
+
+		moves, _ = seating.Remove("G")
+		want := []TableMove{{Player: "D", FromTable: 1, FromSeat: 4, ToTable: 2, ToSeat: 3}}
+		if !reflect.DeepEqual(moves, want) {
+			t.Errorf("got moves %v want %v", moves, want)
+		}
+		assertTableSizes(t, seating, 3, 3)
+	})
+
+	t.Run("breaks a table once everyone fits at one fewer", func(t *testing.T) {
+		seating := &Seating{TableSize: 3, Tables: []*SeatingTable{
+			{Number: 1, Seats: []string{"A", "B", ""}},
+			{Number: 2, Seats: []string{"C", "D", ""}},
+			{Number: 3, Seats: []string{"E", "F", ""}},
+		}}
+
+		moves, _ := seating.Remove("A")
+
+		if len(moves) != 1 || !moves[0].Broken {
+			t.Fatalf("got moves %v", moves)
+		}
+		assertTableSizes(t, seating, 3, 2)
+		if got := moves[0].String(); got != "Table 1 breaks, move B from table 1 seat 2 to table 2 seat 3" {
+			t.Errorf("got %q", got)
+		}
+		if len(seating.Moves) != 1 {
+			t.Errorf("got %d moves kept", len(seating.Moves))
+		}
+	})
+
+	t.Run("rebuys sit at the shortest table", func(t *testing.T) {
+		seating := &Seating{TableSize: 3, Tables: []*SeatingTable{
+			{Number: 1, Seats: []string{"A", "B", "C"}},
+			{Number: 2, Seats: []string{"D", "", "E"}},
+		}}
+
+		seat, _, err := seating.Add("F")
+		assertNoError(t, err)
+		if seat != (SeatAssignment{Player: "F", Table: 2, Seat: 2}) {
+			t.Errorf("got %v", seat)
+		}
+		if _, _, err := seating.Add("F"); err == nil {
+			t.Error("expected an error seating F twice")
+		}
+
+		seat, moves, _ := seating.Add("G")
+		if seat.Table != 3 || len(moves) != 1 {
+			t.Errorf("expected a new table to open and be filled, got %v and %v", seat, moves)
+		}
+	})
+
+	t.Run("the button skips empty seats", func(t *testing.T) {
+		seating := &Seating{TableSize: 4, Tables: []*SeatingTable{
+			{Number: 1, Seats: []string{"A", "", "C", "D"}, Button: 0},
+		}}
+
+		for _, want := range []string{"C", "D", "A"} {
+			got, err := seating.MoveButton(1)
+			assertNoError(t, err)
+			if got != want {
+				t.Errorf("got the button on %s want %s", got, want)
+			}
+		}
+		if _, err := seating.MoveButton(2); err == nil {
+			t.Error("expected an error at a table that does not exist")
+		}
+	})
+}
+
+func TestTournamentSeating(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))
+	out := &bytes.Buffer{}
+	tournament := NewTournament(TournamentRules{BuyIn: 10, TableSize: 3}, 5, clock, out)
+
+	handled, err := tournamentCommand(tournamentGameFor(tournament), "draw A, B, C, D, E")
+	if !handled || err != nil {
+		t.Fatalf("got %v drawing seats", err)
+	}
+	if !strings.Contains(out.String(), "Table 2: ") || !strings.Contains(out.String(), "E: table ") {
+		t.Errorf("expected the draw to be announced, got %q", out.String())
+	}
+	if err := tournament.DrawSeats(namedPlayers(5)); err == nil {
+		t.Error("expected an error drawing seats twice")
+	}
+
+	tournament.Eliminate("A")
+	tournament.Eliminate("B")
+	if !strings.Contains(out.String(), "Final table:") || len(tournament.Seating().Tables) != 1 {
+		t.Errorf("expected the tables to come together, got %q", out.String())
+	}
+
+	restored, err := RestoreTournament(tournament.Checkpoint(), clock, io.Discard)
+	assertNoError(t, err)
+	restored.Eliminate("D")
+	restored.Eliminate("E")
+	result, _ := restored.Finish("C")
+	if result.Seating == nil || len(result.Seating.Draw) != 5 || len(result.Seating.Moves) == 0 {
+		t.Errorf("expected the seating to be kept with the result, got %+v", result.Seating)
+	}
+}
+
+// tournamentGameFor wraps a tournament in a game so its commands can be typed.
+func tournamentGameFor(tournament *Tournament) TournamentGame {
+	return &tournamentOnly{tournament}
+}
+
+type tournamentOnly struct {
+	tournament *Tournament
+}
+
+func (g *tournamentOnly) Start(int, io.Writer)           {}
+func (g *tournamentOnly) Finish(string)                  {}
+func (g *tournamentOnly) PlayTournament(TournamentRules) {}
+func (g *tournamentOnly) Tournament() *Tournament        { return g.tournament }
+
+func namedPlayers(n int) []string {
+	players := make([]string, n)
+	for i := range players {
+		players[i] = fmt.Sprintf("Player %d", i+1)
+	}
+	return players
+}
+
+func assertTableSizes(t testing.TB, seating *Seating, want ...int) {
+	t.Helper()
+
+	var got []int
+	for _, table := range seating.Tables {
+		got = append(got, table.Players())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tables of %v want %v", got, want)
+	}
+}
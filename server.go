@@ -1,82 +1,526 @@
 package poker
 
 import (
+	"context"
+	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
-
-	"github.com/gorilla/websocket"
+	"time"
 )
 
 const jsonContentType = "application/json"
 
+//go:embed game.html
+var gameHTML string
+
+var gameTemplate = template.Must(template.New("game").Parse(gameHTML))
+
+// loadGameTemplate reads the game.html in dir.
+func loadGameTemplate(dir string) (*template.Template, error) {
+	path := filepath.Join(dir, "game.html")
+	page, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading the game page %v", err)
+	}
+	parsed, err := template.New("game").Parse(string(page))
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing %s %v", path, err)
+	}
+	return parsed, nil
+}
+
 type PlayerStore interface {
 	GetPlayerScore(string) int
-	RecordWin(string)
+	RecordWin(string) error
 	GetLeague() League
 }
 
 type PlayerServer struct {
-	store PlayerStore
+	store   PlayerStore
+	newGame func() Game
+	games   *GameManager
+	locale  *Locale
+	page    *template.Template
+	sockets sockets
 	http.Handler
 }
 
+// Player is a line of the league: wins and the cash game net.
 type Player struct {
 	Name string `json:"name"`
 	Wins int    `json:"wins"`
+	Net  int    `json:"net"`
 }
 
-func NewPlayerServer(store PlayerStore) *PlayerServer {
+// NewPlayerServer calls newGame for each game, so each has its own clock.
+func NewPlayerServer(store PlayerStore, newGame func() Game) *PlayerServer {
 	p := new(PlayerServer)
 	p.store = store
+	p.newGame = newGame
+	p.locale = English
+	p.page = gameTemplate
+	p.games = NewGameManager(RealClock{})
+	if checkpoints, ok := store.(CheckpointStore); ok {
+		p.games.KeepCheckpoints(checkpoints)
+	}
 
 	router := http.NewServeMux()
 	router.Handle("/league", http.HandlerFunc(p.leagueHandler))
 	router.Handle("/players/", http.HandlerFunc(p.playersHandler))
 	router.Handle("/game", http.HandlerFunc(p.gameHandler))
 	router.Handle("/ws", http.HandlerFunc(p.websocket))
+	router.Handle("/blinds/design", http.HandlerFunc(p.designBlindsHandler))
+	router.Handle("/settlement", http.HandlerFunc(p.settlementHandler))
+	router.HandleFunc("POST /tournaments", p.recordTournamentHandler)
+	router.HandleFunc("POST /cash-games", p.recordCashGameHandler)
+	router.HandleFunc("PUT /journal", p.syncHandler)
+	router.HandleFunc("GET /games", p.listGamesHandler)
+	router.HandleFunc("POST /games", p.createGameHandler)
+	router.HandleFunc("GET /games/{id}", p.gameInfoHandler)
+	router.HandleFunc("POST /games/{id}/join", p.joinGameHandler)
+	router.HandleFunc("POST /games/{id}/start", p.startGameHandler)
+	router.HandleFunc("POST /games/{id}/end", p.endGameHandler)
 
 	p.Handler = router
 
 	return p
 }
 
+// UseLocale is the language for requests that do not ask for one spoken.
+func (p *PlayerServer) UseLocale(locale *Locale) {
+	p.locale = locale
+}
+
+// UseTemplates serves dir's game.html in place of the built in one.
+func (p *PlayerServer) UseTemplates(dir string) error {
+	page, err := loadGameTemplate(dir)
+	if err != nil {
+		return err
+	}
+	p.page = page
+	return nil
+}
+
+func (p *PlayerServer) localeFor(r *http.Request) *Locale {
+	return MatchLocale(r.Header.Get("Accept-Language"), p.locale)
+}
+
+// httpError answers with a message in the request's language.
+func (p *PlayerServer) httpError(w http.ResponseWriter, r *http.Request, status int, format string, a ...any) {
+	locale := p.localeFor(r)
+	w.Header().Set("content-language", locale.Name)
+	http.Error(w, locale.Sprintf(format, a...), status)
+}
+
+// gamePage is the game page's choices and language.
+type gamePage struct {
+	Variants        []string
+	BlindStructures []string
+	Locale          *Locale
+}
+
+// T is text in the page's language.
+func (g gamePage) T(text string) string {
+	return g.Locale.Text(text)
+}
+
 func (p *PlayerServer) gameHandler(w http.ResponseWriter, r *http.Request) {
-	tmpl, err := template.ParseFiles("game.html")
+	locale := p.localeFor(r)
+	w.Header().Set("content-language", locale.Name)
+	err := p.page.Execute(w, gamePage{Variants: VariantNames(), BlindStructures: BlindStructureNames(), Locale: locale})
 
 	if err != nil {
-		http.Error(w, fmt.Sprintf("problem loading template %s", err.Error()), http.StatusInternalServerError)
-		return
+		p.httpError(w, r, http.StatusInternalServerError, "problem loading template %v", err)
 	}
+}
+
+// Games is every game the server is running.
+func (p *PlayerServer) Games() *GameManager {
+	return p.games
+}
 
-	tmpl.Execute(w, nil)
+// ResumeGames picks up the games left unfinished, with their clocks paused.
+func (p *PlayerServer) ResumeGames() ([]*ManagedGame, error) {
+	var resumed []*ManagedGame
+	var errs []error
+	for _, checkpoint := range p.games.Checkpoints() {
+		checkpoint.Paused = true
+		game, err := p.games.Resume(checkpoint, p.newGame())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resumed = append(resumed, game)
+	}
+	return resumed, errors.Join(errs...)
 }
 
+// websocket joins /ws?game=<id>, or starts a game from the first message.
 func (p *PlayerServer) websocket(w http.ResponseWriter, r *http.Request) {
-	upgrader := websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
+	ws, err := newPlayerServerWS(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	if !p.sockets.add(ws) {
+		ws.goingAway(time.Now().Add(time.Second))
+		return
+	}
+	defer p.sockets.remove(ws)
+
+	if id := r.URL.Query().Get("game"); id != "" {
+		game, ok := p.games.Get(id)
+		if !ok {
+			fmt.Fprintf(ws, "there is no game %s", id)
+			return
+		}
+		defer game.Subscribe(ws)()
+		playGame(ws, game)
+		return
+	}
+
+	startMsg, err := ws.WaitForMsg()
+	if err != nil {
+		return
+	}
+
+	game, numberOfPlayers, err := p.setUpGame(startMsg)
+	if err != nil {
+		fmt.Fprint(ws, err)
+		return
+	}
+
+	_, options, _ := strings.Cut(strings.TrimSpace(startMsg), " ")
+	managed := p.games.Create(game, numberOfPlayers, strings.TrimSpace(options))
+	unsubscribe := managed.Subscribe(ws)
+	managed.Start()
+
+	playGame(ws, managed)
+
+	// nobody else can finish a game its browser walked away from
+	unsubscribe()
+	if managed.Info().Watchers == 0 {
+		managed.Abandon()
+	}
+}
+
+// Shutdown suspends every game and closes their sockets, waiting until ctx is done.
+func (p *PlayerServer) Shutdown(ctx context.Context) error {
+	p.games.SuspendAll()
+	return p.sockets.close(ctx)
+}
+
+// sockets are the websockets open to browsers, for Shutdown to close.
+type sockets struct {
+	mu      sync.Mutex
+	open    map[*playerServerWS]bool
+	closing bool
+	done    sync.WaitGroup
+}
+
+// add keeps ws until it is removed, unless the server is shutting down.
+func (s *sockets) add(ws *playerServerWS) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing {
+		return false
 	}
+	if s.open == nil {
+		s.open = map[*playerServerWS]bool{}
+	}
+	s.open[ws] = true
+	s.done.Add(1)
+	return true
+}
+
+func (s *sockets) remove(ws *playerServerWS) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.open[ws] {
+		delete(s.open, ws)
+		s.done.Done()
+	}
+}
+
+// close sends each socket a close frame, then waits for their handlers.
+func (s *sockets) close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Second)
+	}
+	for ws := range s.open {
+		ws.goingAway(deadline)
+	}
+	s.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		s.done.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("gave up waiting for games to close %w", ctx.Err())
+	}
+}
+
+// playGame plays ws's messages until the game is over; mistakes go back to ws.
+func playGame(ws *playerServerWS, game *ManagedGame) {
+	for !game.Finished() {
+		msg, err := ws.WaitForMsg()
+		if err != nil {
+			return
+		}
+
+		if err := game.Command(msg); err != nil {
+			fmt.Fprint(ws, err)
+		}
+	}
+}
+
+// setUpGame reads "<players> [options]"; "cash" takes no other options.
+func (p *PlayerServer) setUpGame(startMsg string) (Game, int, error) {
+	fields := strings.Fields(startMsg)
+	if len(fields) == 0 {
+		return nil, 0, fmt.Errorf("%q is not a number of players", startMsg)
+	}
+
+	numberOfPlayers, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("%q is not a number of players", fields[0])
+	}
+	if numberOfPlayers < 1 {
+		return nil, 0, fmt.Errorf("a game needs at least one player, not %d", numberOfPlayers)
+	}
+
+	options := fields[1:]
+	if i := slices.Index(options, "cash"); i >= 0 {
+		others := slices.Delete(slices.Clone(options), i, i+1)
+		if len(others) > 0 {
+			return nil, 0, fmt.Errorf("a cash game cannot be played with %s, it takes no other options", strings.Join(others, " "))
+		}
+		return NewCashGame(p.store, RealClock{}), numberOfPlayers, nil
+	}
+
+	game := p.newGame()
+	var rules *TournamentRules
+	for _, option := range options {
+		if key, value, ok := strings.Cut(option, "="); ok {
+			if rules == nil {
+				rules = &TournamentRules{}
+			}
+			if err := setTournamentRule(rules, key, value); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		if option == "deal" {
+			dealingGame, ok := game.(DealingGame)
+			if !ok {
+				return nil, 0, errors.New("this game cannot deal cards")
+			}
+			dealingGame.DealCards(DefaultStartingStack, NewSeed())
+			continue
+		}
+
+		if variant, ok := LookupVariant(option); ok {
+			variantGame, ok := game.(VariantGame)
+			if !ok {
+				return nil, 0, fmt.Errorf("this game cannot deal %s", variant)
+			}
+			variantGame.PlayVariant(variant)
+			continue
+		}
 
-	conn, _ := upgrader.Upgrade(w, r, nil)
-	_, winnerMsg, _ := conn.ReadMessage()
-	p.store.RecordWin(string(winnerMsg))
+		if err := useBuiltInBlinds(game, option); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if rules != nil {
+		tournamentGame, ok := game.(TournamentGame)
+		if !ok {
+			return nil, 0, errors.New("this game cannot be played as a tournament")
+		}
+		tournamentGame.PlayTournament(*rules)
+	}
+
+	return game, numberOfPlayers, nil
+}
+
+func setTournamentRule(rules *TournamentRules, key, value string) error {
+	if key == "payouts" {
+		payouts, err := ParsePayouts(value)
+		if err != nil {
+			return err
+		}
+		rules.Payouts = &payouts
+		return nil
+	}
+
+	amount, err := strconv.Atoi(value)
+	if err != nil || amount < 0 {
+		return fmt.Errorf("%s must be an amount, got %q", key, value)
+	}
+
+	switch key {
+	case "buyin":
+		rules.BuyIn = amount
+	case "rebuy":
+		rules.Rebuy = amount
+	case "addon":
+		rules.AddOn = amount
+	case "tablesize":
+		rules.TableSize = amount
+	default:
+		return fmt.Errorf("%q is not a tournament setting, use buyin, rebuy, addon, payouts or tablesize", key)
+	}
+	return nil
+}
+
+func useBuiltInBlinds(game Game, name string) error {
+	structure, ok, err := BuiltInBlindStructure(name)
+	if !ok {
+		return fmt.Errorf("%q is not a blind structure", name)
+	}
+	if err != nil {
+		return err
+	}
 
+	blindsGame, ok := game.(BlindsGame)
+	if !ok {
+		return fmt.Errorf("this game cannot be played with the %s blind structure", structure.Name)
+	}
+	blindsGame.UseBlinds(structure)
+	return nil
 }
 
+// stopClock silences a game that was abandoned before it finished.
+func stopClock(game Game) {
+	if clocked, ok := game.(ClockedGame); ok && clocked.Clock() != nil {
+		clocked.Clock().Stop()
+	}
+}
+
+// designBlindsHandler answers /blinds/design?players=8&stack=10000&duration=4h&chips=25,100,500.
+func (p *PlayerServer) designBlindsHandler(w http.ResponseWriter, r *http.Request) {
+	design, err := blindDesignFromQuery(r.URL.Query())
+	if err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	structure, err := DesignBlindStructure(design)
+	if err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	w.Header().Set("content-type", jsonContentType)
+	json.NewEncoder(w).Encode(structure)
+}
+
+func blindDesignFromQuery(query url.Values) (BlindDesign, error) {
+	var design BlindDesign
+	var err error
+
+	if design.Players, err = strconv.Atoi(query.Get("players")); err != nil {
+		return design, fmt.Errorf("players must be a number, got %q", query.Get("players"))
+	}
+	if design.StartingStack, err = strconv.Atoi(query.Get("stack")); err != nil {
+		return design, fmt.Errorf("stack must be a number, got %q", query.Get("stack"))
+	}
+	if design.Duration, err = time.ParseDuration(query.Get("duration")); err != nil {
+		return design, fmt.Errorf("duration must look like 3h30m, got %q", query.Get("duration"))
+	}
+	if design.Chips, err = ParseChips(query.Get("chips")); err != nil {
+		return design, err
+	}
+
+	return design, nil
+}
+
+// leagueHandler answers /league, or /league?sort=net by cash game results.
 func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
+	league := p.store.GetLeague()
+	switch sortBy := r.URL.Query().Get("sort"); sortBy {
+	case "", "wins":
+	case "net":
+		league = league.ByNet()
+	default:
+		p.httpError(w, r, http.StatusBadRequest, "cannot sort the league by %q, use wins or net", sortBy)
+		return
+	}
+
 	w.Header().Set("content-type", jsonContentType)
-	json.NewEncoder(w).Encode(p.store.GetLeague())
-	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(league)
+}
+
+// Settlement is what is still owed and the payments that would square it.
+type Settlement struct {
+	Balances Balances  `json:"balances"`
+	Payments []Payment `json:"payments"`
+}
+
+// settlementHandler says who pays whom, and takes a payment on POST.
+func (p *PlayerServer) settlementHandler(w http.ResponseWriter, r *http.Request) {
+	store, ok := p.store.(SettlementStore)
+	if !ok {
+		p.httpError(w, r, http.StatusNotImplemented, "this store does not keep balances")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		balances := store.Balances()
+		payments, err := balances.Settle()
+		if err != nil {
+			p.httpError(w, r, http.StatusInternalServerError, "%v", err)
+			return
+		}
+
+		w.Header().Set("content-type", jsonContentType)
+		json.NewEncoder(w).Encode(Settlement{Balances: balances, Payments: payments})
+	case http.MethodPost:
+		var payment Payment
+		if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+			p.httpError(w, r, http.StatusBadRequest, "problem reading the payment %v", err)
+			return
+		}
+		if err := store.RecordPayment(payment); err != nil {
+			p.httpError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.Header().Set("allow", "GET, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
 }
 
 func (p *PlayerServer) playersHandler(w http.ResponseWriter, r *http.Request) {
 	playerName := strings.TrimPrefix(r.URL.Path, "/players/")
 	switch r.Method {
 	case http.MethodPost:
-		p.processWin(w, playerName)
+		p.processWin(w, r, playerName)
 	case http.MethodGet:
 		p.showScore(w, playerName)
 	}
@@ -94,8 +538,11 @@ func (p *PlayerServer) showScore(w http.ResponseWriter, playerName string) {
 
 }
 
-func (p *PlayerServer) processWin(w http.ResponseWriter, playerName string) {
-	p.store.RecordWin(playerName)
+func (p *PlayerServer) processWin(w http.ResponseWriter, r *http.Request, playerName string) {
+	if err := p.store.RecordWin(playerName); err != nil {
+		p.httpError(w, r, http.StatusInternalServerError, "%v", err)
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
 
 }
@@ -107,3 +554,179 @@ func (s *PlayerServer) RecordWin(name string) {
 		fmt.Println(p.Wins)
 	}
 }
+
+// recordTournamentHandler keeps a tournament played elsewhere.
+func (p *PlayerServer) recordTournamentHandler(w http.ResponseWriter, r *http.Request) {
+	store, ok := p.store.(TournamentStore)
+	if !ok {
+		p.httpError(w, r, http.StatusNotImplemented, "this store does not keep tournaments")
+		return
+	}
+
+	var result TournamentResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "problem reading the tournament %v", err)
+		return
+	}
+	if result.Winner() == "" {
+		p.httpError(w, r, http.StatusBadRequest, "the tournament has no winner")
+		return
+	}
+	if err := store.RecordTournament(result); err != nil {
+		p.httpError(w, r, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// recordCashGameHandler keeps a cash game session played somewhere else.
+func (p *PlayerServer) recordCashGameHandler(w http.ResponseWriter, r *http.Request) {
+	store, ok := p.store.(CashGameStore)
+	if !ok {
+		p.httpError(w, r, http.StatusNotImplemented, "this store does not keep cash games")
+		return
+	}
+
+	var result CashResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "problem reading the cash game %v", err)
+		return
+	}
+	if err := store.RecordCashGame(result); err != nil {
+		p.httpError(w, r, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// syncHandler merges a CLI's journal. Sending it again is safe, so PUT.
+func (p *PlayerServer) syncHandler(w http.ResponseWriter, r *http.Request) {
+	store, ok := p.store.(SyncStore)
+	if !ok {
+		p.httpError(w, r, http.StatusNotImplemented, "this store does not take journals")
+		return
+	}
+
+	var entries []JournalEntry
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "problem reading the journal %v", err)
+		return
+	}
+	results, err := store.Merge(entries)
+	if err != nil {
+		p.httpError(w, r, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// newGameRequest is the body of POST /games.
+type newGameRequest struct {
+	Players int    `json:"players"`
+	Options string `json:"options"`
+}
+
+func (p *PlayerServer) listGamesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, p.games.List())
+}
+
+// createGameHandler sets up a game for players to join before it starts.
+func (p *PlayerServer) createGameHandler(w http.ResponseWriter, r *http.Request) {
+	var request newGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "problem reading the game %v", err)
+		return
+	}
+
+	game, numberOfPlayers, err := p.setUpGame(fmt.Sprintf("%d %s", request.Players, request.Options))
+	if err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	managed := p.games.Create(game, numberOfPlayers, strings.TrimSpace(request.Options))
+	w.Header().Set("location", "/games/"+managed.ID)
+	writeJSON(w, http.StatusCreated, managed.Info())
+}
+
+func (p *PlayerServer) gameInfoHandler(w http.ResponseWriter, r *http.Request) {
+	if game, ok := p.findGame(w, r); ok {
+		writeJSON(w, http.StatusOK, game.Info())
+	}
+}
+
+// joinGameHandler takes a seat with {"name": "Chris"}.
+func (p *PlayerServer) joinGameHandler(w http.ResponseWriter, r *http.Request) {
+	game, ok := p.findGame(w, r)
+	if !ok {
+		return
+	}
+
+	var player struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&player); err != nil || player.Name == "" {
+		p.httpError(w, r, http.StatusBadRequest, `a player joins with {"name": "..."}`)
+		return
+	}
+
+	if err := game.Join(player.Name); err != nil {
+		p.httpError(w, r, http.StatusConflict, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, game.Info())
+}
+
+func (p *PlayerServer) startGameHandler(w http.ResponseWriter, r *http.Request) {
+	game, ok := p.findGame(w, r)
+	if !ok {
+		return
+	}
+
+	if err := game.Start(); err != nil {
+		p.httpError(w, r, http.StatusConflict, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, game.Info())
+}
+
+// endGameHandler finishes a game with {"winner": "Chris"}.
+func (p *PlayerServer) endGameHandler(w http.ResponseWriter, r *http.Request) {
+	game, ok := p.findGame(w, r)
+	if !ok {
+		return
+	}
+
+	var result struct {
+		Winner string `json:"winner"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		p.httpError(w, r, http.StatusBadRequest, "problem reading the result %v", err)
+		return
+	}
+
+	if err := game.End(result.Winner); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrGameNotStarted) || errors.Is(err, ErrGameFinished) || errors.Is(err, ErrSessionOpen) {
+			status = http.StatusConflict
+		}
+		p.httpError(w, r, status, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, game.Info())
+}
+
+func (p *PlayerServer) findGame(w http.ResponseWriter, r *http.Request) (*ManagedGame, bool) {
+	id := r.PathValue("id")
+	game, ok := p.games.Get(id)
+	if !ok {
+		p.httpError(w, r, http.StatusNotFound, "there is no game %s", id)
+	}
+	return game, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("content-type", jsonContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
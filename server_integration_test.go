@@ -11,7 +11,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 	defer cleanDatabase()
 
 	store, _ := NewFileSystemStore(database)
-	server := NewPlayerServer(store)
+	server := NewPlayerServer(store, gameFactory(&GameSpy{}))
 	player := "Pepper"
 
 	server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest(player))
@@ -34,7 +34,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 
 	// 	got := getLeagueFromResponse(t, response.Body)
 	// 	want := []Player{
-	// 		{"Pepper", 3},
+	// 		{Name: "Pepper", Wins: 3},
 	// 	}
 
 	// 	assertLeague(t, got, want)
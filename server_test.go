@@ -1,29 +1,35 @@
 package poker
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+var dummyGame = &GameSpy{}
+
 func TestGETPlayers(t *testing.T) {
 	store := StubPlayerStore{
-		map[string]int{
+		scores: map[string]int{
 			"Pepper": 20,
 			"Floyd":  10,
 		},
-		[]string{},
-		[]Player{
-			{"chris", 30},
+		winCalls: []string{},
+		league: []Player{
+			{Name: "chris", Wins: 30},
 		},
 	}
 
-	server := NewPlayerServer(&store)
+	server := NewPlayerServer(&store, gameFactory(dummyGame))
 	t.Run("returns Pepper's score", func(t *testing.T) {
 
 		request := NewGetScoreRequest("Pepper")
@@ -63,13 +69,13 @@ func TestStoreWins(t *testing.T) {
 
 	t.Run("it returns accepted on POST", func(t *testing.T) {
 		store := StubPlayerStore{
-			map[string]int{},
-			[]string{},
-			[]Player{
-				{"chris", 30},
+			scores:   map[string]int{},
+			winCalls: []string{},
+			league: []Player{
+				{Name: "chris", Wins: 30},
 			},
 		}
-		server := NewPlayerServer(&store)
+		server := NewPlayerServer(&store, gameFactory(dummyGame))
 		request, _ := http.NewRequest(http.MethodPost, "/players/Pepper", nil)
 		response := httptest.NewRecorder()
 
@@ -82,13 +88,13 @@ func TestStoreWins(t *testing.T) {
 	})
 	t.Run("it records wins on POST", func(t *testing.T) {
 		store := StubPlayerStore{
-			map[string]int{},
-			[]string{},
-			[]Player{
-				{"chris", 30},
+			scores:   map[string]int{},
+			winCalls: []string{},
+			league: []Player{
+				{Name: "chris", Wins: 30},
 			},
 		}
-		server := NewPlayerServer(&store)
+		server := NewPlayerServer(&store, gameFactory(dummyGame))
 		player := "Pepper"
 
 		request := newPostWinRequest(player)
@@ -108,7 +114,7 @@ func newPostWinRequest(playerName string) *http.Request {
 
 func TestLeague(t *testing.T) {
 	store := StubPlayerStore{}
-	server := NewPlayerServer(&store)
+	server := NewPlayerServer(&store, gameFactory(dummyGame))
 
 	t.Run("it returns 200 on /league", func(t *testing.T) {
 		request, _ := http.NewRequest(http.MethodGet, "/league", nil)
@@ -129,12 +135,12 @@ func TestLeague(t *testing.T) {
 
 	t.Run("it returns the league table as json", func(t *testing.T) {
 		wantedLeague := []Player{
-			{"Cleo", 32},
-			{"Chris", 20},
-			{"Tiest", 14},
+			{Name: "Cleo", Wins: 32},
+			{Name: "Chris", Wins: 20},
+			{Name: "Tiest", Wins: 14},
 		}
-		store := StubPlayerStore{nil, nil, wantedLeague}
-		server := NewPlayerServer(&store)
+		store := StubPlayerStore{league: wantedLeague}
+		server := NewPlayerServer(&store, gameFactory(dummyGame))
 
 		request := NewLeagueRequest()
 		response := httptest.NewRecorder()
@@ -147,11 +153,137 @@ func TestLeague(t *testing.T) {
 		AssertContentType(t, response, jsonContentType)
 
 	})
+
+	t.Run("it ranks the league by net results", func(t *testing.T) {
+		store := StubPlayerStore{league: []Player{
+			{Name: "Cleo", Wins: 32, Net: -50},
+			{Name: "Chris", Wins: 20, Net: 120},
+			{Name: "Tiest", Wins: 14, Net: 0},
+		}}
+		server := NewPlayerServer(&store, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=net", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		got := GetLeagueFromResponse(t, response.Body)
+		AssertStatus(t, response.Code, http.StatusOK)
+		AssertLeague(t, got, []Player{
+			{Name: "Chris", Wins: 20, Net: 120},
+			{Name: "Tiest", Wins: 14, Net: 0},
+			{Name: "Cleo", Wins: 32, Net: -50},
+		})
+	})
+
+	t.Run("it refuses to rank the league by anything else", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=name", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
+
+func TestSettlement(t *testing.T) {
+	t.Run("it says who pays whom", func(t *testing.T) {
+		store := &StubPlayerStore{balances: Balances{"Cleo": 30, "Chris": -30}}
+		server := NewPlayerServer(store, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodGet, "/settlement", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		var got Settlement
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("unable to parse the settlement %v", err)
+		}
+		AssertStatus(t, response.Code, http.StatusOK)
+		AssertContentType(t, response, jsonContentType)
+		want := []Payment{{From: "Chris", To: "Cleo", Amount: 30}}
+		if !reflect.DeepEqual(got.Payments, want) {
+			t.Errorf("got payments %v want %v", got.Payments, want)
+		}
+	})
+
+	t.Run("it records payments", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		server := NewPlayerServer(store, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodPost, "/settlement", strings.NewReader(`{"from": "Chris", "to": "Cleo", "amount": 30}`))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusAccepted)
+		if len(store.payments) != 1 || store.payments[0] != (Payment{From: "Chris", To: "Cleo", Amount: 30}) {
+			t.Errorf("got payments %v", store.payments)
+		}
+	})
+
+	t.Run("it refuses payments that make no sense", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodPost, "/settlement", strings.NewReader(`{"from": "Chris", "to": "Cleo", "amount": -5}`))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
+
+func TestRecordingResults(t *testing.T) {
+	t.Run("it keeps tournaments played elsewhere", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		server := NewPlayerServer(store, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodPost, "/tournaments", strings.NewReader(`{"entrants": 2, "placings": [{"place": 1, "player": "Cleo"}, {"place": 2, "player": "Chris"}]}`))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusAccepted)
+		AssertPlayerWin(t, store, "Cleo")
+		if len(store.tournaments) != 1 || len(store.tournaments[0].Placings) != 2 {
+			t.Errorf("got tournaments %+v", store.tournaments)
+		}
+	})
+
+	t.Run("it keeps cash games played elsewhere", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		server := NewPlayerServer(store, gameFactory(dummyGame))
+
+		request, _ := http.NewRequest(http.MethodPost, "/cash-games", strings.NewReader(`{"players": [{"player": "Cleo", "net": 20}, {"player": "Chris", "net": -20}]}`))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusAccepted)
+		if len(store.cashGames) != 1 || store.cashGames[0].Players[0].Net != 20 {
+			t.Errorf("got cash games %+v", store.cashGames)
+		}
+	})
+
+	t.Run("it refuses results it cannot read", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+		for path, body := range map[string]string{
+			"/tournaments": `{"placings": [{"place": 2, "player": "Chris"}]}`,
+			"/cash-games":  `not json`,
+		} {
+			request, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			AssertStatus(t, response.Code, http.StatusBadRequest)
+		}
+	})
 }
 
 func TestGame(t *testing.T) {
 	t.Run("GET /game returns 200", func(t *testing.T) {
-		server := NewPlayerServer(&StubPlayerStore{})
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
 
 		request, _ := http.NewRequest(http.MethodGet, "/game", nil)
 		response := httptest.NewRecorder()
@@ -159,26 +291,522 @@ func TestGame(t *testing.T) {
 		server.ServeHTTP(response, request)
 
 		AssertStatus(t, response.Code, http.StatusOK)
+
+		if !strings.Contains(response.Body.String(), `<option value="turbo">`) {
+			t.Error("expected the built in blind structures to be offered")
+		}
 	})
-	t.Run("when we get a message over a websocket it is a winner of a game", func(t *testing.T) {
-		store := &StubPlayerStore{}
+	t.Run("start a game with 3 players and declare Ruth the winner", func(t *testing.T) {
+		game := &GameSpy{}
 		winner := "Ruth"
-		server := httptest.NewServer(NewPlayerServer(store))
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
 
+		writeWSMessage(t, ws, "3")
+		writeWSMessage(t, ws, "winner "+winner)
+
+		assertGameStartedWith(t, game, 3)
+		assertGameFinishedWith(t, game, winner)
+	})
+	t.Run("blind alerts are pushed to the browser over the websocket", func(t *testing.T) {
+		wantedBlindAlert := "Blinds are now 50/100, next 100/200 in 10m0s\n"
+		game := &GameSpy{BlindAlert: []byte(wantedBlindAlert)}
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
 		defer server.Close()
 
-		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
 
-		ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		writeWSMessage(t, ws, "3")
 
-		if err != nil {
-			t.Fatalf("could not open a ws connection on %s %v", wsURL, err)
+		within(t, 100*time.Millisecond, func() {
+			_, gotBlindAlert, _ := ws.ReadMessage()
+			if string(gotBlindAlert) != wantedBlindAlert {
+				t.Errorf("got blind alert %q, want %q", string(gotBlindAlert), wantedBlindAlert)
+			}
+		})
+	})
+	t.Run("a game can be started with a built in blind structure", func(t *testing.T) {
+		clock := NewManualClock(time.Time{})
+		game := NewTexasHoldem(&StubPlayerStore{}, NewAlerter(clock), clock)
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "6 turbo")
+		retryUntil(500*time.Millisecond, func() bool { return clock.Pending() > 0 })
+		clock.Advance(0)
+
+		within(t, 100*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			want := "Blinds are now 25/50, next 50/100 in 10m0s\n"
+			if string(msg) != want {
+				t.Errorf("got %q want %q", string(msg), want)
+			}
+		})
+	})
+
+	t.Run("a game can deal the cards and take moves from the browser", func(t *testing.T) {
+		game := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, NewManualClock(time.Time{}))
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "3 deal")
+		writeWSMessage(t, ws, "fold")
+
+		within(t, 500*time.Millisecond, func() {
+			for {
+				_, msg, err := ws.ReadMessage()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(msg) == "Player 1 folds\n" {
+					return
+				}
+			}
+		})
+	})
+
+	t.Run("a game can be started as another variant", func(t *testing.T) {
+		game := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, NewManualClock(time.Time{}))
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "3 omaha deal")
+
+		within(t, 500*time.Millisecond, func() {
+			for {
+				_, msg, err := ws.ReadMessage()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.HasPrefix(string(msg), "Hand #1 of Pot Limit Omaha") {
+					return
+				}
+			}
+		})
+	})
+
+	t.Run("a tournament takes bust-outs from the browser", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game := NewTexasHoldem(store, &spyAlerter{}, NewManualClock(time.Time{}))
+		server := httptest.NewServer(NewPlayerServer(store, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "3 buyin=20 payouts=70%,30%")
+		writeWSMessage(t, ws, "Alice out")
+
+		within(t, 500*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			if string(msg) != "Alice is out in 3rd place, 2 players left\n" {
+				t.Errorf("got %q", string(msg))
+			}
+		})
+	})
+
+	t.Run("a cash game takes buy-ins from the browser", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		server := httptest.NewServer(NewPlayerServer(store, gameFactory(&GameSpy{})))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "2 cash")
+		writeWSMessage(t, ws, "Alice buys in 100")
+
+		within(t, 500*time.Millisecond, func() {
+			ws.ReadMessage()
+			_, msg, _ := ws.ReadMessage()
+			if string(msg) != "Alice buys in for 100, 100 in play\n" {
+				t.Errorf("got %q", string(msg))
+			}
+		})
+	})
+
+	t.Run("bad tournament settings are reported", func(t *testing.T) {
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(&GameSpy{})))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "3 buyin=lots")
+
+		within(t, 100*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			if !strings.Contains(string(msg), "buyin must be an amount") {
+				t.Errorf("expected an error message but got %q", string(msg))
+			}
+		})
+	})
+
+	t.Run("unknown blind structures are reported", func(t *testing.T) {
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(&GameSpy{})))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "6 hyper")
+
+		within(t, 100*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			if !strings.Contains(string(msg), `"hyper" is not a blind structure`) {
+				t.Errorf("expected an error message but got %q", string(msg))
+			}
+		})
+	})
+
+	t.Run("a game is not started when the number of players is not a number", func(t *testing.T) {
+		game := &GameSpy{}
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(game)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "lots")
+
+		within(t, 100*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			if !strings.Contains(string(msg), "not a number of players") {
+				t.Errorf("expected an error message but got %q", string(msg))
+			}
+		})
+
+		if game.startedWith() != 0 {
+			t.Error("game should not have started")
+		}
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	store := &StubPlayerStore{}
+	clock := NewManualClock(time.Time{})
+	player := NewPlayerServer(store, func() Game {
+		game := NewTexasHoldem(store, &spyAlerter{}, clock)
+		game.UseBlinds(BlindStructure{Name: "test", Levels: testLevels})
+		return game
+	})
+	server := httptest.NewServer(player)
+	defer server.Close()
+
+	ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+	defer ws.Close()
+	writeWSMessage(t, ws, "3")
+
+	if !retryUntil(500*time.Millisecond, func() bool { return len(store.Checkpoints()) == 1 }) {
+		t.Fatal("expected the game to be started and saved")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assertNoError(t, player.Shutdown(ctx))
+
+	t.Run("browsers are told the server is going away", func(t *testing.T) {
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+		var closed *websocket.CloseError
+		for {
+			_, _, err := ws.ReadMessage()
+			if err == nil {
+				continue
+			}
+			if !errors.As(err, &closed) || closed.Code != websocket.CloseGoingAway {
+				t.Errorf("got %v, want a going away close frame", err)
+			}
+			break
+		}
+	})
+
+	t.Run("games are kept to be resumed", func(t *testing.T) {
+		assertCheckpoints(t, store, 1)
+		for _, game := range player.Games().List() {
+			if game.State != GameFinished {
+				t.Errorf("got game %s %s, want it put away", game.ID, game.State)
+			}
+		}
+	})
+
+	t.Run("no more games are started", func(t *testing.T) {
+		late := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer late.Close()
+
+		late.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := late.ReadMessage()
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("got %v, want a going away close frame", err)
 		}
+	})
+}
+
+func TestGames(t *testing.T) {
+	serve := func(server *PlayerServer, method, url, body string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(method, url, strings.NewReader(body))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response
+	}
+	gameInfo := func(t testing.TB, response *httptest.ResponseRecorder) GameInfo {
+		t.Helper()
+		var info GameInfo
+		if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
+			t.Fatalf("could not read the game from %q, %v", response.Body.String(), err)
+		}
+		return info
+	}
+
+	t.Run("a game is created, joined, started and ended over http", func(t *testing.T) {
+		game := &GameSpy{}
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(game))
+
+		response := serve(server, http.MethodPost, "/games", `{"players": 2}`)
+		AssertStatus(t, response.Code, http.StatusCreated)
+		AssertContentType(t, response, jsonContentType)
+		created := gameInfo(t, response)
+		if created.State != GamePending || response.Header().Get("location") != "/games/"+created.ID {
+			t.Errorf("got %+v at %q", created, response.Header().Get("location"))
+		}
+
+		url := "/games/" + created.ID
+		AssertStatus(t, serve(server, http.MethodPost, url+"/join", `{"name": "Ruth"}`).Code, http.StatusOK)
+		AssertStatus(t, serve(server, http.MethodPost, url+"/join", `{"name": "Ruth"}`).Code, http.StatusConflict)
+		AssertStatus(t, serve(server, http.MethodPost, url+"/start", "").Code, http.StatusOK)
+		assertGameStartedWith(t, game, 2)
+
+		if info := gameInfo(t, serve(server, http.MethodGet, url, "")); info.State != GameRunning || len(info.Players) != 1 {
+			t.Errorf("got %+v", info)
+		}
+
+		AssertStatus(t, serve(server, http.MethodPost, url+"/end", `{"winner": ""}`).Code, http.StatusBadRequest)
+		AssertStatus(t, serve(server, http.MethodPost, url+"/end", `{"winner": "Chris"}`).Code, http.StatusBadRequest)
+		AssertStatus(t, serve(server, http.MethodPost, url+"/end", `{"winner": "Ruth"}`).Code, http.StatusOK)
+		assertGameFinishedWith(t, game, "Ruth")
+		AssertStatus(t, serve(server, http.MethodPost, url+"/end", `{"winner": "Ruth"}`).Code, http.StatusConflict)
+	})
+
+	t.Run("a cash game is not ended over http until its books balance", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		server := NewPlayerServer(store, gameFactory(dummyGame))
+
+		created := gameInfo(t, serve(server, http.MethodPost, "/games", `{"players": 2, "options": "cash"}`))
+		url := "/games/" + created.ID
+		AssertStatus(t, serve(server, http.MethodPost, url+"/start", "").Code, http.StatusOK)
+		store.SaveCheckpoint(Checkpoint{ID: created.ID})
+
+		game, _ := server.games.Get(created.ID)
+		assertNoError(t, game.Command("Cleo buys in 20"))
+
+		response := serve(server, http.MethodPost, url+"/end", `{}`)
+		AssertStatus(t, response.Code, http.StatusConflict)
+		if !strings.Contains(response.Body.String(), "Cleo still to cash out") {
+			t.Errorf("got %q want to be told who is still playing", response.Body.String())
+		}
+		assertGameState(t, game, GameRunning)
+		assertCheckpoints(t, store, 1)
+
+		assertNoError(t, game.Command("Cleo cashes out 20"))
+		AssertStatus(t, serve(server, http.MethodPost, url+"/end", `{}`).Code, http.StatusOK)
+		if len(store.cashGames) != 1 {
+			t.Errorf("got %d cash games saved want 1", len(store.cashGames))
+		}
+	})
+
+	t.Run("every game is listed", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, func() Game { return &GameSpy{} })
+		serve(server, http.MethodPost, "/games", `{"players": 3}`)
+		serve(server, http.MethodPost, "/games", `{"players": 5, "options": "cash"}`)
+
+		response := serve(server, http.MethodGet, "/games", "")
+		AssertStatus(t, response.Code, http.StatusOK)
+
+		var games []GameInfo
+		json.NewDecoder(response.Body).Decode(&games)
+		if len(games) != 2 || games[0].Seats != 3 || games[1].Options != "cash" {
+			t.Errorf("got games %+v", games)
+		}
+	})
+
+	t.Run("games that do not exist or make no sense are refused", func(t *testing.T) {
+		server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+		AssertStatus(t, serve(server, http.MethodGet, "/games/42", "").Code, http.StatusNotFound)
+		AssertStatus(t, serve(server, http.MethodPost, "/games/42/join", `{"name": "Ruth"}`).Code, http.StatusNotFound)
+		AssertStatus(t, serve(server, http.MethodPost, "/games", `{"players": 3, "options": "nonsense"}`).Code, http.StatusBadRequest)
+		AssertStatus(t, serve(server, http.MethodPost, "/games", `{"players": 0}`).Code, http.StatusBadRequest)
+
+		response := serve(server, http.MethodPost, "/games", `{"players": 3, "options": "turbo cash"}`)
+		AssertStatus(t, response.Code, http.StatusBadRequest)
+		if !strings.Contains(response.Body.String(), "a cash game cannot be played with turbo") {
+			t.Errorf("got %q want to be told cash takes no other options", response.Body.String())
+		}
+		AssertStatus(t, serve(server, http.MethodPost, "/games", `{"players": -2}`).Code, http.StatusBadRequest)
+	})
+
+	t.Run("a browser can watch and play a game someone else started", func(t *testing.T) {
+		game := &GameSpy{}
+		playerServer := NewPlayerServer(&StubPlayerStore{}, gameFactory(game))
+		server := httptest.NewServer(playerServer)
+		defer server.Close()
+
+		created := gameInfo(t, serve(playerServer, http.MethodPost, "/games", `{"players": 3}`))
+		managed, _ := playerServer.Games().Get(created.ID)
+		managed.Start()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws?game="+created.ID)
+		defer ws.Close()
+
+		retryUntil(500*time.Millisecond, func() bool { return managed.Info().Watchers == 1 })
+		writeWSMessage(t, ws, "winner Ruth")
+
+		assertGameFinishedWith(t, game, "Ruth")
+	})
+
+	t.Run("browsers are told about games that do not exist", func(t *testing.T) {
+		server := httptest.NewServer(NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame)))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws?game=42")
 		defer ws.Close()
 
-		AssertPlayerWin(t, store, winner)
+		within(t, 100*time.Millisecond, func() {
+			_, msg, _ := ws.ReadMessage()
+			if string(msg) != "there is no game 42" {
+				t.Errorf("got %q", string(msg))
+			}
+		})
+	})
+}
+
+// gameFactory hands the same game to every session, so tests can inspect it.
+func gameFactory(game Game) func() Game {
+	return func() Game { return game }
+}
+
+func mustDialWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("could not open a ws connection on %s %v", url, err)
+	}
+
+	return ws
+}
+
+func writeWSMessage(t testing.TB, conn *websocket.Conn, message string) {
+	t.Helper()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+		t.Fatalf("could not send message over ws connection %v", err)
+	}
+}
+
+func assertGameStartedWith(t testing.TB, game *GameSpy, numberOfPlayers int) {
+	t.Helper()
+
+	passed := retryUntil(500*time.Millisecond, func() bool {
+		return game.startedWith() == numberOfPlayers
 	})
+
+	if !passed {
+		t.Errorf("expected start called with %d but got %d", numberOfPlayers, game.startedWith())
+	}
+}
+
+func assertGameFinishedWith(t testing.TB, game *GameSpy, winner string) {
+	t.Helper()
+
+	passed := retryUntil(500*time.Millisecond, func() bool {
+		return game.finishedWith() == winner
+	})
+
+	if !passed {
+		t.Errorf("expected finish called with %q but got %q", winner, game.finishedWith())
+	}
 }
+
+func retryUntil(d time.Duration, f func() bool) bool {
+	deadline := time.Now().Add(d)
+	for time.Now().Before(deadline) {
+		if f() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func within(t testing.TB, d time.Duration, assert func()) {
+	t.Helper()
+
+	done := make(chan struct{}, 1)
+
+	go func() {
+		assert()
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-time.After(d):
+		t.Error("timed out")
+	case <-done:
+	}
+}
+
+func TestDesignBlinds(t *testing.T) {
+	server := NewPlayerServer(&StubPlayerStore{}, gameFactory(dummyGame))
+
+	t.Run("returns a playable blind structure as json", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/blinds/design?players=8&stack=10000&duration=4h&chips=25,100,500,1000", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		AssertStatus(t, response.Code, http.StatusOK)
+		AssertContentType(t, response, jsonContentType)
+
+		structure, err := NewBlindStructure(response.Body)
+		assertNoError(t, err)
+
+		if structure.Levels[0].BigBlind != 100 {
+			t.Errorf("got first level %v", structure.Levels[0])
+		}
+	})
+
+	t.Run("rejects designs it cannot read", func(t *testing.T) {
+		cases := []string{
+			"players=many&stack=10000&duration=4h&chips=25",
+			"players=8&stack=10000&duration=forever&chips=25",
+			"players=8&stack=10000&duration=4h&chips=red",
+			"players=1&stack=10000&duration=4h&chips=25",
+		}
+
+		for _, query := range cases {
+			request := httptest.NewRequest(http.MethodGet, "/blinds/design?"+query, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			AssertStatus(t, response.Code, http.StatusBadRequest)
+		}
+	})
+}
+
 func NewGameRequest(t *testing.T) (*http.Request, error) {
 	request, err := http.NewRequest(http.MethodGet, "/game", nil)
 	return request, err
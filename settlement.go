@@ -0,0 +1,193 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Balances is what each player is owed, or owes when negative.
+type Balances map[string]int
+
+// Total is what the balances come to, zero when every debt has a creditor.
+func (b Balances) Total() int {
+	total := 0
+	for _, amount := range b {
+		total += amount
+	}
+	return total
+}
+
+// Add puts other's results on top of b.
+func (b Balances) Add(other Balances) {
+	for player, amount := range other {
+		b[player] += amount
+	}
+	for player, amount := range b {
+		if amount == 0 {
+			delete(b, player)
+		}
+	}
+}
+
+// Payment is money changing hands to settle up.
+type Payment struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+}
+
+func (p Payment) Validate() error {
+	if p.From == "" || p.To == "" {
+		return errors.New("a payment needs someone to pay and someone to be paid")
+	}
+	if p.From == p.To {
+		return fmt.Errorf("%s cannot pay themselves", p.From)
+	}
+	if p.Amount <= 0 {
+		return fmt.Errorf("a payment must be more than nothing, got %d", p.Amount)
+	}
+	return nil
+}
+
+// settles is what the payment does to the balances.
+func (p Payment) settles() Balances {
+	return Balances{p.From: p.Amount, p.To: -p.Amount}
+}
+
+func (p Payment) String() string {
+	return fmt.Sprintf("%s pays %s %d", p.From, p.To, p.Amount)
+}
+
+// SettlementStore carries what players owe until it is paid.
+type SettlementStore interface {
+	PlayerStore
+	Balances() Balances
+	RecordPayment(Payment) error
+}
+
+// ErrUnbalanced means no payments can settle the balances.
+var ErrUnbalanced = errors.New("the balances do not add up to zero")
+
+// maxExactSettlement is the most players settled exactly.
+const maxExactSettlement = 16
+
+// Settle works out who pays whom, in the fewest payments for a home game's size.
+func (b Balances) Settle() ([]Payment, error) {
+	var players []string
+	for player, amount := range b {
+		if amount != 0 {
+			players = append(players, player)
+		}
+	}
+	if total := b.Total(); total != 0 {
+		return nil, fmt.Errorf("%w, they are %+d out", ErrUnbalanced, total)
+	}
+	sort.Strings(players)
+
+	if len(players) > maxExactSettlement {
+		return b.settleGroup(players), nil
+	}
+
+	var payments []Payment
+	for _, group := range b.zeroSumGroups(players) {
+		payments = append(payments, b.settleGroup(group)...)
+	}
+	return payments, nil
+}
+
+// zeroSumGroups splits players into as many zero sum groups as it can.
+func (b Balances) zeroSumGroups(players []string) [][]string {
+	subsets := 1 << len(players)
+	sums := make([]int, subsets)
+	groups := make([]int, subsets)
+
+	for mask := 1; mask < subsets; mask++ {
+		lowest := mask & -mask
+		sums[mask] = sums[mask^lowest] + b[players[bits.TrailingZeros(uint(lowest))]]
+
+		for i := range players {
+			if bit := 1 << i; mask&bit != 0 {
+				groups[mask] = max(groups[mask], groups[mask^bit])
+			}
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// walk back down taking players off one at a time. Every time what is
+	// left adds up to zero, the players taken off since the last time form a
+	// group.
+	var result [][]string
+	var group []string
+	for mask := subsets - 1; mask != 0; {
+		if sums[mask] == 0 && len(group) > 0 {
+			result = append(result, group)
+			group = nil
+		}
+
+		bonus := 0
+		if sums[mask] == 0 {
+			bonus = 1
+		}
+		for i := range players {
+			if bit := 1 << i; mask&bit != 0 && groups[mask^bit]+bonus == groups[mask] {
+				group = append(group, players[i])
+				mask ^= bit
+				break
+			}
+		}
+	}
+	return append(result, group)
+}
+
+// settleGroup settles a group of n in at most n-1 payments.
+func (b Balances) settleGroup(players []string) []Payment {
+	owed := map[string]int{}
+	for _, player := range players {
+		owed[player] = b[player]
+	}
+
+	biggest := func(sign int) string {
+		best := ""
+		for _, player := range players {
+			if owed[player]*sign > 0 && (best == "" || owed[player]*sign > owed[best]*sign) {
+				best = player
+			}
+		}
+		return best
+	}
+
+	var payments []Payment
+	for {
+		debtor, creditor := biggest(-1), biggest(1)
+		if debtor == "" || creditor == "" {
+			return payments
+		}
+
+		amount := min(-owed[debtor], owed[creditor])
+		payments = append(payments, Payment{From: debtor, To: creditor, Amount: amount})
+		owed[debtor] += amount
+		owed[creditor] -= amount
+	}
+}
+
+// Nets is what each player won or lost at the table.
+func (r CashResult) Nets() Balances {
+	nets := Balances{}
+	for _, stake := range r.Players {
+		nets[stake.Player] += stake.Net
+	}
+	return nets
+}
+
+// Nets leaves out unnamed players, so it only adds up to zero when all were named.
+func (r TournamentResult) Nets() Balances {
+	nets := Balances{}
+	for _, placing := range r.Placings {
+		nets[placing.Player] += placing.Prize - placing.Paid
+	}
+	return nets
+}
@@ -0,0 +1,101 @@
+package poker
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSettle(t *testing.T) {
+	t.Run("the fewest payments square everyone", func(t *testing.T) {
+		balances := Balances{"Alice": 8, "Bob": 5, "Cleo": 4, "Dan": -2, "Eve": -6, "Finn": -9}
+
+		payments, err := balances.Settle()
+		assertNoError(t, err)
+
+		// paying the biggest debts first would take five payments.
+		want := []Payment{
+			{From: "Eve", To: "Alice", Amount: 6},
+			{From: "Dan", To: "Alice", Amount: 2},
+			{From: "Finn", To: "Bob", Amount: 5},
+			{From: "Finn", To: "Cleo", Amount: 4},
+		}
+		if !samePayments(payments, want) {
+			t.Errorf("got %v want %v", payments, want)
+		}
+		assertSettled(t, balances, payments)
+	})
+
+	t.Run("nothing to pay when everyone is square", func(t *testing.T) {
+		payments, err := Balances{"Alice": 0}.Settle()
+		assertNoError(t, err)
+		if len(payments) != 0 {
+			t.Errorf("got %v want no payments", payments)
+		}
+	})
+
+	t.Run("balances that do not add up cannot be settled", func(t *testing.T) {
+		_, err := Balances{"Alice": 10, "Bob": -5}.Settle()
+		if !errors.Is(err, ErrUnbalanced) {
+			t.Errorf("got %v want %v", err, ErrUnbalanced)
+		}
+	})
+
+	t.Run("big tables are still settled", func(t *testing.T) {
+		balances := Balances{}
+		for i := 0; i < maxExactSettlement+4; i++ {
+			balances["Player "+strconv.Itoa(i)] = (i%7 - 3) * 10
+		}
+		balances["Banker"] = -balances.Total()
+
+		payments, err := balances.Settle()
+		assertNoError(t, err)
+		if len(payments) >= len(balances) {
+			t.Errorf("got %d payments for %d players", len(payments), len(balances))
+		}
+		assertSettled(t, balances, payments)
+	})
+}
+
+func TestNets(t *testing.T) {
+	result := TournamentResult{Placings: []Placing{
+		{Place: 1, Player: "Alice", Paid: 20, Prize: 70},
+		{Place: 2, Player: "Bob", Paid: 40, Prize: 30},
+		{Place: 3, Player: "Cleo", Paid: 40},
+	}}
+
+	want := Balances{"Alice": 50, "Bob": -10, "Cleo": -40}
+	if got := result.Nets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func samePayments(got, want []Payment) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for _, payment := range want {
+		found := false
+		for _, g := range got {
+			found = found || g == payment
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func assertSettled(t testing.TB, balances Balances, payments []Payment) {
+	t.Helper()
+
+	left := Balances{}
+	left.Add(balances)
+	for _, payment := range payments {
+		left.Add(payment.settles())
+	}
+	if len(left) != 0 {
+		t.Errorf("still owed after paying %v: %v", payments, left)
+	}
+}
@@ -0,0 +1,78 @@
+package poker
+
+import "fmt"
+
+// PlayerStats is a player's record across everything that has been kept.
+type PlayerStats struct {
+	Player string `json:"player"`
+	Wins   int    `json:"wins"`
+
+	Tournaments    int `json:"tournaments"`
+	TournamentWins int `json:"tournament_wins"`
+	BestPlace      int `json:"best_place,omitempty"`
+	InTheMoney     int `json:"in_the_money"`
+	Winnings       int `json:"winnings"`
+
+	CashGames int `json:"cash_games"`
+	Net       int `json:"net"`
+
+	// Owed is what the player is still owed, or owes when it is negative.
+	Owed int `json:"owed"`
+}
+
+// Stats works out player's record.
+func Stats(player Player, tournaments []TournamentResult, cashGames []CashResult, balances Balances) PlayerStats {
+	stats := PlayerStats{Player: player.Name, Wins: player.Wins, Net: player.Net, Owed: balances[player.Name]}
+
+	for _, result := range tournaments {
+		for _, placing := range result.Placings {
+			if placing.Player != player.Name {
+				continue
+			}
+			stats.Tournaments++
+			if placing.Place == 1 {
+				stats.TournamentWins++
+			}
+			if stats.BestPlace == 0 || placing.Place < stats.BestPlace {
+				stats.BestPlace = placing.Place
+			}
+			if placing.Prize > 0 {
+				stats.InTheMoney++
+			}
+			stats.Winnings += placing.Prize - placing.Paid
+		}
+	}
+
+	for _, game := range cashGames {
+		for _, stake := range game.Players {
+			if stake.Player == player.Name {
+				stats.CashGames++
+			}
+		}
+	}
+	return stats
+}
+
+// Stats is the record of everyone in the league, in league order.
+func (f *FileSystemPlayerStore) Stats() []PlayerStats {
+	league := f.GetLeague()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := make([]PlayerStats, len(league))
+	for i, player := range league {
+		stats[i] = Stats(player, f.tournaments, f.cashGames, f.balances)
+	}
+	return stats
+}
+
+// PlayerStats is one player's record.
+func (f *FileSystemPlayerStore) PlayerStats(name string) (PlayerStats, error) {
+	for _, stats := range f.Stats() {
+		if stats.Player == name {
+			return stats, nil
+		}
+	}
+	return PlayerStats{}, fmt.Errorf("there is no %s in the league", name)
+}
@@ -0,0 +1,22 @@
+package poker
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	tournaments := []TournamentResult{
+		{Placings: []Placing{{Place: 1, Player: "Cleo", Paid: 10, Prize: 30}, {Place: 2, Player: "Chris", Paid: 20}}},
+		{Placings: []Placing{{Place: 1, Player: "Chris", Paid: 10, Prize: 20}, {Place: 2, Player: "Cleo", Paid: 10}}},
+	}
+	cashGames := []CashResult{{Players: []CashStake{{Player: "Chris", Net: 15}, {Player: "Cleo", Net: -15}}}}
+
+	got := Stats(Player{Name: "Chris", Wins: 3, Net: 15}, tournaments, cashGames, Balances{"Chris": 5})
+	want := PlayerStats{
+		Player: "Chris", Wins: 3,
+		Tournaments: 2, TournamentWins: 1, BestPlace: 1, InTheMoney: 1, Winnings: -10,
+		CashGames: 1, Net: 15, Owed: 5,
+	}
+
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
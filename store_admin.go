@@ -0,0 +1,244 @@
+package poker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// AddPlayer puts a new player in the league with no wins.
+func (f *FileSystemPlayerStore) AddPlayer(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if name == "" {
+		return errors.New("a player needs a name")
+	}
+	if f.league.Find(name) != nil {
+		return fmt.Errorf("%s is already in the league", name)
+	}
+	f.league = append(f.league, Player{Name: name})
+	return f.save()
+}
+
+// RenamePlayer renames a player everywhere but in games still being played.
+func (f *FileSystemPlayerStore) RenamePlayer(from, to string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if to == "" {
+		return errors.New("a player needs a name")
+	}
+	player := f.league.Find(from)
+	if player == nil {
+		return fmt.Errorf("there is no %s in the league", from)
+	}
+	if f.league.Find(to) != nil {
+		return fmt.Errorf("%s is already in the league, merge the two instead", to)
+	}
+
+	player.Name = to
+	f.renameHistory(from, to)
+	return f.save()
+}
+
+// MergePlayers adds from's wins, nets and history to into.
+func (f *FileSystemPlayerStore) MergePlayers(from, into string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if from == into {
+		return fmt.Errorf("%s cannot be merged with themselves", from)
+	}
+	merged, kept := f.league.Find(from), f.league.Find(into)
+	switch {
+	case merged == nil:
+		return fmt.Errorf("there is no %s in the league", from)
+	case kept == nil:
+		return fmt.Errorf("there is no %s in the league", into)
+	}
+
+	kept.Wins += merged.Wins
+	kept.Net += merged.Net
+	for i, player := range f.league {
+		if player.Name == from {
+			f.league = append(f.league[:i], f.league[i+1:]...)
+			break
+		}
+	}
+	f.renameHistory(from, into)
+	return f.save()
+}
+
+func (f *FileSystemPlayerStore) renameHistory(from, to string) {
+	rename := func(name *string) {
+		if *name == from {
+			*name = to
+		}
+	}
+
+	for i := range f.tournaments {
+		result := &f.tournaments[i]
+		for j := range result.Placings {
+			rename(&result.Placings[j].Player)
+		}
+		if seating := result.Seating; seating != nil {
+			for j := range seating.Draw {
+				rename(&seating.Draw[j].Player)
+			}
+			for j := range seating.Moves {
+				rename(&seating.Moves[j].Player)
+			}
+			for _, table := range seating.Tables {
+				for j := range table.Seats {
+					rename(&table.Seats[j])
+				}
+			}
+		}
+	}
+	for i := range f.cashGames {
+		for j := range f.cashGames[i].Players {
+			rename(&f.cashGames[i].Players[j].Player)
+		}
+	}
+	if owed, ok := f.balances[from]; ok {
+		delete(f.balances, from)
+		f.balances[to] += owed
+	}
+}
+
+// Export writes everything as indented JSON that Import reads back.
+func (f *FileSystemPlayerStore) Export(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(f.data())
+}
+
+// Import replaces everything with an export, or an old league on its own.
+func (f *FileSystemPlayerStore) Import(r io.Reader) error {
+	data, err := readStoredData(r)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.use(data)
+	return f.save()
+}
+
+// Empty reports whether nothing has been kept yet.
+func (f *FileSystemPlayerStore) Empty() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.league) == 0 && len(f.tournaments) == 0 && len(f.cashGames) == 0 && len(f.games) == 0 && len(f.journal) == 0
+}
+
+func (f *FileSystemPlayerStore) data() storedData {
+	return storedData{
+		League: f.league, Tournaments: f.tournaments, CashGames: f.cashGames, Balances: f.balances, Games: f.games,
+		Journal: f.journal, Merged: f.merged,
+	}
+}
+
+// Check returns a line for each thing in the store that does not add up.
+func (f *FileSystemPlayerStore) Check() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return checkData(f.data())
+}
+
+func checkData(data storedData) []string {
+	var problems []string
+	problem := func(format string, a ...any) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	seen := map[string]bool{}
+	for _, player := range data.League {
+		switch {
+		case player.Name == "":
+			problem("a player in the league has no name")
+		case seen[player.Name]:
+			problem("%s is in the league more than once", player.Name)
+		}
+		seen[player.Name] = true
+	}
+
+	won, nets := map[string]int{}, map[string]int{}
+	for i, result := range data.Tournaments {
+		places := map[int]string{}
+		for _, placing := range result.Placings {
+			if other, ok := places[placing.Place]; ok {
+				problem("tournament %d has %s and %s both in %s place", i+1, other, placing.Player, Ordinal(placing.Place))
+			}
+			places[placing.Place] = placing.Player
+		}
+		if winner := result.Winner(); winner == "" {
+			problem("tournament %d has no winner", i+1)
+		} else {
+			won[winner]++
+		}
+	}
+	for _, game := range data.CashGames {
+		for _, stake := range game.Players {
+			nets[stake.Player] += stake.Net
+		}
+	}
+
+	for _, player := range data.League {
+		if player.Wins < won[player.Name] {
+			problem("%s has %d wins in the league but won %d tournaments", player.Name, player.Wins, won[player.Name])
+		}
+		if player.Net != nets[player.Name] {
+			problem("%s has a net of %d in the league but %d from cash games", player.Name, player.Net, nets[player.Name])
+		}
+		delete(won, player.Name)
+		delete(nets, player.Name)
+	}
+	for _, player := range sortedKeys(won) {
+		problem("%s won a tournament but is not in the league", player)
+	}
+	for _, player := range sortedKeys(nets) {
+		if nets[player] != 0 {
+			problem("%s played cash games but is not in the league", player)
+		}
+	}
+
+	if total := data.Balances.Total(); total != 0 {
+		problem("what is owed comes to %d rather than nothing", total)
+	}
+
+	ids := map[string]bool{}
+	for _, game := range data.Games {
+		if ids[game.ID] {
+			problem("there are two unfinished games numbered %s", game.ID)
+		}
+		ids[game.ID] = true
+	}
+
+	entries := map[string]bool{}
+	for _, entry := range data.Journal {
+		if entries[entry.ID] {
+			problem("there are two journal entries numbered %s", entry.ID)
+		}
+		entries[entry.ID] = true
+	}
+	return problems
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package poker
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestFileSystemStoreAdmin(t *testing.T) {
+	newStore := func(t *testing.T) *FileSystemPlayerStore {
+		t.Helper()
+		database, cleanDatabase := createTempFile(t, "")
+		t.Cleanup(cleanDatabase)
+
+		store, err := NewFileSystemStore(database)
+		assertNoError(t, err)
+		store.RecordWin("Chris")
+		store.RecordTournament(TournamentResult{Entrants: 2, Placings: []Placing{
+			{Place: 1, Player: "Chris", Paid: 10, Prize: 20},
+			{Place: 2, Player: "Chirs", Paid: 10},
+		}, Seating: &Seating{Draw: []SeatAssignment{{Player: "Chirs", Table: 1, Seat: 2}}}})
+		store.RecordCashGame(CashResult{Players: []CashStake{{Player: "Chirs", BoughtIn: 50, Net: -20}, {Player: "Cleo", BoughtIn: 50, Net: 20}}})
+		return store
+	}
+
+	t.Run("players can be added once", func(t *testing.T) {
+		store := newStore(t)
+
+		assertNoError(t, store.AddPlayer("Dan"))
+		if store.league.Find("Dan") == nil {
+			t.Error("expected Dan in the league")
+		}
+		for _, name := range []string{"Dan", ""} {
+			if err := store.AddPlayer(name); err == nil {
+				t.Errorf("expected an error adding %q", name)
+			}
+		}
+	})
+
+	t.Run("a rename reaches everything kept", func(t *testing.T) {
+		store := newStore(t)
+
+		assertNoError(t, store.RenamePlayer("Chirs", "Christine"))
+
+		if store.league.Find("Chirs") != nil || store.league.Find("Christine") == nil {
+			t.Errorf("got league %v", store.league)
+		}
+		if got := store.tournaments[0].Placings[1].Player; got != "Christine" {
+			t.Errorf("got %s in second place", got)
+		}
+		if got := store.tournaments[0].Seating.Draw[0].Player; got != "Christine" {
+			t.Errorf("got %s in the draw", got)
+		}
+		if got := store.cashGames[0].Players[0].Player; got != "Christine" {
+			t.Errorf("got %s in the cash game", got)
+		}
+		if _, ok := store.balances["Chirs"]; ok {
+			t.Errorf("got balances %v", store.balances)
+		}
+		if err := store.RenamePlayer("Christine", "Chris"); err == nil {
+			t.Error("expected an error renaming onto someone in the league")
+		}
+		if err := store.RenamePlayer("Nobody", "Somebody"); err == nil {
+			t.Error("expected an error renaming someone not in the league")
+		}
+	})
+
+	t.Run("merging adds two players together", func(t *testing.T) {
+		store := newStore(t)
+		store.RecordWin("Chirs")
+
+		assertNoError(t, store.MergePlayers("Chirs", "Chris"))
+
+		want := Player{Name: "Chris", Wins: 3, Net: -20}
+		if got := store.league.Find("Chris"); got == nil || *got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+		if store.league.Find("Chirs") != nil {
+			t.Error("expected Chirs to have left the league")
+		}
+		if problems := store.Check(); problems != nil {
+			t.Errorf("expected the merged store to add up, got %v", problems)
+		}
+		if err := store.MergePlayers("Chris", "Chris"); err == nil {
+			t.Error("expected an error merging a player with themselves")
+		}
+	})
+
+	t.Run("an export can be imported", func(t *testing.T) {
+		store := newStore(t)
+		exported := &bytes.Buffer{}
+		assertNoError(t, store.Export(exported))
+
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+		other, _ := NewFileSystemStore(database)
+		if !other.Empty() {
+			t.Error("expected a new store to be empty")
+		}
+
+		assertNoError(t, other.Import(exported))
+
+		if !reflect.DeepEqual(other.data(), store.data()) {
+			t.Errorf("got %+v want %+v", other.data(), store.data())
+		}
+		reopened, _ := NewFileSystemStore(database)
+		if reopened.GetPlayerScore("Chris") != 2 {
+			t.Error("expected the import to be saved")
+		}
+	})
+}
+
+func TestCheckData(t *testing.T) {
+	data := storedData{
+		League: League{{Name: "Chris", Wins: 0, Net: 5}, {Name: "Chris"}, {Name: ""}},
+		Tournaments: []TournamentResult{
+			{Placings: []Placing{{Place: 1, Player: "Chris"}, {Place: 2, Player: "Cleo"}, {Place: 2, Player: "Dan"}}},
+			{Placings: []Placing{{Place: 2, Player: "Cleo"}}},
+			{Placings: []Placing{{Place: 1, Player: "Ruth"}}},
+		},
+		CashGames: []CashResult{{Players: []CashStake{{Player: "Dan", Net: 10}, {Player: "Chris", Net: -10}}}},
+		Balances:  Balances{"Chris": 15},
+		Games:     []Checkpoint{{ID: "1"}, {ID: "1"}},
+	}
+
+	want := []string{
+		"Chris is in the league more than once",
+		"a player in the league has no name",
+		"tournament 1 has Cleo and Dan both in 2nd place",
+		"tournament 2 has no winner",
+		"Chris has 0 wins in the league but won 1 tournaments",
+		"Chris has a net of 5 in the league but -10 from cash games",
+		"Ruth won a tournament but is not in the league",
+		"Dan played cash games but is not in the league",
+		"what is owed comes to 15 rather than nothing",
+		"there are two unfinished games numbered 1",
+	}
+
+	if got := checkData(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("got problems\n%q\nwant\n%q", got, want)
+	}
+}
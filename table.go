@@ -0,0 +1,165 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+const (
+	MinSeats = 2
+	MaxSeats = 10
+)
+
+// Table keeps the stacks and the button between hands.
+type Table struct {
+	Seats   []*Seat
+	Button  int
+	Hand    *Hand
+	Variant Variant
+
+	hands int
+	seed  int64
+	rng   *rand.Rand
+	out   io.Writer
+}
+
+// NewTable seats players with the same stack; the same seed deals the same cards.
+func NewTable(players []string, stack int, seed int64, out io.Writer) (*Table, error) {
+	if len(players) < MinSeats || len(players) > MaxSeats {
+		return nil, fmt.Errorf("a table seats %d to %d players, not %d", MinSeats, MaxSeats, len(players))
+	}
+	if stack <= 0 {
+		return nil, fmt.Errorf("starting stack must be positive, got %d", stack)
+	}
+
+	table := &Table{
+		Button:  len(players) - 1,
+		Variant: TexasHoldemVariant,
+		seed:    seed,
+		rng:     rand.New(rand.NewSource(seed)),
+		out:     out,
+	}
+	for _, player := range players {
+		table.Seats = append(table.Seats, &Seat{Player: player, Stack: stack})
+	}
+	return table, nil
+}
+
+// DealHand moves the button on and deals everyone with chips.
+func (t *Table) DealHand(blinds BlindLevel) (*Hand, error) {
+	if t.Hand != nil && !t.Hand.Finished() {
+		return nil, errors.New("the current hand has not finished")
+	}
+	if blinds.Break {
+		return nil, errors.New("no cards are dealt during a break")
+	}
+
+	var seats []*Seat
+	button := 0
+	t.Button = t.nextWithChips(t.Button)
+	for i := range t.Seats {
+		seat := t.Seats[(t.Button+i)%len(t.Seats)]
+		if seat.Stack > 0 {
+			seats = append(seats, seat)
+		}
+	}
+	if len(seats) < MinSeats {
+		return nil, errors.New("only one player has chips left")
+	}
+	if len(seats) > t.Variant.MaxSeats {
+		return nil, fmt.Errorf("%s is dealt to at most %d players, not %d", t.Variant, t.Variant.MaxSeats, len(seats))
+	}
+
+	deck := t.Variant.Deck()
+	deck.Shuffle(t.rng)
+
+	t.hands++
+	hand, err := newHand(t.hands, t.Variant, seats, button, blinds, deck, t.out)
+	if err != nil {
+		return nil, err
+	}
+	t.Hand = hand
+	return hand, nil
+}
+
+func (t *Table) nextWithChips(seat int) int {
+	for i := 1; i <= len(t.Seats); i++ {
+		next := (seat + i) % len(t.Seats)
+		if t.Seats[next].Stack > 0 {
+			return next
+		}
+	}
+	return seat
+}
+
+// PlayersWithChips lists who is still in, in seat order.
+func (t *Table) PlayersWithChips() []string {
+	var players []string
+	for _, seat := range t.Seats {
+		if seat.Stack > 0 {
+			players = append(players, seat.Player)
+		}
+	}
+	return players
+}
+
+// TableCheckpoint is a table between hands.
+type TableCheckpoint struct {
+	Seats  []SeatCheckpoint `json:"seats"`
+	Button int              `json:"button"`
+	Hands  int              `json:"hands"`
+	Seed   int64            `json:"seed"`
+}
+
+type SeatCheckpoint struct {
+	Player string `json:"player"`
+	Stack  int    `json:"stack"`
+}
+
+// Checkpoint saves the table as it was before any hand being played.
+func (t *Table) Checkpoint() TableCheckpoint {
+	checkpoint := TableCheckpoint{Button: t.Button, Hands: t.hands, Seed: t.seed}
+
+	inHand := map[string]int{}
+	if t.Hand != nil && !t.Hand.Finished() {
+		for _, seat := range t.Hand.Seats {
+			inHand[seat.Player] = seat.InPot
+		}
+		checkpoint.Button = (t.Button + len(t.Seats) - 1) % len(t.Seats)
+		checkpoint.Hands--
+	}
+
+	for _, seat := range t.Seats {
+		checkpoint.Seats = append(checkpoint.Seats, SeatCheckpoint{Player: seat.Player, Stack: seat.Stack + inHand[seat.Player]})
+	}
+	return checkpoint
+}
+
+// RestoreTable seats everyone as they were saved, with a fresh shuffle.
+func RestoreTable(checkpoint TableCheckpoint, out io.Writer) (*Table, error) {
+	if len(checkpoint.Seats) < MinSeats || len(checkpoint.Seats) > MaxSeats {
+		return nil, fmt.Errorf("a table seats %d to %d players, not %d", MinSeats, MaxSeats, len(checkpoint.Seats))
+	}
+	if checkpoint.Button < 0 || checkpoint.Button >= len(checkpoint.Seats) {
+		return nil, fmt.Errorf("there is no seat %d for the button", checkpoint.Button+1)
+	}
+
+	seed := checkpoint.Seed + int64(checkpoint.Hands)
+	table := &Table{
+		Button:  checkpoint.Button,
+		Variant: TexasHoldemVariant,
+		hands:   checkpoint.Hands,
+		seed:    checkpoint.Seed,
+		rng:     rand.New(rand.NewSource(seed)),
+		out:     out,
+	}
+	for _, seat := range checkpoint.Seats {
+		if seat.Stack < 0 {
+			return nil, fmt.Errorf("%s cannot have %d chips", seat.Player, seat.Stack)
+		}
+		table.Seats = append(table.Seats, &Seat{Player: seat.Player, Stack: seat.Stack})
+	}
+	return table, nil
+}
@@ -0,0 +1,32 @@
+package poker
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrNotATerminal is returned when input is a file or pipe, or the system is unsupported.
+var ErrNotATerminal = errors.New("not a terminal")
+
+// Terminal is the CLI's keyboard and screen; see terminal_*.go.
+type Terminal struct {
+	in  *os.File
+	out *os.File
+}
+
+// OpenTerminal checks that in and out are both a terminal.
+func OpenTerminal(in, out *os.File) (*Terminal, error) {
+	if !isTerminal(in) || !isTerminal(out) {
+		return nil, ErrNotATerminal
+	}
+	return &Terminal{in: in, out: out}, nil
+}
+
+// Size is the screen in characters, 80 by 24 when unknown.
+func (t *Terminal) Size() (int, int) {
+	width, height, err := terminalSize(t.out)
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
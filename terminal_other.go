@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package poker
+
+import "os"
+
+// Elsewhere there is no full screen clock, and the CLI prints the clock as
+// lines of text instead.
+
+func isTerminal(*os.File) bool { return false }
+
+func terminalSize(*os.File) (int, int, error) { return 0, 0, ErrNotATerminal }
+
+func (t *Terminal) raw() (func(), error) { return nil, ErrNotATerminal }
+
+func (t *Terminal) ReadKey() (byte, bool, error) { return 0, false, ErrNotATerminal }
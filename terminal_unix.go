@@ -0,0 +1,77 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package poker
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// keyWait is how long ReadKey waits for a key, in tenths of a second.
+const keyWait = 5
+
+func isTerminal(f *os.File) bool {
+	_, err := getTermios(f)
+	return err == nil
+}
+
+func getTermios(f *os.File) (*syscall.Termios, error) {
+	termios := &syscall.Termios{}
+	if err := ioctl(f, ioctlGetTermios, unsafe.Pointer(termios)); err != nil {
+		return nil, err
+	}
+	return termios, nil
+}
+
+func setTermios(f *os.File, termios *syscall.Termios) error {
+	return ioctl(f, ioctlSetTermios, unsafe.Pointer(termios))
+}
+
+func terminalSize(f *os.File) (int, int, error) {
+	var size struct{ rows, cols, x, y uint16 }
+	if err := ioctl(f, syscall.TIOCGWINSZ, unsafe.Pointer(&size)); err != nil {
+		return 0, 0, err
+	}
+	return int(size.cols), int(size.rows), nil
+}
+
+func ioctl(f *os.File, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// raw turns off echo and line buffering, returning how to undo it.
+func (t *Terminal) raw() (func(), error) {
+	saved, err := getTermios(t.in)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *saved
+	raw.Iflag &^= syscall.IXON | syscall.ICRNL
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 0
+	raw.Cc[syscall.VTIME] = keyWait
+	if err := setTermios(t.in, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() { setTermios(t.in, saved) }, nil
+}
+
+// ReadKey waits a moment for a key and reports false if none was pressed.
+func (t *Terminal) ReadKey() (byte, bool, error) {
+	var key [1]byte
+	n, err := syscall.Read(int(t.in.Fd()), key[:])
+	if err == syscall.EINTR || err == syscall.EAGAIN {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return key[0], n == 1, nil
+}
@@ -6,13 +6,23 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 type StubPlayerStore struct {
-	scores   map[string]int
-	winCalls []string
-	league   []Player
+	scores      map[string]int
+	winCalls    []string
+	league      []Player
+	tournaments []TournamentResult
+	cashGames   []CashResult
+	balances    Balances
+	payments    []Payment
+
+	// games save checkpoints from their own timers
+	mu          sync.Mutex
+	checkpoints []Checkpoint
 }
 
 func (s *StubPlayerStore) GetPlayerScore(name string) int {
@@ -21,8 +31,9 @@ func (s *StubPlayerStore) GetPlayerScore(name string) int {
 	return score
 }
 
-func (s *StubPlayerStore) RecordWin(name string) {
+func (s *StubPlayerStore) RecordWin(name string) error {
 	s.winCalls = append(s.winCalls, name)
+	return nil
 }
 
 func (s *StubPlayerStore) GetLeague() League {
@@ -30,6 +41,207 @@ func (s *StubPlayerStore) GetLeague() League {
 
 }
 
+// RecordTournament keeps the result and counts the win, like the real stores.
+func (s *StubPlayerStore) RecordTournament(result TournamentResult) error {
+	s.tournaments = append(s.tournaments, result)
+	s.winCalls = append(s.winCalls, result.Winner())
+	return nil
+}
+
+func (s *StubPlayerStore) Tournaments() []TournamentResult {
+	return s.tournaments
+}
+
+func (s *StubPlayerStore) RecordCashGame(result CashResult) error {
+	s.cashGames = append(s.cashGames, result)
+	return nil
+}
+
+func (s *StubPlayerStore) Balances() Balances {
+	return s.balances
+}
+
+func (s *StubPlayerStore) RecordPayment(payment Payment) error {
+	if err := payment.Validate(); err != nil {
+		return err
+	}
+	s.payments = append(s.payments, payment)
+	return nil
+}
+
+func (s *StubPlayerStore) SaveCheckpoint(checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, saved := range s.checkpoints {
+		if saved.ID == checkpoint.ID {
+			s.checkpoints[i] = checkpoint
+			return nil
+		}
+	}
+	s.checkpoints = append(s.checkpoints, checkpoint)
+	return nil
+}
+
+func (s *StubPlayerStore) RemoveCheckpoint(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, saved := range s.checkpoints {
+		if saved.ID == id {
+			s.checkpoints = append(s.checkpoints[:i], s.checkpoints[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *StubPlayerStore) Checkpoints() []Checkpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Checkpoint{}, s.checkpoints...)
+}
+
+// GameSpy records how a Game was driven, writing BlindAlert on Start.
+type GameSpy struct {
+	mu sync.Mutex
+
+	StartCalled bool
+	StartedWith int
+	BlindAlert  []byte
+
+	FinishCalled bool
+	FinishedWith string
+}
+
+func (g *GameSpy) Start(numberOfPlayers int, alertsDestination io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.StartCalled = true
+	g.StartedWith = numberOfPlayers
+
+	if g.BlindAlert != nil {
+		alertsDestination.Write(g.BlindAlert)
+	}
+}
+
+func (g *GameSpy) Finish(winner string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.FinishCalled = true
+	g.FinishedWith = winner
+}
+
+// startedWith and finishedWith read the spy safely while a server goroutine drives it.
+func (g *GameSpy) startedWith() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.StartedWith
+}
+
+func (g *GameSpy) finishedWith() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.FinishedWith
+}
+
+// ManualClock only moves on Advance, firing due timers in order.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock *ManualClock
+	at    time.Time
+	f     func()
+	done  bool
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &manualTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the clock by d, running due timers before it returns.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		next := c.nextDue(end)
+		if next == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		next.done = true
+		if next.at.After(c.now) {
+			c.now = next.at
+		}
+		c.mu.Unlock()
+
+		next.f()
+	}
+}
+
+// Pending reports how many timers are waiting to fire.
+func (c *ManualClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := 0
+	for _, timer := range c.timers {
+		if !timer.done {
+			pending++
+		}
+	}
+	return pending
+}
+
+func (c *ManualClock) nextDue(end time.Time) *manualTimer {
+	var next *manualTimer
+	for _, timer := range c.timers {
+		if timer.done || timer.at.After(end) {
+			continue
+		}
+		if next == nil || timer.at.Before(next.at) {
+			next = timer
+		}
+	}
+	return next
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	pending := !t.done
+	t.done = true
+	return pending
+}
+
 func AssertPlayerWin(t testing.TB, store *StubPlayerStore, winner string) {
 	t.Helper()
 
@@ -0,0 +1,585 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payouts are percentages or fixed amounts per place, never both.
+type Payouts struct {
+	Percentages []float64 `json:"percentages,omitempty"`
+	Fixed       []int     `json:"fixed,omitempty"`
+}
+
+// ParsePayouts reads "50%,30%,20%" or "500,300,200".
+func ParsePayouts(input string) (Payouts, error) {
+	var payouts Payouts
+	fields := strings.Split(input, ",")
+	percentages := strings.Contains(input, "%")
+
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if percentages {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+			if err != nil || !strings.HasSuffix(field, "%") {
+				return Payouts{}, fmt.Errorf("%q is not a percentage, write every place like 50%%", field)
+			}
+			payouts.Percentages = append(payouts.Percentages, value)
+			continue
+		}
+
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return Payouts{}, fmt.Errorf("%q is not an amount", field)
+		}
+		payouts.Fixed = append(payouts.Fixed, value)
+	}
+
+	return payouts, payouts.Validate()
+}
+
+func (p Payouts) Validate() error {
+	switch {
+	case len(p.Percentages) > 0 && len(p.Fixed) > 0:
+		return errors.New("payouts are either percentages or fixed amounts, not both")
+	case len(p.Percentages) > 0:
+		total := 0.0
+		for _, percentage := range p.Percentages {
+			if percentage <= 0 {
+				return fmt.Errorf("payout percentages must be positive, got %v%%", percentage)
+			}
+			total += percentage
+		}
+		if math.Abs(total-100) > 0.001 {
+			return fmt.Errorf("payout percentages must add up to 100%%, got %v%%", total)
+		}
+	case len(p.Fixed) > 0:
+		for _, amount := range p.Fixed {
+			if amount <= 0 {
+				return fmt.Errorf("payouts must be positive, got %d", amount)
+			}
+		}
+	default:
+		return errors.New("payouts need at least one place")
+	}
+	return nil
+}
+
+// DefaultPayouts pays more places the bigger the field.
+func DefaultPayouts(entrants int) Payouts {
+	switch {
+	case entrants < 5:
+		return Payouts{Percentages: []float64{100}}
+	case entrants < 8:
+		return Payouts{Percentages: []float64{65, 35}}
+	case entrants < 16:
+		return Payouts{Percentages: []float64{50, 30, 20}}
+	}
+	return Payouts{Percentages: []float64{40, 25, 15, 12, 8}}
+}
+
+// Prizes shares out prizePool, giving whatever is left over to the winner.
+func (p Payouts) Prizes(prizePool, entrants int) ([]int, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	places := max(len(p.Percentages), len(p.Fixed))
+	prizes := make([]int, min(places, entrants))
+
+	paid := 0
+	for i := range prizes {
+		if p.Fixed != nil {
+			prizes[i] = p.Fixed[i]
+		} else {
+			prizes[i] = int(float64(prizePool) * p.Percentages[i] / 100)
+		}
+		paid += prizes[i]
+	}
+
+	if paid > prizePool {
+		return nil, fmt.Errorf("the payouts come to %d but the prize pool is only %d", paid, prizePool)
+	}
+	if len(prizes) > 0 {
+		prizes[0] += prizePool - paid
+	}
+	return prizes, nil
+}
+
+// TournamentRules are the prices and payouts; a zero Rebuy or AddOn is not allowed.
+type TournamentRules struct {
+	BuyIn   int      `json:"buy_in"`
+	Rebuy   int      `json:"rebuy,omitempty"`
+	AddOn   int      `json:"add_on,omitempty"`
+	Payouts *Payouts `json:"payouts,omitempty"`
+
+	// TableSize is the seats per table in a draw, DefaultTableSize when zero.
+	TableSize int `json:"table_size,omitempty"`
+
+	// StartingStack is only used for the average stack, so can be zero.
+	StartingStack int `json:"starting_stack,omitempty"`
+}
+
+// Placing is where a player finished and what it cost and paid them.
+type Placing struct {
+	Place  int       `json:"place"`
+	Player string    `json:"player"`
+	OutAt  time.Time `json:"out_at,omitempty"`
+	BuyIns int       `json:"buy_ins"`
+	Rebuys int       `json:"rebuys,omitempty"`
+	AddOns int       `json:"add_ons,omitempty"`
+	Paid   int       `json:"paid,omitempty"`
+	Prize  int       `json:"prize,omitempty"`
+}
+
+// TournamentResult is everything worth keeping about a finished tournament.
+type TournamentResult struct {
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Entrants  int       `json:"entrants"`
+	PrizePool int       `json:"prize_pool"`
+	Placings  []Placing `json:"placings"`
+	Seating   *Seating  `json:"seating,omitempty"`
+}
+
+// Winner is the player who finished first.
+func (r TournamentResult) Winner() string {
+	for _, placing := range r.Placings {
+		if placing.Place == 1 {
+			return placing.Player
+		}
+	}
+	return ""
+}
+
+// TournamentStore is a PlayerStore that keeps whole tournament results.
+type TournamentStore interface {
+	PlayerStore
+	RecordTournament(TournamentResult) error
+}
+
+// TournamentGame is a Game run as a tournament with bust-outs and payouts.
+type TournamentGame interface {
+	Game
+	PlayTournament(TournamentRules)
+	Tournament() *Tournament
+}
+
+const TournamentCommandsHelp = "{Name} out, {Name} rebuys, {Name} adds on, draw {Names}, seating or button {table}"
+
+var ErrTournamentOver = errors.New("the tournament is over")
+
+// Tournament keeps the books. Players need a name only once they do something.
+type Tournament struct {
+	rules    TournamentRules
+	clock    Clock
+	to       io.Writer
+	started  time.Time
+	entrants int
+	entries  map[string]*Placing
+	out      []*Placing
+	finished bool
+	seating  *Seating
+	rng      *rand.Rand
+}
+
+// NewTournament starts the books for entrants players, announcing to to.
+func NewTournament(rules TournamentRules, entrants int, clock Clock, to io.Writer) *Tournament {
+	return &Tournament{
+		rules:    rules,
+		clock:    clock,
+		to:       to,
+		started:  clock.Now(),
+		entrants: entrants,
+		entries:  map[string]*Placing{},
+		rng:      rand.New(rand.NewSource(NewSeed())),
+	}
+}
+
+func (t *Tournament) entry(player string) *Placing {
+	entry, ok := t.entries[player]
+	if !ok {
+		entry = &Placing{Player: player, BuyIns: 1}
+		t.entries[player] = entry
+	}
+	return entry
+}
+
+// named refuses a new name once every entrant has been named.
+func (t *Tournament) named(player string) error {
+	if len(t.entries) >= t.entrants && t.entries[player] == nil {
+		return fmt.Errorf("all %d players are already named and %s is not one of them", t.entrants, player)
+	}
+	return nil
+}
+
+// canFinish refuses a winner while others are in, so every prize is paid.
+func (t *Tournament) canFinish(winner string) error {
+	if t.finished {
+		return ErrTournamentOver
+	}
+	if t.isOut(winner) {
+		return fmt.Errorf("%s is already out", winner)
+	}
+	if err := t.named(winner); err != nil {
+		return err
+	}
+	if t.Remaining() > 1 {
+		return fmt.Errorf("%s cannot win while others are still in, %d players are left", winner, t.Remaining())
+	}
+	return nil
+}
+
+func (t *Tournament) isOut(player string) bool {
+	for _, placing := range t.out {
+		if placing.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// Remaining is how many players are still in.
+func (t *Tournament) Remaining() int {
+	return t.entrants - len(t.out)
+}
+
+// AverageStack is the chips per player still in, 0 without a starting stack.
+func (t *Tournament) AverageStack() int {
+	if t.Remaining() <= 0 {
+		return 0
+	}
+	stacks := t.entrants
+	for _, entry := range t.entries {
+		stacks += entry.Rebuys + entry.AddOns
+	}
+	return stacks * t.rules.StartingStack / t.Remaining()
+}
+
+func (t *Tournament) PrizePool() int {
+	pool := t.entrants * t.rules.BuyIn
+	for _, entry := range t.entries {
+		pool += entry.Rebuys*t.rules.Rebuy + entry.AddOns*t.rules.AddOn
+	}
+	return pool
+}
+
+// Eliminate records player going out now and returns the place they finished in.
+func (t *Tournament) Eliminate(player string) (int, error) {
+	if t.finished {
+		return 0, ErrTournamentOver
+	}
+	if t.isOut(player) {
+		return 0, fmt.Errorf("%s is already out", player)
+	}
+	if t.Remaining() <= 1 {
+		return 0, fmt.Errorf("%s is the last player in, type \"%s wins\" to finish", player, player)
+	}
+	if err := t.named(player); err != nil {
+		return 0, err
+	}
+
+	entry := t.entry(player)
+	entry.Place = t.Remaining()
+	entry.OutAt = t.clock.Now()
+	t.out = append(t.out, entry)
+
+	left := fmt.Sprintf("%d players left", t.Remaining())
+	if t.Remaining() == 1 {
+		left = "one player left"
+	}
+	fmt.Fprintf(t.to, "%s is out in %s place, %s\n", player, Ordinal(entry.Place), left)
+
+	if t.seating != nil {
+		moves, err := t.seating.Remove(player)
+		if err != nil {
+			fmt.Fprintln(t.to, err)
+		}
+		t.writeMoves(moves)
+	}
+	return entry.Place, nil
+}
+
+// Rebuy buys player back in, bringing them back if they were out.
+func (t *Tournament) Rebuy(player string) error {
+	if t.finished {
+		return ErrTournamentOver
+	}
+	if t.rules.Rebuy == 0 {
+		return errors.New("rebuys are not allowed in this tournament")
+	}
+	if err := t.named(player); err != nil {
+		return err
+	}
+
+	for i, placing := range t.out {
+		if placing.Player == player {
+			t.out = append(t.out[:i], t.out[i+1:]...)
+			break
+		}
+	}
+
+	// everyone who went out after them has one fewer player left behind.
+	for i, placing := range t.out {
+		placing.Place = t.entrants - i
+	}
+
+	entry := t.entry(player)
+	entry.Rebuys++
+	entry.OutAt = time.Time{}
+	entry.Place = 0
+
+	fmt.Fprintf(t.to, "%s rebuys, the prize pool is %d\n", player, t.PrizePool())
+
+	if t.seating == nil {
+		return nil
+	}
+	if _, seated := t.seating.Find(player); !seated {
+		seat, moves, err := t.seating.Add(player)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(t.to, "%s sits at table %d seat %d\n", player, seat.Table, seat.Seat)
+		t.writeMoves(moves)
+	}
+	return nil
+}
+
+// AddOn sells player the add-on.
+func (t *Tournament) AddOn(player string) error {
+	if t.finished {
+		return ErrTournamentOver
+	}
+	if t.rules.AddOn == 0 {
+		return errors.New("there is no add-on in this tournament")
+	}
+	if t.isOut(player) {
+		return fmt.Errorf("%s is out", player)
+	}
+	if err := t.named(player); err != nil {
+		return err
+	}
+
+	t.entry(player).AddOns++
+
+	fmt.Fprintf(t.to, "%s adds on, the prize pool is %d\n", player, t.PrizePool())
+	return nil
+}
+
+// Finish crowns winner and pays the prizes.
+func (t *Tournament) Finish(winner string) (TournamentResult, error) {
+	if err := t.canFinish(winner); err != nil {
+		return TournamentResult{}, err
+	}
+
+	payouts := DefaultPayouts(t.entrants)
+	if t.rules.Payouts != nil {
+		payouts = *t.rules.Payouts
+	}
+	prizePool := t.PrizePool()
+	prizes, err := payouts.Prizes(prizePool, t.entrants)
+	if err != nil {
+		return TournamentResult{}, err
+	}
+
+	t.finished = true
+	champion := t.entry(winner)
+	champion.Place = 1
+
+	result := TournamentResult{
+		Started:   t.started,
+		Finished:  t.clock.Now(),
+		Entrants:  t.entrants,
+		PrizePool: prizePool,
+		Placings:  []Placing{*champion},
+		Seating:   t.seating,
+	}
+	for i := len(t.out) - 1; i >= 0; i-- {
+		result.Placings = append(result.Placings, *t.out[i])
+	}
+
+	for i := range result.Placings {
+		placing := &result.Placings[i]
+		placing.Paid = placing.BuyIns*t.rules.BuyIn + placing.Rebuys*t.rules.Rebuy + placing.AddOns*t.rules.AddOn
+		if placing.Place <= len(prizes) {
+			placing.Prize = prizes[placing.Place-1]
+		}
+	}
+	return result, nil
+}
+
+// DrawSeats seats and names every entrant at random.
+func (t *Tournament) DrawSeats(players []string) error {
+	if t.finished {
+		return ErrTournamentOver
+	}
+	if t.seating != nil {
+		return errors.New("seats have already been drawn")
+	}
+	if len(players) != t.entrants {
+		return fmt.Errorf("the draw needs all %d players, got %d", t.entrants, len(players))
+	}
+	for player := range t.entries {
+		if !slices.Contains(players, player) {
+			return fmt.Errorf("%s is playing but not in the draw", player)
+		}
+	}
+
+	tableSize := t.rules.TableSize
+	if tableSize == 0 {
+		tableSize = DefaultTableSize
+	}
+	seating, err := DrawSeats(players, tableSize, t.rng)
+	if err != nil {
+		return err
+	}
+
+	t.seating = seating
+	for _, player := range players {
+		t.entry(player)
+	}
+	seating.write(t.to)
+	return nil
+}
+
+// Seating is where everyone sits, or nil before seats are drawn.
+func (t *Tournament) Seating() *Seating {
+	return t.seating
+}
+
+func (t *Tournament) writeMoves(moves []TableMove) {
+	for _, move := range moves {
+		fmt.Fprintln(t.to, move)
+	}
+	if len(moves) > 0 && len(t.seating.Tables) == 1 {
+		fmt.Fprintln(t.to, "Final table:")
+		t.seating.write(t.to)
+	}
+}
+
+// seatingCommand runs "draw Alice Bob Cleo", "seating" and "button 2".
+func (t *Tournament) seatingCommand(input string) (bool, error) {
+	command, rest, _ := strings.Cut(input, " ")
+	switch strings.ToLower(command) {
+	case "draw":
+		return true, t.DrawSeats(ParseNames(rest))
+	case "seating":
+		if t.seating == nil {
+			return true, errors.New(`no seats have been drawn, type "draw" and everyone's names`)
+		}
+		t.seating.write(t.to)
+		return true, nil
+	case "button":
+		if t.seating == nil {
+			return true, errors.New(`no seats have been drawn, type "draw" and everyone's names`)
+		}
+		number, err := strconv.Atoi(strings.TrimSpace(rest))
+		if err != nil {
+			return true, fmt.Errorf("%q is not a table number", rest)
+		}
+		player, err := t.seating.MoveButton(number)
+		if err != nil {
+			return true, err
+		}
+		fmt.Fprintf(t.to, "%s has the button at table %d\n", player, number)
+		return true, nil
+	}
+	return false, nil
+}
+
+// TournamentCheckpoint is the books part way through; Out is first out first.
+type TournamentCheckpoint struct {
+	Rules    TournamentRules `json:"rules"`
+	Started  time.Time       `json:"started"`
+	Entrants int             `json:"entrants"`
+	Entries  []Placing       `json:"entries,omitempty"`
+	Out      []string        `json:"out,omitempty"`
+	Seating  *Seating        `json:"seating,omitempty"`
+}
+
+func (t *Tournament) Checkpoint() TournamentCheckpoint {
+	checkpoint := TournamentCheckpoint{Rules: t.rules, Started: t.started, Entrants: t.entrants, Seating: t.seating}
+	for _, entry := range t.entries {
+		checkpoint.Entries = append(checkpoint.Entries, *entry)
+	}
+	sort.Slice(checkpoint.Entries, func(i, j int) bool { return checkpoint.Entries[i].Player < checkpoint.Entries[j].Player })
+	for _, placing := range t.out {
+		checkpoint.Out = append(checkpoint.Out, placing.Player)
+	}
+	return checkpoint
+}
+
+// RestoreTournament opens the books again where checkpoint left them.
+func RestoreTournament(checkpoint TournamentCheckpoint, clock Clock, to io.Writer) (*Tournament, error) {
+	t := NewTournament(checkpoint.Rules, checkpoint.Entrants, clock, to)
+	t.started, t.seating = checkpoint.Started, checkpoint.Seating
+
+	for _, entry := range checkpoint.Entries {
+		entry := entry
+		t.entries[entry.Player] = &entry
+	}
+	for _, player := range checkpoint.Out {
+		entry, ok := t.entries[player]
+		if !ok {
+			return nil, fmt.Errorf("%s went out without ever entering", player)
+		}
+		t.out = append(t.out, entry)
+	}
+	if len(t.out) >= t.entrants {
+		return nil, fmt.Errorf("all %d players are out", t.entrants)
+	}
+	return t, nil
+}
+
+// tournamentCommand runs "Alice out", "Alice rebuys" and "Alice adds on".
+func tournamentCommand(game Game, input string) (bool, error) {
+	tournamentGame, ok := game.(TournamentGame)
+	if !ok || tournamentGame.Tournament() == nil {
+		return false, nil
+	}
+	tournament := tournamentGame.Tournament()
+
+	input = strings.TrimSpace(input)
+	if handled, err := tournament.seatingCommand(input); handled {
+		return true, err
+	}
+	for _, command := range []struct {
+		suffix string
+		run    func(string) error
+	}{
+		{" out", func(player string) error {
+			_, err := tournament.Eliminate(player)
+			return err
+		}},
+		{" rebuys", tournament.Rebuy},
+		{" adds on", tournament.AddOn},
+	} {
+		if player, ok := strings.CutSuffix(input, command.suffix); ok && player != "" {
+			return true, command.run(player)
+		}
+	}
+	return false, nil
+}
+
+// Ordinal writes places the way people say them: 1st, 2nd, 3rd, 4th, 11th.
+func Ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
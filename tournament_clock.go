@@ -0,0 +1,301 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrClockPaused     = errors.New("the clock is already paused")
+	ErrClockRunning    = errors.New("the clock is already running")
+	ErrClockStopped    = errors.New("the clock has been stopped")
+	ErrNoNextLevel     = errors.New("already at the final blind level")
+	ErrNoPreviousLevel = errors.New("already at the first blind level")
+)
+
+// ClockState is a snapshot of where a tournament clock is.
+type ClockState struct {
+	Level     int
+	Blinds    BlindLevel
+	Remaining time.Duration
+	Paused    bool
+}
+
+// TournamentClock schedules every level with the BlindAlerter, rescheduling when it moves.
+type TournamentClock struct {
+	mu sync.Mutex
+
+	alerter BlindAlerter
+	to      io.Writer
+	levels  []BlindLevel
+	clock   Clock
+
+	level     int
+	remaining time.Duration
+	anchor    time.Time
+	paused    bool
+	stopped   bool
+	pending   []Timer
+}
+
+func NewTournamentClock(levels []BlindLevel, alerter BlindAlerter, to io.Writer, clock Clock) *TournamentClock {
+	return &TournamentClock{
+		alerter: alerter,
+		to:      to,
+		levels:  levels,
+		clock:   clock,
+	}
+}
+
+// Start puts the clock on the first level and schedules every alert.
+func (c *TournamentClock) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.levels) == 0 {
+		return
+	}
+
+	c.moveTo(0, c.levels[0].Duration)
+}
+
+// StartAt carries the clock on from a saved level and time left.
+func (c *TournamentClock) StartAt(level int, remaining time.Duration, paused bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if level < 0 || level >= len(c.levels) {
+		return fmt.Errorf("there is no blind level %d, the structure has %d", level+1, len(c.levels))
+	}
+	if remaining < 0 || remaining > c.levels[level].Duration {
+		return fmt.Errorf("level %d cannot have %v left, it only lasts %v", level+1, remaining, c.levels[level].Duration)
+	}
+
+	c.paused = paused
+	c.moveTo(level, remaining)
+	return nil
+}
+
+func (c *TournamentClock) Pause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkRunning(); err != nil {
+		return err
+	}
+
+	c.level, c.remaining = c.position()
+	c.cancelPending()
+	c.paused = true
+	c.writeStatus()
+
+	return nil
+}
+
+func (c *TournamentClock) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return ErrClockStopped
+	}
+	if !c.paused {
+		return ErrClockRunning
+	}
+
+	c.paused = false
+	c.moveTo(c.level, c.remaining)
+
+	return nil
+}
+
+// Skip jumps straight to the start of the next level.
+func (c *TournamentClock) Skip() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return ErrClockStopped
+	}
+
+	level, _ := c.position()
+	if level+1 >= len(c.levels) {
+		return ErrNoNextLevel
+	}
+
+	c.moveTo(level+1, c.levels[level+1].Duration)
+	return nil
+}
+
+// Back returns to the start of the previous level.
+func (c *TournamentClock) Back() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return ErrClockStopped
+	}
+
+	level, _ := c.position()
+	if level == 0 {
+		return ErrNoPreviousLevel
+	}
+
+	c.moveTo(level-1, c.levels[level-1].Duration)
+	return nil
+}
+
+// Stop cancels every alert to come; a stopped clock cannot restart.
+func (c *TournamentClock) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.level, c.remaining = c.position()
+	c.cancelPending()
+	c.stopped = true
+}
+
+func (c *TournamentClock) State() ClockState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	level, remaining := c.position()
+
+	state := ClockState{Level: level, Remaining: remaining, Paused: c.paused}
+	if level < len(c.levels) {
+		state.Blinds = c.levels[level]
+	}
+	return state
+}
+
+// Next is the level after the current one, and false at the last level.
+func (c *TournamentClock) Next() (BlindLevel, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	level, _ := c.position()
+	if level+1 >= len(c.levels) {
+		return BlindLevel{}, false
+	}
+	return c.levels[level+1], true
+}
+
+func (c *TournamentClock) checkRunning() error {
+	if c.stopped {
+		return ErrClockStopped
+	}
+	if c.paused {
+		return ErrClockPaused
+	}
+	return nil
+}
+
+// moveTo anchors the clock at level, rescheduling the rest when running.
+func (c *TournamentClock) moveTo(level int, remaining time.Duration) {
+	c.cancelPending()
+
+	c.level = level
+	c.remaining = remaining
+	c.anchor = c.clock.Now()
+
+	if c.paused {
+		c.writeStatus()
+		return
+	}
+
+	c.schedule(0, c.alertFor(level, remaining))
+
+	at := remaining
+	for i := level + 1; i < len(c.levels); i++ {
+		c.schedule(at, c.alertFor(i, c.levels[i].Duration))
+		at += c.levels[i].Duration
+	}
+}
+
+func (c *TournamentClock) schedule(at time.Duration, alert BlindAlert) {
+	c.pending = append(c.pending, c.alerter.ScheduleAlertAt(at, alert, c.to))
+}
+
+func (c *TournamentClock) alertFor(level int, remaining time.Duration) BlindAlert {
+	alert := BlindAlert{Level: c.levels[level]}
+	if level+1 < len(c.levels) {
+		alert.Next = c.levels[level+1]
+		alert.NextIn = remaining
+	}
+	return alert
+}
+
+func (c *TournamentClock) cancelPending() {
+	for _, timer := range c.pending {
+		timer.Stop()
+	}
+	c.pending = nil
+}
+
+// position works out the current level and the time left in it.
+func (c *TournamentClock) position() (int, time.Duration) {
+	level, remaining := c.level, c.remaining
+	if c.paused || c.stopped || len(c.levels) == 0 {
+		return level, remaining
+	}
+
+	elapsed := c.clock.Now().Sub(c.anchor)
+	for elapsed >= remaining && level+1 < len(c.levels) {
+		elapsed -= remaining
+		level++
+		remaining = c.levels[level].Duration
+	}
+
+	return level, max(remaining-elapsed, 0)
+}
+
+func (c *TournamentClock) writeStatus() {
+	alert := c.alertFor(c.level, c.remaining)
+	fmt.Fprintf(c.to, "Clock paused at %v%s\n", alert.Level, alert.upcoming())
+}
+
+// ClockedGame is a Game that runs a tournament clock players can control.
+type ClockedGame interface {
+	Game
+	Clock() *TournamentClock
+}
+
+const ClockCommandsHelp = "pause, resume, skip or back"
+
+// isClockCommand reports whether command moves or stops the clock.
+func isClockCommand(command string) bool {
+	switch strings.ToLower(strings.TrimSpace(command)) {
+	case "pause", "resume", "skip", "back":
+		return true
+	}
+	return false
+}
+
+// controlClock applies a clock command, reporting whether input was one.
+func controlClock(game Game, command string) (bool, error) {
+	var control func(*TournamentClock) error
+
+	switch strings.ToLower(strings.TrimSpace(command)) {
+	case "pause":
+		control = (*TournamentClock).Pause
+	case "resume":
+		control = (*TournamentClock).Resume
+	case "skip":
+		control = (*TournamentClock).Skip
+	case "back":
+		control = (*TournamentClock).Back
+	default:
+		return false, nil
+	}
+
+	clocked, ok := game.(ClockedGame)
+	if !ok || clocked.Clock() == nil {
+		return true, errors.New("this game has no clock running")
+	}
+
+	return true, control(clocked.Clock())
+}
@@ -0,0 +1,214 @@
+package poker
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scheduledAlert struct {
+	at    time.Duration
+	alert BlindAlert
+	timer *stubTimer
+}
+
+type stubTimer struct {
+	stopped bool
+}
+
+func (s *stubTimer) Stop() bool {
+	s.stopped = true
+	return true
+}
+
+type spyAlerter struct {
+	scheduled []scheduledAlert
+}
+
+func (s *spyAlerter) ScheduleAlertAt(at time.Duration, alert BlindAlert, to io.Writer) Timer {
+	timer := &stubTimer{}
+	s.scheduled = append(s.scheduled, scheduledAlert{at, alert, timer})
+	return timer
+}
+
+// live returns the alerts that are still waiting to fire.
+func (s *spyAlerter) live() []scheduledAlert {
+	var live []scheduledAlert
+	for _, a := range s.scheduled {
+		if !a.timer.stopped {
+			live = append(live, a)
+		}
+	}
+	return live
+}
+
+var (
+	level100 = BlindLevel{SmallBlind: 50, BigBlind: 100, Duration: 10 * time.Minute}
+	level200 = BlindLevel{SmallBlind: 100, BigBlind: 200, Duration: 10 * time.Minute}
+	level400 = BlindLevel{SmallBlind: 200, BigBlind: 400, Duration: 10 * time.Minute}
+
+	testLevels = []BlindLevel{level100, level200, level400}
+)
+
+func newTestClock(out io.Writer) (*TournamentClock, *spyAlerter, *ManualClock) {
+	alerter := &spyAlerter{}
+	fake := NewManualClock(time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))
+
+	return NewTournamentClock(testLevels, alerter, out, fake), alerter, fake
+}
+
+func TestTournamentClock(t *testing.T) {
+	t.Run("start schedules every level", func(t *testing.T) {
+		clock, alerter, _ := newTestClock(io.Discard)
+
+		clock.Start()
+
+		assertLiveAlerts(t, alerter, []scheduledAlert{
+			{at: 0, alert: BlindAlert{level100, level200, 10 * time.Minute}},
+			{at: 10 * time.Minute, alert: BlindAlert{level200, level400, 10 * time.Minute}},
+			{at: 20 * time.Minute, alert: BlindAlert{Level: level400}},
+		})
+	})
+
+	t.Run("pause cancels pending alerts and reports the time left", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		clock, alerter, fake := newTestClock(out)
+
+		clock.Start()
+		fake.Advance(14 * time.Minute)
+		assertNoClockError(t, clock.Pause())
+
+		assertLiveAlerts(t, alerter, nil)
+		assertClockState(t, clock.State(), ClockState{Level: 1, Blinds: level200, Remaining: 6 * time.Minute, Paused: true})
+
+		if !strings.Contains(out.String(), "Clock paused at 100/200, next 200/400 in 6m0s") {
+			t.Errorf("expected a pause message but got %q", out.String())
+		}
+	})
+
+	t.Run("time does not pass while paused", func(t *testing.T) {
+		clock, alerter, fake := newTestClock(io.Discard)
+
+		clock.Start()
+		fake.Advance(4 * time.Minute)
+		clock.Pause()
+		fake.Advance(time.Hour)
+		assertNoClockError(t, clock.Resume())
+
+		assertClockState(t, clock.State(), ClockState{Level: 0, Blinds: level100, Remaining: 6 * time.Minute})
+		assertLiveAlerts(t, alerter, []scheduledAlert{
+			{at: 0, alert: BlindAlert{level100, level200, 6 * time.Minute}},
+			{at: 6 * time.Minute, alert: BlindAlert{level200, level400, 10 * time.Minute}},
+			{at: 16 * time.Minute, alert: BlindAlert{Level: level400}},
+		})
+	})
+
+	t.Run("skip and back move a whole level", func(t *testing.T) {
+		clock, alerter, fake := newTestClock(io.Discard)
+
+		clock.Start()
+		fake.Advance(3 * time.Minute)
+		assertNoClockError(t, clock.Skip())
+
+		assertClockState(t, clock.State(), ClockState{Level: 1, Blinds: level200, Remaining: 10 * time.Minute})
+		assertLiveAlerts(t, alerter, []scheduledAlert{
+			{at: 0, alert: BlindAlert{level200, level400, 10 * time.Minute}},
+			{at: 10 * time.Minute, alert: BlindAlert{Level: level400}},
+		})
+
+		fake.Advance(time.Minute)
+		assertNoClockError(t, clock.Back())
+		assertClockState(t, clock.State(), ClockState{Level: 0, Blinds: level100, Remaining: 10 * time.Minute})
+	})
+
+	t.Run("skip while paused stays paused", func(t *testing.T) {
+		clock, alerter, _ := newTestClock(io.Discard)
+
+		clock.Start()
+		clock.Pause()
+		assertNoClockError(t, clock.Skip())
+
+		assertLiveAlerts(t, alerter, nil)
+		assertClockState(t, clock.State(), ClockState{Level: 1, Blinds: level200, Remaining: 10 * time.Minute, Paused: true})
+	})
+
+	t.Run("the clock cannot move past either end", func(t *testing.T) {
+		clock, _, fake := newTestClock(io.Discard)
+
+		clock.Start()
+		assertClockError(t, clock.Back(), ErrNoPreviousLevel)
+
+		fake.Advance(25 * time.Minute)
+		assertClockError(t, clock.Skip(), ErrNoNextLevel)
+	})
+
+	t.Run("the final level never runs out", func(t *testing.T) {
+		clock, _, fake := newTestClock(io.Discard)
+
+		clock.Start()
+		fake.Advance(3 * time.Hour)
+
+		assertClockState(t, clock.State(), ClockState{Level: 2, Blinds: level400, Remaining: 0})
+	})
+
+	t.Run("pause and resume only work from the other state", func(t *testing.T) {
+		clock, _, _ := newTestClock(io.Discard)
+
+		clock.Start()
+		assertClockError(t, clock.Resume(), ErrClockRunning)
+		clock.Pause()
+		assertClockError(t, clock.Pause(), ErrClockPaused)
+	})
+
+	t.Run("stop cancels everything for good", func(t *testing.T) {
+		clock, alerter, _ := newTestClock(io.Discard)
+
+		clock.Start()
+		clock.Stop()
+
+		assertLiveAlerts(t, alerter, nil)
+		assertClockError(t, clock.Pause(), ErrClockStopped)
+		assertClockError(t, clock.Skip(), ErrClockStopped)
+	})
+}
+
+func assertLiveAlerts(t testing.TB, alerter *spyAlerter, want []scheduledAlert) {
+	t.Helper()
+
+	got := alerter.live()
+	if len(got) != len(want) {
+		t.Fatalf("got %d pending alerts %v, want %d", len(got), got, len(want))
+	}
+
+	for i := range want {
+		if got[i].at != want[i].at || got[i].alert != want[i].alert {
+			t.Errorf("alert %d: got %v at %v, want %v at %v", i, got[i].alert, got[i].at, want[i].alert, want[i].at)
+		}
+	}
+}
+
+func assertClockState(t testing.TB, got, want ClockState) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("got clock state %+v, want %+v", got, want)
+	}
+}
+
+func assertNoClockError(t testing.TB, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("did not expect a clock error but got %v", err)
+	}
+}
+
+func assertClockError(t testing.TB, got, want error) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,314 @@
+package poker
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var eightPM = time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+func newTestTournament(rules TournamentRules, entrants int) (*Tournament, *ManualClock, *bytes.Buffer) {
+	clock := NewManualClock(eightPM)
+	out := &bytes.Buffer{}
+	return NewTournament(rules, entrants, clock, out), clock, out
+}
+
+func TestTournament(t *testing.T) {
+	t.Run("players go out in order with the time they went", func(t *testing.T) {
+		tournament, clock, out := newTestTournament(TournamentRules{BuyIn: 20}, 4)
+
+		clock.Advance(30 * time.Minute)
+		assertPlace(t, tournament, "Alice", 4)
+		clock.Advance(time.Hour)
+		assertPlace(t, tournament, "Bob", 3)
+		assertPlace(t, tournament, "Cleo", 2)
+
+		result, err := tournament.Finish("Dan")
+		assertNoError(t, err)
+
+		want := []Placing{
+			{Place: 1, Player: "Dan", BuyIns: 1, Paid: 20, Prize: 80},
+			{Place: 2, Player: "Cleo", BuyIns: 1, Paid: 20, OutAt: eightPM.Add(90 * time.Minute)},
+			{Place: 3, Player: "Bob", BuyIns: 1, Paid: 20, OutAt: eightPM.Add(90 * time.Minute)},
+			{Place: 4, Player: "Alice", BuyIns: 1, Paid: 20, OutAt: eightPM.Add(30 * time.Minute)},
+		}
+		if !reflect.DeepEqual(result.Placings, want) {
+			t.Errorf("got placings %+v\nwant %+v", result.Placings, want)
+		}
+		if result.PrizePool != 80 || !result.Started.Equal(eightPM) || !result.Finished.Equal(eightPM.Add(90*time.Minute)) {
+			t.Errorf("got result %+v", result)
+		}
+		if !strings.Contains(out.String(), "Alice is out in 4th place, 3 players left\n") {
+			t.Errorf("expected the bust-out to be announced but got %q", out.String())
+		}
+	})
+
+	t.Run("rebuys and add-ons go into the prize pool", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20, Rebuy: 20, AddOn: 10}, 5)
+
+		assertNoError(t, tournament.Rebuy("Alice"))
+		assertNoError(t, tournament.AddOn("Alice"))
+		assertNoError(t, tournament.AddOn("Bob"))
+
+		if got := tournament.PrizePool(); got != 5*20+20+2*10 {
+			t.Errorf("got prize pool %d want 140", got)
+		}
+	})
+
+	t.Run("a rebuy brings a player back in", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20, Rebuy: 20}, 4)
+
+		assertPlace(t, tournament, "Alice", 4)
+		assertPlace(t, tournament, "Bob", 3)
+		assertNoError(t, tournament.Rebuy("Alice"))
+
+		if tournament.Remaining() != 3 {
+			t.Errorf("got %d players left want 3", tournament.Remaining())
+		}
+		assertPlace(t, tournament, "Alice", 3)
+
+		assertPlace(t, tournament, "Dan", 2)
+		result, _ := tournament.Finish("Cleo")
+		if placing := result.Placings[len(result.Placings)-1]; placing.Player != "Bob" || placing.Place != 4 {
+			t.Errorf("expected Bob to have moved down to 4th but got %+v", placing)
+		}
+		if placing := result.Placings[2]; placing.Player != "Alice" || placing.Rebuys != 1 {
+			t.Errorf("expected Alice 3rd with a rebuy but got %+v", placing)
+		}
+	})
+
+	t.Run("things that cannot happen", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20}, 2)
+
+		if err := tournament.Rebuy("Alice"); err == nil {
+			t.Error("expected rebuys to be refused")
+		}
+		if err := tournament.AddOn("Alice"); err == nil {
+			t.Error("expected add-ons to be refused")
+		}
+
+		assertPlace(t, tournament, "Alice", 2)
+		if _, err := tournament.Eliminate("Alice"); err == nil {
+			t.Error("expected Alice not to go out twice")
+		}
+		if _, err := tournament.Eliminate("Bob"); err == nil {
+			t.Error("expected the last player not to go out")
+		}
+		if _, err := tournament.Finish("Alice"); err == nil {
+			t.Error("expected a player who is out not to win")
+		}
+
+		tournament.Finish("Bob")
+		if _, err := tournament.Finish("Bob"); err != ErrTournamentOver {
+			t.Errorf("got %v want %v", err, ErrTournamentOver)
+		}
+	})
+
+	t.Run("nobody wins while others are still in, so every prize is paid", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20}, 8)
+
+		assertPlace(t, tournament, "A", 8)
+		if _, err := tournament.Finish("H"); err == nil {
+			t.Fatal("expected H not to win with six others still in")
+		}
+
+		for _, player := range []string{"B", "C", "D", "E", "F", "G"} {
+			tournament.Eliminate(player)
+		}
+		result, err := tournament.Finish("H")
+		assertNoError(t, err)
+
+		paid := 0
+		for _, placing := range result.Placings {
+			paid += placing.Prize
+		}
+		if paid != result.PrizePool || len(result.Placings) != 8 {
+			t.Errorf("paid %d of a pool of %d across %d places", paid, result.PrizePool, len(result.Placings))
+		}
+	})
+
+	t.Run("only as many names as players", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20}, 3)
+
+		assertPlace(t, tournament, "Alice", 3)
+		tournament.entry("Bob")
+		tournament.entry("Cleo")
+
+		if _, err := tournament.Eliminate("Dan"); err == nil {
+			t.Error("expected a fourth name to be refused")
+		}
+	})
+
+	t.Run("a name nobody has rebuys, adds on or wins nothing", func(t *testing.T) {
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 20, Rebuy: 20, AddOn: 10}, 3)
+
+		assertPlace(t, tournament, "Alice", 3)
+		tournament.entry("Bob")
+		tournament.entry("Cleo")
+
+		assertHandError(t, tournament.Rebuy("Dan"), "Dan is not one of them")
+		assertHandError(t, tournament.AddOn("Dan"), "Dan is not one of them")
+		_, err := tournament.Finish("Dan")
+		assertHandError(t, err, "Dan is not one of them")
+
+		if got := tournament.PrizePool(); got != 60 {
+			t.Errorf("got prize pool %d want 60", got)
+		}
+		if _, ok := tournament.entries["Dan"]; ok {
+			t.Error("expected no entry for Dan")
+		}
+	})
+
+	t.Run("payouts", func(t *testing.T) {
+		payouts := Payouts{Percentages: []float64{50, 30, 20}}
+		tournament, _, _ := newTestTournament(TournamentRules{BuyIn: 25, Payouts: &payouts}, 9)
+
+		for _, player := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+			tournament.Eliminate(player)
+		}
+		result, err := tournament.Finish("I")
+		assertNoError(t, err)
+
+		prizes := map[string]int{}
+		for _, placing := range result.Placings {
+			prizes[placing.Player] = placing.Prize
+		}
+		if prizes["I"] != 113 || prizes["H"] != 67 || prizes["G"] != 45 || prizes["F"] != 0 {
+			t.Errorf("got prizes %v", prizes)
+		}
+	})
+}
+
+func TestPayouts(t *testing.T) {
+	t.Run("parses percentages and fixed amounts", func(t *testing.T) {
+		percentages, err := ParsePayouts("60%, 40%")
+		assertNoError(t, err)
+		if !reflect.DeepEqual(percentages, Payouts{Percentages: []float64{60, 40}}) {
+			t.Errorf("got %+v", percentages)
+		}
+
+		fixed, err := ParsePayouts("500,300")
+		assertNoError(t, err)
+		if !reflect.DeepEqual(fixed, Payouts{Fixed: []int{500, 300}}) {
+			t.Errorf("got %+v", fixed)
+		}
+	})
+
+	t.Run("rejects payouts that do not add up", func(t *testing.T) {
+		for _, input := range []string{"50%,30%", "50%,30,20%", "lots", "100%,-10%,10%"} {
+			if _, err := ParsePayouts(input); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		}
+	})
+
+	cases := []struct {
+		name      string
+		payouts   Payouts
+		pool      int
+		entrants  int
+		want      []int
+		wantError bool
+	}{
+		{"odd chips go to the winner", Payouts{Percentages: []float64{50, 30, 20}}, 101, 10, []int{51, 30, 20}, false},
+		{"more places than players", Payouts{Percentages: []float64{50, 30, 20}}, 100, 2, []int{70, 30}, false},
+		{"fixed amounts", Payouts{Fixed: []int{60, 30}}, 100, 10, []int{70, 30}, false},
+		{"fixed amounts bigger than the pool", Payouts{Fixed: []int{60, 50}}, 100, 10, nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.payouts.Prizes(c.pool, c.entrants)
+			if (err != nil) != c.wantError {
+				t.Fatalf("got error %v", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"} {
+		if got := Ordinal(n); got != want {
+			t.Errorf("Ordinal(%d) = %q want %q", n, got, want)
+		}
+	}
+}
+
+func TestTournamentCommands(t *testing.T) {
+	newGame := func(store PlayerStore) *TexasHoldem {
+		game := NewTexasHoldem(store, &spyAlerter{}, NewManualClock(eightPM))
+		game.PlayTournament(TournamentRules{BuyIn: 10, Rebuy: 10})
+		game.Start(3, io.Discard)
+		return game
+	}
+
+	t.Run("bust-outs and rebuys are commands", func(t *testing.T) {
+		game := newGame(&StubPlayerStore{})
+
+		for _, input := range []string{"Alice out", "Alice rebuys", "Mary Jane out"} {
+			handled, err := gameCommand(game, input)
+			if !handled || err != nil {
+				t.Fatalf("%q: got handled %v, error %v", input, handled, err)
+			}
+		}
+		if game.Tournament().Remaining() != 2 {
+			t.Errorf("got %d players left want 2", game.Tournament().Remaining())
+		}
+	})
+
+	t.Run("finishing saves the whole result", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game := newGame(store)
+
+		gameCommand(game, "Alice out")
+		gameCommand(game, "Bob out")
+		game.Finish("Cleo")
+
+		if len(store.tournaments) != 1 || store.tournaments[0].Winner() != "Cleo" || len(store.tournaments[0].Placings) != 3 {
+			t.Fatalf("got tournaments %+v", store.tournaments)
+		}
+		AssertPlayerWin(t, store, "Cleo")
+	})
+
+	t.Run("games that are not tournaments leave the commands alone", func(t *testing.T) {
+		game := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, NewManualClock(eightPM))
+		game.Start(3, io.Discard)
+
+		if handled, _ := gameCommand(game, "Alice out"); handled {
+			t.Error("expected the command not to be handled")
+		}
+	})
+
+	t.Run("players who lose their chips at a dealt table are knocked out", func(t *testing.T) {
+		game := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, NewManualClock(eightPM))
+		game.PlayTournament(TournamentRules{BuyIn: 10})
+		game.DealCards(1000, 2)
+		game.Start(2, io.Discard)
+
+		gameCommand(game, "all-in")
+		gameCommand(game, "call")
+
+		if game.Tournament().Remaining() != 1 {
+			t.Errorf("got %d players left want 1", game.Tournament().Remaining())
+		}
+	})
+}
+
+func assertPlace(t testing.TB, tournament *Tournament, player string, want int) {
+	t.Helper()
+
+	got, err := tournament.Eliminate(player)
+	if err != nil {
+		t.Fatalf("could not knock %s out: %v", player, err)
+	}
+	if got != want {
+		t.Errorf("%s went out in %s, want %s", player, Ordinal(got), Ordinal(want))
+	}
+}
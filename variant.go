@@ -0,0 +1,359 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BettingLimit caps how much a player can bet or raise.
+type BettingLimit int
+
+const (
+	NoLimit BettingLimit = iota
+	PotLimit
+)
+
+// Variant is a form of poker. Stud levels use the small blind as the bring-in.
+type Variant struct {
+	Name      string
+	Title     string
+	MaxSeats  int
+	HoleCards int
+	Stud      bool
+	Limit     BettingLimit
+
+	// Deck returns the unshuffled cards the variant is played with.
+	Deck func() *Deck
+
+	// High values a player's best hand from their cards and the board.
+	High func(hole, board []Card) HandValue
+
+	// Low values the best qualifying low, and is nil when the high hand scoops.
+	Low func(hole, board []Card) (LowHand, bool)
+
+	// Blinds is the structure played when none is chosen.
+	Blinds func(players int) BlindStructure
+}
+
+func (v Variant) String() string {
+	return v.Title
+}
+
+// Validate checks a variant has everything needed to deal it.
+func (v Variant) Validate() error {
+	switch {
+	case v.Name == "" || strings.ContainsAny(v.Name, " \t"):
+		return fmt.Errorf("a variant needs a one word name, got %q", v.Name)
+	case v.MaxSeats < MinSeats:
+		return fmt.Errorf("%s must seat at least %d players", v.Name, MinSeats)
+	case !v.Stud && v.HoleCards < 1:
+		return fmt.Errorf("%s must deal some hole cards", v.Name)
+	case v.Deck == nil || v.High == nil || v.Blinds == nil:
+		return fmt.Errorf("%s needs a deck, a way to rank hands and a blind structure", v.Name)
+	}
+	return nil
+}
+
+// VariantGame is a Game that can deal variants other than Hold'em.
+type VariantGame interface {
+	Game
+	PlayVariant(Variant)
+}
+
+var (
+	variantsMu sync.RWMutex
+	variants   = map[string]Variant{}
+)
+
+// RegisterVariant adds a variant under a name that is not case sensitive.
+func RegisterVariant(variant Variant) error {
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+
+	variantsMu.Lock()
+	defer variantsMu.Unlock()
+
+	key := strings.ToLower(variant.Name)
+	if _, ok := variants[key]; ok {
+		return fmt.Errorf("there is already a variant called %s", variant.Name)
+	}
+	variants[key] = variant
+	return nil
+}
+
+// LookupVariant finds a registered variant by name.
+func LookupVariant(name string) (Variant, bool) {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+
+	variant, ok := variants[strings.ToLower(name)]
+	return variant, ok
+}
+
+// VariantNames lists the registered variants in alphabetical order.
+func VariantNames() []string {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+
+	names := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		names = append(names, variant.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	TexasHoldemVariant = Variant{
+		Name:      "holdem",
+		Title:     "Texas Hold'em",
+		MaxSeats:  MaxSeats,
+		HoleCards: 2,
+		Deck:      NewDeck,
+		High:      bestOfAll,
+		Blinds:    DefaultBlindStructure,
+	}
+
+	OmahaVariant = Variant{
+		Name:      "omaha",
+		Title:     "Pot Limit Omaha",
+		MaxSeats:  MaxSeats,
+		HoleCards: 4,
+		Limit:     PotLimit,
+		Deck:      NewDeck,
+		High:      bestOmaha,
+		Blinds:    DefaultBlindStructure,
+	}
+
+	OmahaHiLoVariant = Variant{
+		Name:      "omaha-hi-lo",
+		Title:     "Pot Limit Omaha Hi-Lo",
+		MaxSeats:  MaxSeats,
+		HoleCards: 4,
+		Limit:     PotLimit,
+		Deck:      NewDeck,
+		High:      bestOmaha,
+		Low:       bestOmahaLow,
+		Blinds:    DefaultBlindStructure,
+	}
+
+	ShortDeckVariant = Variant{
+		Name:      "short-deck",
+		Title:     "Short Deck Hold'em",
+		MaxSeats:  MaxSeats,
+		HoleCards: 2,
+		Deck:      NewShortDeck,
+		High: func(hole, board []Card) HandValue {
+			return evaluateShortDeck(append(append([]Card{}, hole...), board...))
+		},
+		Blinds: shortDeckBlinds,
+	}
+
+	SevenCardStudVariant = Variant{
+		Name:     "stud",
+		Title:    "Seven Card Stud",
+		MaxSeats: 8,
+		Stud:     true,
+		Deck:     NewDeck,
+		High:     bestOfAll,
+		Blinds:   studAntes,
+	}
+)
+
+func init() {
+	for _, variant := range []Variant{TexasHoldemVariant, OmahaVariant, OmahaHiLoVariant, ShortDeckVariant, SevenCardStudVariant} {
+		if err := RegisterVariant(variant); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// bestOfAll plays the best five of every card, as in Hold'em and stud.
+func bestOfAll(hole, board []Card) HandValue {
+	cards := make([]Card, 0, len(hole)+len(board))
+	return evaluate(append(append(cards, hole...), board...))
+}
+
+// omahaHands calls play with each hand of two hole cards and three from the board.
+func omahaHands(hole, board []Card, play func([]Card)) {
+	var five [5]Card
+	for a := 0; a < len(hole); a++ {
+		for b := a + 1; b < len(hole); b++ {
+			five[0], five[1] = hole[a], hole[b]
+			for c := 0; c < len(board); c++ {
+				for d := c + 1; d < len(board); d++ {
+					for e := d + 1; e < len(board); e++ {
+						five[2], five[3], five[4] = board[c], board[d], board[e]
+						play(five[:])
+					}
+				}
+			}
+		}
+	}
+}
+
+func bestOmaha(hole, board []Card) HandValue {
+	best := HandValue(0)
+	omahaHands(hole, board, func(five []Card) {
+		best = max(best, evaluate(five))
+	})
+	return best
+}
+
+func bestOmahaLow(hole, board []Card) (LowHand, bool) {
+	best, found := LowHand(0), false
+	omahaHands(hole, board, func(five []Card) {
+		if low, ok := eightOrBetter(five); ok && (!found || low < best) {
+			best, found = low, true
+		}
+	})
+	return best, found
+}
+
+// LowHand is an ace to five low; smaller is better.
+type LowHand uint32
+
+// eightOrBetter values five cards as a low, if they qualify.
+func eightOrBetter(five []Card) (LowHand, bool) {
+	var seen uint16
+	for _, card := range five {
+		rank := lowRank(card.Rank)
+		if rank > 8 || seen&(1<<rank) != 0 {
+			return 0, false
+		}
+		seen |= 1 << rank
+	}
+
+	var low LowHand
+	for rank := 8; rank >= 1; rank-- {
+		if seen&(1<<rank) != 0 {
+			low = low<<rankBits | LowHand(rank)
+		}
+	}
+	return low, true
+}
+
+func lowRank(rank Rank) int {
+	if rank == Ace {
+		return 1
+	}
+	return int(rank)
+}
+
+// String writes the low the way players say it, e.g. "8-6-4-2-A low".
+func (l LowHand) String() string {
+	var names []string
+	for i := 4; i >= 0; i-- {
+		rank := Rank(l>>(i*rankBits)) & 0xf
+		if rank == 1 {
+			names = append(names, Ace.String())
+			continue
+		}
+		names = append(names, rank.String())
+	}
+	return strings.Join(names, "-") + " low"
+}
+
+// NewShortDeck returns the 36 cards from six to ace.
+func NewShortDeck() *Deck {
+	deck := &Deck{}
+	for _, card := range NewDeck().cards {
+		if card.Rank >= Six {
+			deck.cards = append(deck.cards, card)
+		}
+	}
+	return deck
+}
+
+const shortDeckWheel = 1<<Ace | 1<<Six | 1<<Seven | 1<<Eight | 1<<Nine
+
+// shortDeckOrder ranks a flush above a full house.
+var shortDeckOrder = [...]HandValue{
+	HighCard: 0, OnePair: 1, TwoPair: 2, ThreeOfAKind: 3, Straight: 4,
+	FullHouse: 5, Flush: 6, FourOfAKind: 7, StraightFlush: 8,
+}
+
+// evaluateShortDeck plays A-6-7-8-9 as the lowest straight and a flush above a full house.
+func evaluateShortDeck(cards []Card) HandValue {
+	value := evaluateWithWheel(cards, shortDeckWheel, Nine)
+	return shortDeckOrder[value.Category()]<<(5*rankBits+categoryBits) | value
+}
+
+// shortDeckBlinds adds an ante the size of the big blind.
+func shortDeckBlinds(players int) BlindStructure {
+	structure := DefaultBlindStructure(players)
+	structure.Name = "short deck"
+	for i := range structure.Levels {
+		structure.Levels[i].Ante = structure.Levels[i].BigBlind
+	}
+	return structure
+}
+
+// studAntes makes the default levels stud antes, bring-ins and smallest bets.
+func studAntes(players int) BlindStructure {
+	structure := DefaultBlindStructure(players)
+	structure.Name = "stud"
+	for i, level := range structure.Levels {
+		structure.Levels[i].Ante = max(level.BigBlind/5, 1)
+		structure.Levels[i].SmallBlind = max(level.SmallBlind/2, 1)
+	}
+	return structure
+}
+
+// showingValue ranks a stud player's upcards to decide who acts first.
+func showingValue(cards []Card) HandValue {
+	var counts [Ace + 1]int
+	for _, card := range cards {
+		counts[card.Rank]++
+	}
+
+	var groups [5][]Rank
+	for rank := Ace; rank >= Two; rank-- {
+		if counts[rank] > 0 {
+			groups[counts[rank]] = append(groups[counts[rank]], rank)
+		}
+	}
+
+	category := HighCard
+	switch {
+	case len(groups[4]) > 0:
+		category = FourOfAKind
+	case len(groups[3]) > 0:
+		category = ThreeOfAKind
+	case len(groups[2]) > 1:
+		category = TwoPair
+	case len(groups[2]) > 0:
+		category = OnePair
+	}
+
+	var ranks []Rank
+	for size := 4; size >= 1; size-- {
+		ranks = append(ranks, groups[size]...)
+	}
+	return newHandValue(category, ranks...)
+}
+
+var errNoBringIn = errors.New("nobody has a card showing to bring it in")
+
+// bringIn is the seat with the lowest card showing, suits breaking ties.
+func bringIn(seats []*Seat) (int, error) {
+	lowest := -1
+	var low Card
+	for i, seat := range seats {
+		if len(seat.Upcards) == 0 {
+			continue
+		}
+		card := seat.Upcards[0]
+		if lowest < 0 || card.Rank < low.Rank || card.Rank == low.Rank && card.Suit < low.Suit {
+			lowest, low = i, card
+		}
+	}
+	if lowest < 0 {
+		return 0, errNoBringIn
+	}
+	return lowest, nil
+}
@@ -0,0 +1,242 @@
+package poker
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVariants(t *testing.T) {
+	t.Run("the built in variants can be found by name", func(t *testing.T) {
+		for _, name := range []string{"holdem", "Omaha", "omaha-hi-lo", "short-deck", "STUD"} {
+			if _, ok := LookupVariant(name); !ok {
+				t.Errorf("expected to find %q", name)
+			}
+		}
+		if _, ok := LookupVariant("razz"); ok {
+			t.Error("expected razz not to be a variant")
+		}
+	})
+
+	t.Run("variants cannot be registered twice or half finished", func(t *testing.T) {
+		if err := RegisterVariant(TexasHoldemVariant); err == nil {
+			t.Error("expected holdem to be refused a second time")
+		}
+		if err := RegisterVariant(Variant{Name: "razz", MaxSeats: 8, Stud: true}); err == nil {
+			t.Error("expected a variant with no way to rank hands to be refused")
+		}
+	})
+
+	t.Run("each variant brings its own blinds", func(t *testing.T) {
+		for _, name := range VariantNames() {
+			variant, _ := LookupVariant(name)
+			if err := variant.Blinds(6).Validate(); err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+		}
+		if SevenCardStudVariant.Blinds(6).Levels[0].Ante == 0 {
+			t.Error("expected stud to be played with antes")
+		}
+	})
+}
+
+func TestOmaha(t *testing.T) {
+	t.Run("plays exactly two hole cards", func(t *testing.T) {
+		hole, _ := ParseCards("Ah 3c 4d 7s")
+		board, _ := ParseCards("2h 5h 9h Jh Kc")
+
+		if got := bestOfAll(hole, board).Category(); got != Flush {
+			t.Fatalf("expected Hold'em rules to make a flush, got %v", got)
+		}
+		if got := bestOmaha(hole, board); got.Category() != HighCard {
+			t.Errorf("got %v want ace high with only one heart in the hand", got)
+		}
+	})
+
+	t.Run("pot limit caps raises at the size of the pot", func(t *testing.T) {
+		hand, _ := newVariantHand(t, OmahaVariant, NewDeck(), blinds50100, 1000, 1000, 1000)
+
+		assertHandError(t, hand.Act(0, Move{Action: Raise, Amount: 400}), "the pot limit is 350")
+
+		play(t, hand, "all-in")
+		assertStacks(t, hand, 650, 950, 900)
+	})
+
+	t.Run("the low hand takes half the pot", func(t *testing.T) {
+		deck := riggedDeck(t, []string{"Ks Kd Qc Jh", "Ah 2d Th Tc", "9s 9c 8d 8h"}, "Kc 3s 5d 7h Qd")
+		hand, out := newVariantHand(t, OmahaHiLoVariant, deck, blinds50100, 1000, 1000, 1000)
+
+		play(t, hand, "call", "call", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check")
+
+		assertStacks(t, hand, 1050, 1050, 900)
+		for _, want := range []string{"A wins 150 with three of a kind, Ks\n", "B wins 150 with 7-5-3-2-A low\n"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in %q", want, out.String())
+			}
+		}
+	})
+
+	t.Run("the best hand scoops when nobody has a low", func(t *testing.T) {
+		deck := riggedDeck(t, []string{"Ks Kd Qc Jh", "Ah 2d Th Tc", "9s 9c 8d 8h"}, "Kc 3s Jd 7h Qd")
+		hand, _ := newVariantHand(t, OmahaHiLoVariant, deck, blinds50100, 1000, 1000, 1000)
+
+		play(t, hand, "call", "call", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check")
+
+		assertStacks(t, hand, 900, 1200, 900)
+	})
+}
+
+func TestLowHands(t *testing.T) {
+	cases := []struct {
+		cards string
+		want  string
+		ok    bool
+	}{
+		{"Ah 2c 3d 4s 5h", "5-4-3-2-A low", true},
+		{"8h 6c 4d 2s Ah", "8-6-4-2-A low", true},
+		{"9h 6c 4d 2s Ah", "", false},
+		{"2h 2c 4d 5s Ah", "", false},
+	}
+
+	for _, c := range cases {
+		cards, _ := ParseCards(c.cards)
+		low, ok := eightOrBetter(cards)
+		if ok != c.ok || ok && low.String() != c.want {
+			t.Errorf("%s: got %v, %v want %q, %v", c.cards, low, ok, c.want, c.ok)
+		}
+	}
+
+	wheel, _ := ParseCards("Ah 2c 3d 4s 5h")
+	eight, _ := ParseCards("8h 2c 3d 4s 5h")
+	best, _ := eightOrBetter(wheel)
+	worse, _ := eightOrBetter(eight)
+	if best >= worse {
+		t.Errorf("expected %v to beat %v", best, worse)
+	}
+}
+
+func TestShortDeck(t *testing.T) {
+	if got := NewShortDeck().Len(); got != 36 {
+		t.Errorf("got %d cards want 36", got)
+	}
+
+	rank := func(cards string) HandValue {
+		hole, _ := ParseCards(cards)
+		return ShortDeckVariant.High(hole, nil)
+	}
+
+	if flush, fullHouse := rank("As Js 9s 7s 6s"), rank("Kh Kd Kc 7h 7d"); flush <= fullHouse {
+		t.Errorf("expected %v to beat %v", flush, fullHouse)
+	}
+	lowest := rank("Ah 6d 7c 8s 9h")
+	if lowest.Category() != Straight || lowest <= rank("Ah Kd 8c 7s 6h") || lowest >= rank("Td 6d 7c 8s 9h") {
+		t.Errorf("expected A-6-7-8-9 to be the lowest straight, got %v", lowest)
+	}
+}
+
+// riggedStudDeck stacks a deck so the seats, button first, get their seven
+// cards in the order written.
+func riggedStudDeck(t testing.TB, hands ...string) *Deck {
+	t.Helper()
+
+	holes := make([][]Card, len(hands))
+	for i, hand := range hands {
+		holes[i], _ = ParseCards(hand)
+	}
+
+	var cards []Card
+	for round := 0; round < 7; round++ {
+		for i := range holes {
+			cards = append(cards, holes[(i+1)%len(holes)][round])
+		}
+	}
+	return &Deck{cards: cards}
+}
+
+func TestSevenCardStud(t *testing.T) {
+	studLevel := BlindLevel{SmallBlind: 25, BigBlind: 100, Ante: 10, Duration: 10 * time.Minute}
+
+	t.Run("the lowest card brings it in and the best cards showing open", func(t *testing.T) {
+		deck := riggedStudDeck(t, "2c 3c Kd Ks 4h 5h 6h", "7c 8c 3d 9d Td Jd Qd", "2d 4d 9h 2h 6s 7s 8s")
+		hand, out := newVariantHand(t, SevenCardStudVariant, deck, studLevel, 1000, 1000, 1000)
+
+		assertStacks(t, hand, 990, 965, 990)
+		assertToAct(t, hand, 2)
+		if !strings.Contains(out.String(), "Dealt to A: 2c 3c, showing Kd\n") {
+			t.Errorf("expected the cards to be dealt, got %q", out.String())
+		}
+
+		play(t, hand, "raise 100", "call", "call")
+		assertStreet(t, hand, FourthStreet)
+		assertToAct(t, hand, 0)
+
+		play(t, hand, "check", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check", "check")
+		assertStreet(t, hand, Showdown)
+		if !hand.Finished() {
+			t.Fatal("expected the showdown to settle the hand")
+		}
+		assertChipsKept(t, hand, []int{1000, 1000, 1000})
+		if len(hand.Seats[0].Cards) != 7 || len(hand.Seats[0].Upcards) != 4 {
+			t.Errorf("got cards %v showing %v", hand.Seats[0].Cards, hand.Seats[0].Upcards)
+		}
+	})
+
+	t.Run("eight players share a card when the deck runs out", func(t *testing.T) {
+		table, _ := NewTable(strings.Split("ABCDEFGH", ""), 1000, 1, io.Discard)
+		table.Variant = SevenCardStudVariant
+
+		hand, err := table.DealHand(studLevel)
+		assertNoError(t, err)
+		for hand.ToAct() >= 0 {
+			move := Move{Action: Check}
+			if hand.ToCall() > 0 {
+				move = Move{Action: Call}
+			}
+			assertNoError(t, hand.Act(hand.ToAct(), move))
+		}
+
+		assertBoard(t, hand, 1)
+		assertChipsKept(t, hand, []int{1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000})
+	})
+
+	t.Run("seven cards and the shared one make the best full house", func(t *testing.T) {
+		cases := []struct {
+			hole, board string
+			want        HandValue
+		}{
+			{"Ah Kh 9h 5h 2h Ad As", "Kd", newHandValue(FullHouse, Ace, King)},
+			{"7c 7d 7h 5c 5d 5h Kc", "Kd", newHandValue(FullHouse, Seven, King)},
+		}
+		for _, c := range cases {
+			hole, _ := ParseCards(c.hole)
+			board, _ := ParseCards(c.board)
+			if got := SevenCardStudVariant.High(hole, board); got != c.want {
+				t.Errorf("%s with %s: got %v want %v", c.hole, c.board, got, c.want)
+			}
+		}
+	})
+
+	t.Run("no more than eight are dealt in", func(t *testing.T) {
+		table, _ := NewTable(strings.Split("ABCDEFGHI", ""), 1000, 1, io.Discard)
+		table.Variant = SevenCardStudVariant
+
+		if _, err := table.DealHand(studLevel); err == nil {
+			t.Error("expected nine players to be too many")
+		}
+	})
+}
+
+func TestTexasHoldem_PlayVariant(t *testing.T) {
+	game := NewTexasHoldem(&StubPlayerStore{}, &spyAlerter{}, NewManualClock(eightPM))
+	game.PlayVariant(SevenCardStudVariant)
+	game.DealCards(1000, 1)
+
+	out := &bytes.Buffer{}
+	game.Start(3, out)
+
+	if !strings.Contains(out.String(), "Hand #1 of Seven Card Stud: antes 20, bring-in 25\n") {
+		t.Errorf("expected a stud hand dealt with stud antes, got %q", out.String())
+	}
+}